@@ -0,0 +1,46 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"justTest/internal/repo"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+// пересчет остатков счетов (текущих и на конец дня) по журналу транзакций:
+// go run ./cmd/balances              - все аккаунты
+// go run ./cmd/balances -account 42  - один аккаунт
+func main() {
+	var accountID = flag.Int64("account", 0, "account id to rebuild, 0 - all accounts")
+	flag.Parse()
+	if *accountID < 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment")
+	}
+
+	db, err := sql.Open("postgres", os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatal("Cannot connect to database:", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		log.Fatal("Error connecting to database:", err)
+	}
+
+	if err := repo.NewBalanceRepository(db).RebuildBalances(*accountID); err != nil {
+		log.Fatal("Balance rebuild failed:", err)
+	}
+	if *accountID == 0 {
+		log.Println("Rebuilt balances of all accounts")
+		return
+	}
+	log.Printf("Rebuilt balances of account %d", *accountID)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"justTest/internal/repo"
+	"justTest/internal/services"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+// импорт курсов валют из локального файла:
+// go run ./cmd/rates -source ecb -file eurofxref-hist.xml
+// go run ./cmd/rates -source nbk -file get_rates.xml
+func main() {
+	var source = flag.String("source", "", "rate file format: ecb (eurofxref XML) or nbk (National Bank of Kazakhstan XML)")
+	var path = flag.String("file", "", "path to the rate file")
+	flag.Parse()
+	if *source == "" || *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment")
+	}
+
+	db, err := sql.Open("postgres", os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatal("Cannot connect to database:", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		log.Fatal("Error connecting to database:", err)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatal("Cannot open rate file:", err)
+	}
+	defer file.Close()
+
+	rateService := services.NewRateService(repo.NewCurrencyRateRepository(db))
+	count, err := rateService.ImportRates(*source, file)
+	if err != nil {
+		log.Fatal("Rate import failed:", err)
+	}
+	log.Printf("Imported %d %s rates from %s", count, *source, *path)
+}
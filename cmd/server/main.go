@@ -61,6 +61,13 @@ func main() {
 	budgetRepo := repo.NewBudgetRepository(db)
 	notificationRepo := repo.NewNotificationRepository(db)
 	settingRepo := repo.NewUserNotificationSettingsRepository(db)
+	recurringRepo := repo.NewRecurringTransactionRepository(db)
+	currencyRateRepo := repo.NewCurrencyRateRepository(db)
+	balanceRepo := repo.NewBalanceRepository(db)
+	creditTermsRepo := repo.NewCreditTermsRepository(db)
+	goalRepo := repo.NewGoalRepository(db)
+	installmentRepo := repo.NewInstallmentRepository(db)
+	debtRepo := repo.NewDebtRepository(db)
 
 	// RabbitMQ configuration
 	rabbitmqURL := os.Getenv("RABBITMQ_URL")
@@ -77,12 +84,20 @@ func main() {
 	if publisher != nil {
 		defer publisher.Close()
 	}
-	accountService := services.NewAccountService(accountRepo, bankAccountRepo, transactionRepo, authClient)
+	rateService := services.NewRateService(currencyRateRepo)
+	budgetService := services.NewBudgetService(budgetRepo, transactionRepo, accountRepo, categoryRepo, installmentRepo, rateService, publisher)
+	accountService := services.NewAccountService(accountRepo, bankAccountRepo, transactionRepo, rateService, budgetService, authClient)
 	bankAccService := services.NewBankAccService(bankAccountRepo, accountRepo)
-	transactionService := services.NewTransactionService(transactionRepo, bankAccountRepo, categoryRepo, accountRepo)
+	transactionService := services.NewTransactionService(transactionRepo, bankAccountRepo, categoryRepo, accountRepo, balanceRepo, rateService)
 	categoryService := services.NewCategoryService(accountRepo, categoryRepo, authClient)
-	budgetService := services.NewBudgetService(budgetRepo, transactionRepo, accountRepo, categoryRepo, publisher)
 	notificationService := services.NewNotificationService(notificationRepo, settingRepo, publisher)
+	creditService := services.NewCreditService(creditTermsRepo, bankAccountRepo, accountRepo, transactionRepo, balanceRepo, notificationService)
+	debtService := services.NewDebtService(debtRepo, bankAccountRepo, accountRepo, notificationService)
+	goalService := services.NewGoalService(goalRepo, bankAccountRepo, accountRepo, transactionRepo, balanceRepo, notificationService)
+	analyticsService := services.NewAnalyticsService(transactionRepo, accountRepo, bankAccountRepo, rateService)
+	recurringService := services.NewRecurringService(recurringRepo, bankAccountRepo, categoryRepo, accountRepo, transactionService, publisher)
+	installmentService := services.NewInstallmentService(installmentRepo, bankAccountRepo, categoryRepo, accountRepo, rateService, publisher)
+	importService := services.NewImportService(transactionRepo, categoryRepo, accountRepo, bankAccService)
 
 	var consumer *events.Consumer
 	if publisher != nil {
@@ -98,10 +113,18 @@ func main() {
 	}
 	accountHandler := handlers.NewAccountHandler(accountService)
 	bankAccountHandler := handlers.NewBankAccountHandler(bankAccService)
-	transactionHandler := handlers.NewTransactionHandler(transactionService, publisher)
+	transactionHandler := handlers.NewTransactionHandler(transactionService, creditService, publisher)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 	budgetHandler := handlers.NewBudgetHandler(budgetService)
 	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	recurringHandler := handlers.NewRecurringHandler(recurringService)
+	importHandler := handlers.NewImportHandler(importService)
+	rateHandler := handlers.NewRateHandler(rateService)
+	creditHandler := handlers.NewCreditHandler(creditService)
+	goalHandler := handlers.NewGoalHandler(goalService)
+	installmentHandler := handlers.NewInstallmentHandler(installmentService)
+	debtHandler := handlers.NewDebtHandler(debtService)
 
 	router := gin.Default()
 
@@ -114,6 +137,14 @@ func main() {
 		categoryHandler,
 		budgetHandler,
 		notificationHandler,
+		analyticsHandler,
+		recurringHandler,
+		importHandler,
+		rateHandler,
+		creditHandler,
+		goalHandler,
+		installmentHandler,
+		debtHandler,
 	)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -125,6 +156,18 @@ func main() {
 	} else {
 		log.Println("RabbitMQ consumer disabled - events will not be processed")
 	}
+	schedulerInterval := time.Minute
+	if value := os.Getenv("RECURRING_SCHEDULER_INTERVAL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			schedulerInterval = parsed
+		}
+	}
+	startRecurringScheduler(recurringService, schedulerInterval)
+	startInstallmentScheduler(installmentService, schedulerInterval)
+	startCreditReminderScheduler(creditService, time.Hour)
+	startGoalMilestoneScheduler(goalService, time.Hour)
+	startDebtReminderScheduler(debtService, time.Hour)
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"justTest/internal/services"
+	"log"
+	"time"
+)
+
+// startRecurringScheduler сразу догоняет пропущенные срабатывания, затем проверяет правила каждые interval
+func startRecurringScheduler(recurringService *services.RecurringService, interval time.Duration) {
+	go func() {
+		log.Printf("Starting recurring transactions scheduler (every %s)", interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := recurringService.ProcessDue(time.Now()); err != nil {
+				log.Printf("[Scheduler] Error processing recurring transactions: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// startCreditReminderScheduler проверяет сроки платежей по кредитным счетам каждые interval,
+// напоминание по одному сроку отправляется один раз
+func startCreditReminderScheduler(creditService *services.CreditService, interval time.Duration) {
+	go func() {
+		log.Printf("Starting credit payment reminder scheduler (every %s)", interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := creditService.SendDueReminders(time.Now()); err != nil {
+				log.Printf("[Scheduler] Error sending credit payment reminders: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// startGoalMilestoneScheduler проверяет рубежи целей каждые interval:
+// прогресс целей со сберегательным счетом меняется вместе с транзакциями счета
+func startGoalMilestoneScheduler(goalService *services.GoalService, interval time.Duration) {
+	go func() {
+		log.Printf("Starting goal milestone scheduler (every %s)", interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := goalService.CheckMilestones(); err != nil {
+				log.Printf("[Scheduler] Error checking goal milestones: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// startInstallmentScheduler проводит наступившие платежи по рассрочкам каждые interval,
+// после простоя сервера пропущенные платежи проводятся при первом запуске
+func startInstallmentScheduler(installmentService *services.InstallmentService, interval time.Duration) {
+	go func() {
+		log.Printf("Starting installment payments scheduler (every %s)", interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := installmentService.ProcessDue(time.Now()); err != nil {
+				log.Printf("[Scheduler] Error processing installment payments: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// startDebtReminderScheduler проверяет сроки возврата долгов каждые interval,
+// напоминание по одному сроку отправляется один раз
+func startDebtReminderScheduler(debtService *services.DebtService, interval time.Duration) {
+	go func() {
+		log.Printf("Starting debt reminder scheduler (every %s)", interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := debtService.SendDueReminders(time.Now()); err != nil {
+				log.Printf("[Scheduler] Error sending debt reminders: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -69,13 +69,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new financial account for the authenticated user",
+                "description": "Update the display name, the timezone (IANA name, e.g. Asia/Almaty), the first day of the week (0 - Sunday, 1 - Monday) and the base currency. Month and week boundaries in budgets and reports are calculated in this timezone; net worth, the monthly report and budgets are calculated in the base currency",
                 "consumes": [
                     "application/json"
                 ],
@@ -85,21 +85,21 @@ const docTemplate = `{
                 "tags": [
                     "accounts"
                 ],
-                "summary": "Create a new account",
+                "summary": "Update account",
                 "parameters": [
                     {
-                        "description": "Account creation request",
+                        "description": "Account profile",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateAccountRequest"
+                            "$ref": "#/definitions/models.UpdateAccountRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.AccountResponse"
                         }
@@ -117,49 +117,42 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     }
                 }
-            }
-        },
-        "/account/{account_id}/transactions": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all transactions for a specific bank account",
+                "description": "Create a new financial account for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "accounts"
                 ],
-                "summary": "Get transaction history by bank account",
+                "summary": "Create a new account",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Bank Account ID",
-                        "name": "account_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Account creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateAccountRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.TransactionResponse"
-                            }
+                            "$ref": "#/definitions/models.AccountResponse"
                         }
                     },
                     "400": {
@@ -186,80 +179,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/bankAccounts": {
+        "/account/net-worth": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all bank accounts for the authenticated user",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "bank-accounts"
-                ],
-                "summary": "Get all bank accounts",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.BankAccount"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Create a new bank account for the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get balances of all active bank accounts at the end of a day converted to the account base currency, with per-currency subtotals and the rates used",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bank-accounts"
+                    "accounts"
                 ],
-                "summary": "Create a new bank account",
+                "summary": "Get net worth",
                 "parameters": [
                     {
-                        "description": "Bank account creation request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateBankAccountRequest"
-                        }
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD) in the account timezone, today by default",
+                        "name": "date",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.BankAccount"
+                            "$ref": "#/definitions/models.NetWorth"
                         }
                     },
                     "400": {
@@ -286,42 +233,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/bankAccounts/{bank_account_id}": {
+        "/account/summary": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a specific bank account by ID for the authenticated user",
+                "description": "Get balances per currency and per active bank account, income and expenses for the current month per currency and the current month budget statuses in one request",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bank-accounts"
-                ],
-                "summary": "Get a specific bank account",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Bank Account ID",
-                        "name": "bank_account_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "accounts"
                 ],
+                "summary": "Get account summary",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.BankAccount"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.AccountSummary"
                         }
                     },
                     "401": {
@@ -332,7 +263,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Bank account not found",
+                        "description": "Account not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -346,36 +277,40 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/account/{account_id}/transactions": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a bank account by ID (will also delete all related transactions)",
+                "description": "Get all transactions for a specific bank account",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bank-accounts"
+                    "transactions"
                 ],
-                "summary": "Delete a bank account",
+                "summary": "Get transaction history by bank account",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Bank Account ID",
-                        "name": "bank_account_id",
+                        "name": "account_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success message",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TransactionResponse"
+                            }
                         }
                     },
                     "400": {
@@ -392,13 +327,6 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -409,36 +337,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/bankAccounts/{bank_account_id}/activate": {
-            "put": {
+        "/analytics/cash-flow": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Activate a previously deactivated bank account by ID",
+                "description": "Get projected month-end balances: current balances of active bank accounts plus planned transactions, including installment payments, converted to the base currency at today's rate",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bank-accounts"
+                    "analytics"
                 ],
-                "summary": "Activate a bank account",
+                "summary": "Get cash-flow forecast",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Bank Account ID",
-                        "name": "bank_account_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Months to forecast, starting with the current one (1-24, default 6)",
+                        "name": "months",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success message",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.CashFlowForecast"
                         }
                     },
                     "400": {
@@ -454,54 +380,49 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
-                    },
-                    "403": {
-                        "description": "Access denied",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     }
                 }
             }
         },
-        "/bankAccounts/{bank_account_id}/deactivate": {
-            "put": {
+        "/analytics/categories": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deactivate a bank account by ID (keeps transactions but hides account)",
+                "description": "Get expense totals and percentages per category, one set per currency",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bank-accounts"
+                    "analytics"
                 ],
-                "summary": "Deactivate a bank account",
+                "summary": "Get spending by category for a date range",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Bank Account ID",
-                        "name": "bank_account_id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success message",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CategorySpending"
+                            }
                         }
                     },
                     "400": {
@@ -518,13 +439,6 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -535,36 +449,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/bank_accounts/{account_id}/balance": {
+        "/analytics/income-expense": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the current balance of a specific bank account",
+                "description": "Get income, expense, net income and savings rate per currency, optionally as a day/week/month time series",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "analytics"
                 ],
-                "summary": "Get bank account balance",
+                "summary": "Get income vs expenses for a date range",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Bank Account ID",
-                        "name": "account_id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "Time series grouping",
+                        "name": "group_by",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Balance information",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.IncomeExpenseReport"
+                            }
                         }
                     },
                     "400": {
@@ -591,21 +525,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/budgets": {
+        "/analytics/monthly": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all budgets with status for a specific month",
+                "description": "Get total income, expense, spending by category and top expenses for a month (transfers excluded)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "analytics"
                 ],
-                "summary": "Get all budgets for a month",
+                "summary": "Get monthly report",
                 "parameters": [
                     {
                         "type": "integer",
@@ -622,16 +556,20 @@ const docTemplate = `{
                         "name": "month",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of top expenses",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.BudgetWithStatus"
-                            }
+                            "$ref": "#/definitions/models.MonthlyReport"
                         }
                     },
                     "400": {
@@ -656,14 +594,56 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "security": [
+            }
+        },
+        "/bankAccounts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all bank accounts for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank-accounts"
+                ],
+                "summary": "Get all bank accounts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BankAccount"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new monthly budget for a category",
+                "description": "Create a new bank account for the authenticated user. An optional opening balance of an existing account is stored as an opening_balance transaction that is excluded from income, expenses and budgets",
                 "consumes": [
                     "application/json"
                 ],
@@ -671,17 +651,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "bank-accounts"
                 ],
-                "summary": "Create a new budget",
+                "summary": "Create a new bank account",
                 "parameters": [
                     {
-                        "description": "Budget creation request",
+                        "description": "Bank account creation request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateBudgetRequest"
+                            "$ref": "#/definitions/models.CreateBankAccountRequest"
                         }
                     }
                 ],
@@ -689,7 +669,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Budget"
+                            "$ref": "#/definitions/models.BankAccount"
                         }
                     },
                     "400": {
@@ -716,36 +696,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/budgets/summary": {
-            "get": {
+        "/bankAccounts/import": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get overall budget summary (total planned vs spent) for a month",
+                "description": "Import a camt.053 or camt.052 statement into the bank account whose IBAN matches the statement; opening and closing balances are reconciled with the account balance",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "import"
                 ],
-                "summary": "Get budget summary for a month",
+                "summary": "Import a camt statement by IBAN",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 2024,
-                        "description": "Year",
-                        "name": "year",
-                        "in": "query",
+                        "type": "file",
+                        "description": "Statement file",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Month (1-12)",
-                        "name": "month",
-                        "in": "query",
+                        "enum": [
+                            "camt053",
+                            "camt052"
+                        ],
+                        "type": "string",
+                        "description": "Statement format",
+                        "name": "format",
+                        "in": "formData",
                         "required": true
                     }
                 ],
@@ -753,7 +738,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.BudgetSummary"
+                            "$ref": "#/definitions/models.ImportSummary"
                         }
                     },
                     "400": {
@@ -769,62 +754,39 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     }
                 }
             }
         },
-        "/budgets/{category_id}/status": {
+        "/bankAccounts/{bank_account_id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get budget status (spent/remaining) for a specific category and month",
+                "description": "Get a specific bank account by ID for the authenticated user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "bank-accounts"
                 ],
-                "summary": "Get budget status for a category",
+                "summary": "Get a specific bank account",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Category ID",
-                        "name": "category_id",
+                        "description": "Bank Account ID",
+                        "name": "bank_account_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 2024,
-                        "description": "Year",
-                        "name": "year",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Month (1-12)",
-                        "name": "month",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.BudgetStatus"
+                            "$ref": "#/definitions/models.BankAccount"
                         }
                     },
                     "400": {
@@ -842,7 +804,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Budget not found",
+                        "description": "Bank account not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -856,31 +818,43 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/categories": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all categories for the authenticated user's account",
+                "description": "Delete a bank account by ID (will also delete all related transactions)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "bank-accounts"
+                ],
+                "summary": "Delete a bank account",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank Account ID",
+                        "name": "bank_account_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all categories",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Success message",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Category"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -890,6 +864,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -898,40 +879,38 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/bankAccounts/{bank_account_id}/activate": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new expense or income category for the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Activate a previously deactivated bank account by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "bank-accounts"
                 ],
-                "summary": "Create a new category",
+                "summary": "Activate a bank account",
                 "parameters": [
                     {
-                        "description": "Category creation request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateCategoryRequest"
-                        }
+                        "type": "integer",
+                        "description": "Bank Account ID",
+                        "name": "bank_account_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Success message",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -948,6 +927,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -958,35 +944,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{category_id}": {
+        "/bankAccounts/{bank_account_id}/balance-history": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a specific category by ID for the authenticated user",
+                "description": "Get the end-of-day balance of a bank account for every day of a period; days are in the account timezone",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "transactions"
                 ],
-                "summary": "Get a specific category",
+                "summary": "Get bank account balance history",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Category ID",
-                        "name": "category_id",
+                        "description": "Bank account ID",
+                        "name": "bank_account_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD), 30 days before to by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD), today by default",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "$ref": "#/definitions/models.BalanceHistory"
                         }
                     },
                     "400": {
@@ -1002,52 +1000,39 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
-                    },
-                    "404": {
-                        "description": "Category not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/bankAccounts/{bank_account_id}/credit": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a category by ID for the authenticated user",
+                "description": "Get available credit, the last closed statement balance, payments since the statement, the remaining minimum payment, the payment due date and the estimated interest if the statement is not repaid",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "credit"
                 ],
-                "summary": "Delete a category",
+                "summary": "Get credit account status",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Category ID",
-                        "name": "category_id",
+                        "description": "Bank account ID",
+                        "name": "bank_account_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success message",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.CreditStatus"
                         }
                     },
                     "400": {
@@ -1063,47 +1048,105 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
-                    },
-                    "404": {
-                        "description": "Category not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     }
                 }
-            }
-        },
-        "/transactions": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all transactions for the authenticated user",
+                "description": "Set the credit limit, statement closing day, payment due day, annual interest rate and minimum payment percent of a credit bank account. Days after the end of a month mean its last day",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "credit"
+                ],
+                "summary": "Set credit account terms",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank account ID",
+                        "name": "bank_account_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Credit terms",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreditTermsRequest"
+                        }
+                    }
                 ],
-                "summary": "Get all transactions",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.TransactionResponse"
-                            }
+                            "$ref": "#/definitions/models.CreditTerms"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/bankAccounts/{bank_account_id}/deactivate": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deactivate a bank account by ID (keeps transactions but hides account)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bank-accounts"
+                ],
+                "summary": "Deactivate a bank account",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank Account ID",
+                        "name": "bank_account_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -1113,6 +1156,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1121,40 +1171,159 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/bankAccounts/{bank_account_id}/import": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new income, expense, or transfer transaction",
+                "description": "Import transactions from a CSV, OFX/QFX, QIF or camt.053/052 statement into a bank account. Rows with an already imported OFX FITID / camt reference or matching an existing transaction by date, amount and description are skipped",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "import"
                 ],
-                "summary": "Create a new transaction",
+                "summary": "Import a bank statement",
                 "parameters": [
                     {
-                        "description": "Transaction creation request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "Bank account ID",
+                        "name": "bank_account_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Statement file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ofx",
+                            "qfx",
+                            "qif",
+                            "camt053",
+                            "camt052"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Statement format",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSV: date column name or number (from 1), required for csv",
+                        "name": "date_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSV: amount column name or number, negative amounts are expenses, required for csv",
+                        "name": "amount_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSV: description column name or number, required for csv",
+                        "name": "description_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSV: category name column",
+                        "name": "category_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date format for csv and qif, e.g. DD.MM.YYYY",
+                        "name": "date_format",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            ".",
+                            "\"",
+                            "\""
+                        ],
+                        "type": "string",
+                        "description": "Decimal separator",
+                        "name": "decimal_separator",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": ",",
+                        "description": "CSV delimiter: , ; tab |",
+                        "name": "delimiter",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CreateTransactionRequest"
+                            "$ref": "#/definitions/models.ImportSummary"
                         }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/bank_accounts/{account_id}/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current balance of a specific bank account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get bank account balance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank Account ID",
+                        "name": "account_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Balance information",
                         "schema": {
-                            "$ref": "#/definitions/models.TransactionResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -1181,35 +1350,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/transactions/{id}": {
+        "/budgets": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a specific transaction by ID for the authenticated user",
+                "description": "Get all budgets whose period contains the reference date, with status over each budget's own period",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "budgets"
                 ],
-                "summary": "Get a specific transaction",
+                "summary": "Get budgets for a date",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reference date YYYY-MM-DD, defaults to today",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Budget period: weekly, monthly or yearly, all periods if empty",
+                        "name": "period",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Transaction ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Year, used with month when date is not set",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Month (1-12), used with year when date is not set",
+                        "name": "month",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TransactionResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BudgetWithStatus"
+                            }
                         }
                     },
                     "400": {
@@ -1226,13 +1415,6 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Transaction not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1241,16 +1423,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/transfer": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a transfer transaction between two bank accounts",
+                "description": "Create a weekly, monthly or yearly budget for a category (category_id), a set of categories (category_ids) or all expense categories of the account (all_categories). The period is the calendar week (starting on the account week start day), month or year containing date, or the month/year from year and month. Mode limit uses amount as the period limit, mode envelope makes available what is assigned to it. With rollover the remainder or overspend carries into the next period",
                 "consumes": [
                     "application/json"
                 ],
@@ -1258,26 +1438,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "budgets"
                 ],
-                "summary": "Transfer money between bank accounts",
+                "summary": "Create a new budget",
                 "parameters": [
                     {
-                        "description": "Transfer request",
+                        "description": "Budget creation request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.TransferRequest"
+                            "$ref": "#/definitions/models.CreateBudgetRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Success message",
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Budget"
                         }
                     },
                     "400": {
@@ -1303,87 +1482,4378 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.AccountResponse": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "display_name": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "timezone": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
-                }
-            }
         },
-        "models.BankAccount": {
-            "type": "object",
+        "/budgets/copy": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Clone every monthly budget of one month into another month, optionally adjusting amounts by a percentage. Budgets whose set of categories already has a monthly budget in the target month are skipped",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Copy monthly budgets to another month",
+                "parameters": [
+                    {
+                        "description": "Copy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CopyBudgetsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CopyBudgetsResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets/envelopes/assign": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assign money to an envelope budget in the account base currency. A negative amount returns money to \"to be assigned\" and cannot exceed what is left in the envelope",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Assign money to an envelope",
+                "parameters": [
+                    {
+                        "description": "Assignment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AssignBudgetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets/envelopes/move": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move money from one envelope budget to another, no more than is left in the source envelope. Returns statuses of both envelopes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Move money between envelopes",
+                "parameters": [
+                    {
+                        "description": "Move",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MoveBudgetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BudgetStatus"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets/envelopes/{id}/assignments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the history of money assigned to and moved from an envelope budget, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Get envelope assignments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BudgetAssignment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Envelope not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets/summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get overall budget summary (total planned vs spent) for the budgets whose period contains the reference date",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Get budget summary for a date",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reference date YYYY-MM-DD, defaults to today",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Budget period: weekly, monthly or yearly, all periods if empty",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year, used with month when date is not set",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Month (1-12), used with year when date is not set",
+                        "name": "month",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets/{category_id}/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get budget status (spent/remaining) for a budget covering the category whose period contains the reference date: a category budget, a multi-category budget or an all-categories budget. Without period the shortest period budget is used, a single category budget before broader ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Get budget status for a category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reference date YYYY-MM-DD, defaults to today",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Budget period: weekly, monthly or yearly, all periods if empty",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year, used with month when date is not set",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Month (1-12), used with year when date is not set",
+                        "name": "month",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Budget not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the name, the amount and the rollover flag of a budget. The period, dates and mode cannot be changed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Update a budget",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Budget update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateBudgetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a budget. Money assigned to an envelope returns to \"to be assigned\", transactions are not affected",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Delete a budget",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all categories for the authenticated user's account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Get all categories",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Category"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new expense or income category for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Create a new category",
+                "parameters": [
+                    {
+                        "description": "Category creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{category_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific category by ID for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Get a specific category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a category by ID for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Delete a category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/counterparties": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all counterparties of the user ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debts"
+                ],
+                "summary": "Get counterparties",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Counterparty"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a person money is lent to or borrowed from. Names are unique per user, case-insensitive",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debts"
+                ],
+                "summary": "Create a counterparty",
+                "parameters": [
+                    {
+                        "description": "Counterparty",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateCounterpartyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Counterparty"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/counterparties/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a counterparty without debts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debts"
+                ],
+                "summary": "Delete a counterparty",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Counterparty ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/debts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get outstanding debts of the user, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debts"
+                ],
+                "summary": "Get debts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only debts with this counterparty",
+                        "name": "counterparty_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include fully repaid debts",
+                        "name": "include_settled",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Debt"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record money lent to or borrowed from a counterparty. With bank_account_id a debt transaction moves the money on that account; debt transactions change balances but are excluded from income, expenses and budgets",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debts"
+                ],
+                "summary": "Record a debt",
+                "parameters": [
+                    {
+                        "description": "Debt",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateDebtRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Debt"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/debts/balances": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get outstanding amounts per counterparty and currency: owed to the user, owed by the user and the net",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debts"
+                ],
+                "summary": "Get outstanding debt balances",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DebtBalance"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/debts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a debt with its repayments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debts"
+                ],
+                "summary": "Get a debt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Debt ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Debt"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a debt, its repayments and their bank account transactions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debts"
+                ],
+                "summary": "Delete a debt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Debt ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/debts/{id}/repayments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record a partial or full repayment, not more than the outstanding amount. With bank_account_id (in the debt currency) a debt transaction moves the money on that account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debts"
+                ],
+                "summary": "Repay a debt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Debt ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Repayment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DebtRepaymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.DebtRepayment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/goals": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all goals of the user with progress, the monthly contribution needed to reach the target date and the projected completion date based on the average contribution of the last months",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Get savings goals",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Months for the average contribution (1-24, default 3)",
+                        "name": "months",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.GoalProgress"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a goal with a target amount and date. With bank_account_id the progress is the balance of that savings bank account, otherwise contributions are logged manually and currency is required",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Create a savings goal",
+                "parameters": [
+                    {
+                        "description": "Goal",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateGoalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Goal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get progress of a goal, the monthly contribution needed and the projected completion date",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Get savings goal progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Goal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Months for the average contribution (1-24, default 3)",
+                        "name": "months",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GoalProgress"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a goal and its contributions, the linked bank account is not changed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Delete a savings goal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Goal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}/contributions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get manually logged contributions of a goal, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Get savings goal contributions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Goal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.GoalContribution"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Log a contribution to a goal without a linked bank account, a negative amount is a withdrawal. Milestone notifications are sent at 25, 50, 75 and 100%",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Add a contribution to a savings goal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Goal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Contribution",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GoalContributionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.GoalContribution"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/installments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all installment plans of the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "installments"
+                ],
+                "summary": "Get installment plans",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.InstallmentPlan"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an installment plan on a bank account. The total amount plus the fee is split into equal monthly payments starting from first_payment_date; every payment not paid yet is created as a planned expense transaction that becomes a real one on its due date",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "installments"
+                ],
+                "summary": "Create an installment plan",
+                "parameters": [
+                    {
+                        "description": "Installment plan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateInstallmentPlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.InstallmentPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/installments/obligations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get installment payments not made yet grouped by month, starting with the current one. Month totals are in the base currency at today's rate",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "installments"
+                ],
+                "summary": "Get outstanding installment obligations by month",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Months ahead (1-60, default 12)",
+                        "name": "months",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.InstallmentObligations"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/installments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get an installment plan with its payment schedule and the remaining amount",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "installments"
+                ],
+                "summary": "Get an installment plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Installment plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.InstallmentPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an installment plan and its future planned payments; payments already made stay as regular transactions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "installments"
+                ],
+                "summary": "Delete an installment plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Installment plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/rates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the rate from one currency to another on a date: the latest stored rate not after the date, inverse rate or cross rate through a pivot currency (EUR, KZT, USD)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rates"
+                ],
+                "summary": "Get a currency rate on a date",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "USD",
+                        "description": "From currency",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "KZT",
+                        "description": "To currency",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD), today by default",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CurrencyRateQuote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Rate not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all recurring transaction rules of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Get recurring transactions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RecurringTransaction"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a rule that materializes income or expense transactions daily, weekly, monthly or yearly",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Create a recurring transaction",
+                "parameters": [
+                    {
+                        "description": "Recurring transaction request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a recurring transaction rule by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Get a recurring transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Recurring transaction not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change a recurring transaction rule; the schedule is recalculated without repeating past runs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Update a recurring transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Recurring transaction request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a recurring transaction rule; already created transactions are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Delete a recurring transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all transactions for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get all transactions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TransactionResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new income, expense, or transfer transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Create a new transaction",
+                "parameters": [
+                    {
+                        "description": "Transaction creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific transaction by ID for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get a specific transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change amount, category, description, date and type of an income or expense transaction. is_planned false realizes a planned transaction, which then counts towards balances and budgets",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Update a transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transaction update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Transaction belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an income or expense transaction by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Delete a transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Transaction belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a transfer transaction between two bank accounts. For accounts in different currencies pass rate or received_amount, otherwise the stored rate is used",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Transfer money between bank accounts",
+                "parameters": [
+                    {
+                        "description": "Transfer request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.Account": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "description": "основная валюта для расчетов (KZT, USD, EUR)",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "description": "имя для отображения в UI",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "\"Мой аккаунт\", или имя пользователя",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "для корректного отображения времени",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "ID из auth-сервиса (Node.js)",
+                    "type": "string"
+                },
+                "week_start_day": {
+                    "description": "первый день недели для недельных бюджетов: 0 - воскресенье, 1 - понедельник",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AccountResponse": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "week_start_day": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AccountSummary": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/models.Account"
+                },
+                "balances": {
+                    "description": "балансы по валютам: [{currency: \"KZT\", amount: 150000}, ...]",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyBalance"
+                    }
+                },
+                "bank_accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BankAccountSummary"
+                    }
+                },
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BudgetStatus"
+                    }
+                },
+                "monthly_expenses": {
+                    "description": "расходы за месяц по валютам",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyBalance"
+                    }
+                },
+                "monthly_income": {
+                    "description": "доходы за месяц по валютам",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyBalance"
+                    }
+                }
+            }
+        },
+        "models.AssignBudgetRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "budget_id"
+            ],
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "budget_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "models.BalanceHistory": {
+            "type": "object",
+            "properties": {
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DailyBalance"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BankAccount": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "ссылка на главный аккаунт",
+                    "type": "integer"
+                },
+                "account_type": {
+                    "description": "\"cash\", \"debit\", \"credit\", \"savings\"",
+                    "type": "string"
+                },
+                "bank_name": {
+                    "description": "\"Kaspi\", \"Halyk\", \"Cash\"",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "\"KZT\", \"USD\", \"EUR\"",
+                    "type": "string"
+                },
+                "iban": {
+                    "description": "для сопоставления выписок camt.053/052",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "\"Каспи рубли\", \"Халык доллары\", \"Наличные USD\"",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BankAccountSummary": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "bank_account": {
+                    "$ref": "#/definitions/models.BankAccount"
+                }
+            }
+        },
+        "models.Budget": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "all_categories": {
+                    "description": "по всем категориям аккаунта",
+                    "type": "boolean"
+                },
+                "amount": {
+                    "description": "лимит на период в базовой валюте аккаунта",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "budget_limit_name": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "description": "у бюджета нескольких категорий и по всем категориям - nil",
+                    "type": "integer"
+                },
+                "category_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "mode": {
+                    "description": "\"limit\" - лимит amount на период, \"envelope\" - конверт",
+                    "type": "string"
+                },
+                "period": {
+                    "description": "\"monthly\", \"weekly\", \"yearly\"",
+                    "type": "string"
+                },
+                "rollover": {
+                    "description": "остаток или перерасход переносится в следующий период",
+                    "type": "boolean"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BudgetAssignment": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "description": "в базовой валюте, отрицательная - из конверта",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "budget_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "related_budget_id": {
+                    "description": "другой конверт при перемещении",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BudgetStatus": {
+            "type": "object",
+            "properties": {
+                "assigned": {
+                    "description": "лимит периода или распределенное в конверт",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "available": {
+                    "description": "assigned + carried_in, от него считаются remaining и progress",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "budget": {
+                    "$ref": "#/definitions/models.Budget"
+                },
+                "carried_in": {
+                    "description": "перенесено из прошлых периодов (rollover), отрицательное - перерасход",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "installments": {
+                    "description": "платежи по рассрочкам категории, которые еще наступят в периоде бюджета",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "is_exceeded": {
+                    "description": "превышен ли бюджет",
+                    "type": "boolean"
+                },
+                "is_projected_exceeded": {
+                    "description": "будет ли бюджет превышен с учетом рассрочек",
+                    "type": "boolean"
+                },
+                "progress": {
+                    "description": "процент использования (0-100)",
+                    "type": "number"
+                },
+                "projected": {
+                    "description": "потрачено + платежи по рассрочкам",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "remaining": {
+                    "description": "осталось",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "spent": {
+                    "description": "потрачено в текущем периоде в валюте бюджета",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.BudgetSummary": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "description": "Детали по каждому бюджету",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BudgetWithStatus"
+                    }
+                },
+                "is_over_budget": {
+                    "description": "Превышен ли общий бюджет",
+                    "type": "boolean"
+                },
+                "to_be_assigned": {
+                    "description": "Доход, еще не распределенный по конвертам",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "total_available": {
+                    "description": "Доступно с учетом переносов и конвертов",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "total_carried_in": {
+                    "description": "Перенесено из прошлых периодов",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "total_installments": {
+                    "description": "Предстоящие платежи по рассрочкам в категориях бюджетов",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "total_planned": {
+                    "description": "Общая планируемая сумма",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "total_remaining": {
+                    "description": "Общая оставшаяся сумма",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "total_spent": {
+                    "description": "Общая потраченная сумма",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.BudgetWithStatus": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "$ref": "#/definitions/models.Budget"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.BudgetStatus"
+                }
+            }
+        },
+        "models.CashFlowForecast": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CashFlowMonth"
+                    }
+                },
+                "start_balance": {
+                    "description": "остаток активных счетов сейчас",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.CashFlowMonth": {
+            "type": "object",
+            "properties": {
+                "end_balance": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "installments": {
+                    "description": "из них платежи по рассрочкам",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "net_flow": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "planned_expense": {
+                    "description": "включая платежи по рассрочкам",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "planned_income": {
+                    "$ref": "#/definitions/models.Money"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "привязаны к аккаунту",
+                    "type": "integer"
+                },
+                "color": {
+                    "description": "для UI",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "icon": {
+                    "description": "для UI",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "\"Продукты\", \"Транспорт\", \"Зарплата\"",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "\"income\", \"expense\"",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CategorySpending": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "заполняется в отчетах по произвольному периоду",
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CopyBudgetsRequest": {
+            "type": "object",
+            "required": [
+                "from_month",
+                "from_year",
+                "to_month",
+                "to_year"
+            ],
+            "properties": {
+                "adjust_percent": {
+                    "description": "изменение сумм, %: 10 - на 10% больше, -5 - меньше",
+                    "type": "number",
+                    "maximum": 1000,
+                    "minimum": -100
+                },
+                "from_month": {
+                    "type": "integer",
+                    "maximum": 12,
+                    "minimum": 1
+                },
+                "from_year": {
+                    "type": "integer",
+                    "minimum": 2020
+                },
+                "to_month": {
+                    "type": "integer",
+                    "maximum": 12,
+                    "minimum": 1
+                },
+                "to_year": {
+                    "type": "integer",
+                    "minimum": 2020
+                }
+            }
+        },
+        "models.CopyBudgetsResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Budget"
+                    }
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Budget"
+                    }
+                }
+            }
+        },
+        "models.Counterparty": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateAccountRequest": {
+            "type": "object",
+            "required": [
+                "display_name"
+            ],
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "maxLength": 40,
+                    "minLength": 2
+                }
+            }
+        },
+        "models.CreateBankAccountRequest": {
+            "type": "object",
+            "required": [
+                "account_type",
+                "bank_name",
+                "currency",
+                "name"
+            ],
+            "properties": {
+                "account_type": {
+                    "type": "string",
+                    "enum": [
+                        "cash",
+                        "debit",
+                        "credit",
+                        "savings"
+                    ]
+                },
+                "bank_name": {
+                    "type": "string",
+                    "maxLength": 40,
+                    "minLength": 2
+                },
+                "currency": {
+                    "type": "string",
+                    "enum": [
+                        "KZT",
+                        "USD",
+                        "EUR",
+                        "RUB"
+                    ]
+                },
+                "iban": {
+                    "description": "необязательно, пробелы допускаются",
+                    "type": "string",
+                    "maxLength": 42
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 40,
+                    "minLength": 2
+                },
+                "opening_balance": {
+                    "description": "начальный остаток уже существующего счета, может быть отрицательным; не считается доходом",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "opening_date": {
+                    "description": "\"2024-10-01\" в таймзоне аккаунта, по умолчанию - сейчас",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateBudgetRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "budget_name"
+            ],
+            "properties": {
+                "all_categories": {
+                    "description": "или все категории аккаунта",
+                    "type": "boolean"
+                },
+                "amount": {
+                    "description": "Планируемая сумма, больше нуля",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "budget_name": {
+                    "description": "\"Продукты на октябрь\"",
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2
+                },
+                "category_id": {
+                    "description": "ID категории",
+                    "type": "integer"
+                },
+                "category_ids": {
+                    "description": "или несколько категорий",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "date": {
+                    "description": "любой день периода, YYYY-MM-DD",
+                    "type": "string"
+                },
+                "mode": {
+                    "description": "по умолчанию limit",
+                    "type": "string",
+                    "enum": [
+                        "limit",
+                        "envelope"
+                    ]
+                },
+                "month": {
+                    "description": "Месяц (1-12) для monthly без date",
+                    "type": "integer",
+                    "maximum": 12,
+                    "minimum": 1
+                },
+                "period": {
+                    "description": "по умолчанию monthly",
+                    "type": "string",
+                    "enum": [
+                        "monthly",
+                        "weekly",
+                        "yearly"
+                    ]
+                },
+                "rollover": {
+                    "description": "переносить остаток в следующий период",
+                    "type": "boolean"
+                },
+                "year": {
+                    "description": "Год для monthly и yearly без date",
+                    "type": "integer",
+                    "minimum": 2020
+                }
+            }
+        },
+        "models.CreateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "color",
+                "icon",
+                "name",
+                "type"
+            ],
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "income",
+                        "expense"
+                    ]
+                }
+            }
+        },
+        "models.CreateCounterpartyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "models.CreateDebtRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "counterparty_id",
+                "direction"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "больше нуля",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "bank_account_id": {
+                    "description": "валюта долга - валюта счета",
+                    "type": "integer"
+                },
+                "counterparty_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string",
+                    "enum": [
+                        "KZT",
+                        "USD",
+                        "EUR",
+                        "RUB"
+                    ]
+                },
+                "date": {
+                    "description": "\"2024-10-01\" в таймзоне аккаунта, по умолчанию - сейчас",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "по умолчанию \"Lent to \u003cимя\u003e\" / \"Borrowed from \u003cимя\u003e\"",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "direction": {
+                    "type": "string",
+                    "enum": [
+                        "lent",
+                        "borrowed"
+                    ]
+                },
+                "due_date": {
+                    "description": "\"2024-12-01\", срок возврата",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateGoalRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "target_amount",
+                "target_date"
+            ],
+            "properties": {
+                "bank_account_id": {
+                    "description": "счет с account_type = savings",
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string",
+                    "enum": [
+                        "KZT",
+                        "USD",
+                        "EUR",
+                        "RUB"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2
+                },
+                "target_amount": {
+                    "description": "больше нуля",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "target_date": {
+                    "description": "\"2025-06-01\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateInstallmentPlanRequest": {
+            "type": "object",
+            "required": [
+                "bank_account_id",
+                "description",
+                "first_payment_date",
+                "payments_count",
+                "total_amount"
+            ],
+            "properties": {
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "fee": {
+                    "description": "комиссия за весь срок",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "first_payment_date": {
+                    "description": "\"2024-11-05\"",
+                    "type": "string"
+                },
+                "paid_payments": {
+                    "description": "сколько платежей уже внесено до заведения рассрочки",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "payments_count": {
+                    "description": "число платежей",
+                    "type": "integer",
+                    "maximum": 60,
+                    "minimum": 1
+                },
+                "total_amount": {
+                    "description": "стоимость покупки, больше нуля",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.CreateTransactionRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "bank_account_id",
+                "description",
+                "transaction_type"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Сумма транзакции",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "bank_account_id": {
+                    "description": "ID банковского счета",
+                    "type": "integer"
+                },
+                "category_id": {
+                    "description": "ID категории (может быть null)",
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "\"2024-10-01\" или \"2024-10-01T15:04:05\" в таймзоне аккаунта, RFC3339; если нет - сейчас",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Описание транзакции",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "is_planned": {
+                    "description": "запланированная транзакция, только для нее дата может быть в будущем",
+                    "type": "boolean"
+                },
+                "splits": {
+                    "description": "разбивка по категориям, сумма = amount",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionSplitRequest"
+                    }
+                },
+                "transaction_type": {
+                    "description": "Тип: доход или расход",
+                    "type": "string",
+                    "enum": [
+                        "income",
+                        "expense"
+                    ]
+                }
+            }
+        },
+        "models.CreditStatus": {
+            "type": "object",
+            "properties": {
+                "available_credit": {
+                    "description": "лимит + остаток, отрицательный - лимит превышен",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "balance": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "estimated_interest": {
+                    "description": "проценты за месяц, если не погасить остаток выписки",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "minimum_payment": {
+                    "description": "оставшийся минимальный платеж",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "paid_since_statement": {
+                    "description": "пополнения после закрытия выписки",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "remaining_statement": {
+                    "description": "сколько еще погасить до срока, чтобы не платить проценты",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "statement_balance": {
+                    "description": "задолженность на конец дня закрытия",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "statement_end": {
+                    "description": "день закрытия выписки",
+                    "type": "string"
+                },
+                "statement_start": {
+                    "description": "первый день периода выписки",
+                    "type": "string"
+                },
+                "terms": {
+                    "$ref": "#/definitions/models.CreditTerms"
+                }
+            }
+        },
+        "models.CreditTerms": {
+            "type": "object",
+            "properties": {
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credit_limit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "interest_rate": {
+                    "description": "годовая ставка, %",
+                    "type": "number"
+                },
+                "min_payment_percent": {
+                    "description": "минимальный платеж, % от выписки",
+                    "type": "number"
+                },
+                "payment_due_day": {
+                    "description": "день платежа, 1-31",
+                    "type": "integer"
+                },
+                "statement_day": {
+                    "description": "день закрытия выписки, 1-31",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreditTermsRequest": {
+            "type": "object",
+            "required": [
+                "credit_limit",
+                "payment_due_day",
+                "statement_day"
+            ],
+            "properties": {
+                "credit_limit": {
+                    "description": "больше нуля",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "interest_rate": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "min_payment_percent": {
+                    "description": "по умолчанию 5",
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "payment_due_day": {
+                    "type": "integer",
+                    "maximum": 31,
+                    "minimum": 1
+                },
+                "statement_day": {
+                    "type": "integer",
+                    "maximum": 31,
+                    "minimum": 1
+                }
+            }
+        },
+        "models.CurrencyBalance": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CurrencyRate": {
+            "type": "object",
+            "properties": {
+                "from_currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "rate_date": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "\"ecb\", \"nbk\", \"manual\"",
+                    "type": "string"
+                },
+                "to_currency": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CurrencyRateQuote": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "from_currency": {
+                    "type": "string"
+                },
+                "pivot": {
+                    "description": "опорная валюта, если прямого курса нет",
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "rates": {
+                    "description": "сохраненные курсы, из которых посчитан Rate",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyRate"
+                    }
+                },
+                "to_currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DailyBalance": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Debt": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "counterparty_id": {
+                    "type": "integer"
+                },
+                "counterparty_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_settled": {
+                    "type": "boolean"
+                },
+                "outstanding": {
+                    "description": "Amount - Repaid",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "repaid": {
+                    "description": "сумма возвратов",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "repayments": {
+                    "description": "заполняется при чтении одного долга",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DebtRepayment"
+                    }
+                },
+                "transaction_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DebtBalance": {
+            "type": "object",
+            "properties": {
+                "counterparty_id": {
+                    "type": "integer"
+                },
+                "counterparty_name": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "i_owe": {
+                    "description": "остаток по borrowed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "net": {
+                    "description": "\u003e 0 - должны нам",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "owed_to_me": {
+                    "description": "остаток по lent",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.DebtRepayment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "debt_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DebtRepaymentRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "больше нуля, не больше остатка долга",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "bank_account_id": {
+                    "description": "счет в валюте долга",
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "\"2024-10-01\" в таймзоне аккаунта, по умолчанию - сейчас",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "models.Goal": {
+            "type": "object",
             "properties": {
                 "account_id": {
-                    "description": "ссылка на главный аккаунт",
                     "type": "integer"
                 },
-                "account_type": {
-                    "description": "\"cash\", \"debit\", \"credit\", \"savings\"",
-                    "type": "string"
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_milestone": {
+                    "description": "последний отмеченный рубеж, %",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "target_amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "target_date": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GoalContribution": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "goal_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GoalContributionRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "отрицательная - снятие",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "date": {
+                    "description": "\"2024-10-01\" в таймзоне аккаунта, по умолчанию - сейчас",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "models.GoalProgress": {
+            "type": "object",
+            "properties": {
+                "average_monthly": {
+                    "description": "средний прирост накоплений в месяц",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "goal": {
+                    "$ref": "#/definitions/models.Goal"
+                },
+                "is_completed": {
+                    "type": "boolean"
+                },
+                "monthly_needed": {
+                    "description": "сколько откладывать в месяц, чтобы успеть к сроку",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "months": {
+                    "description": "за сколько последних месяцев считается средний взнос",
+                    "type": "integer"
+                },
+                "months_left": {
+                    "description": "полных месяцев до TargetDate, не меньше 1",
+                    "type": "integer"
+                },
+                "on_track": {
+                    "description": "прогноз не позже срока",
+                    "type": "boolean"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "projected_date": {
+                    "description": "когда цель будет достигнута при таком взносе, nil - никогда",
+                    "type": "string"
+                },
+                "remaining": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "saved": {
+                    "$ref": "#/definitions/models.Money"
+                }
+            }
+        },
+        "models.ImportRowIssue": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportSummary": {
+            "type": "object",
+            "properties": {
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "failed_rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRowIssue"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "imported_rows": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "reconciliation": {
+                    "description": "только если в выписке есть остатки (camt)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.StatementReconciliation"
+                        }
+                    ]
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "skipped_rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRowIssue"
+                    }
+                }
+            }
+        },
+        "models.IncomeExpensePoint": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "net_income": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "total_expense": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "total_income": {
+                    "$ref": "#/definitions/models.Money"
+                }
+            }
+        },
+        "models.IncomeExpenseReport": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "net_income": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "periods": {
+                    "description": "временной ряд, если задан group_by",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IncomeExpensePoint"
+                    }
+                },
+                "savings_rate": {
+                    "description": "процент сбережений",
+                    "type": "number"
+                },
+                "total_expense": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "total_income": {
+                    "$ref": "#/definitions/models.Money"
+                }
+            }
+        },
+        "models.InstallmentObligations": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "базовая валюта аккаунта",
+                    "type": "string"
+                },
+                "month": {
+                    "type": "integer"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InstallmentPayment"
+                    }
+                },
+                "total": {
+                    "description": "сумма платежей в базовой валюте по текущему курсу",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InstallmentPayment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "отрицательная, как у транзакции расхода",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "is_paid": {
+                    "description": "транзакция уже не запланированная",
+                    "type": "boolean"
+                },
+                "plan_id": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InstallmentPlan": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "fee": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "first_payment_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "paid_payments": {
+                    "description": "оплачено до заведения рассрочки, транзакций нет",
+                    "type": "integer"
+                },
+                "payments": {
+                    "description": "заполняются при чтении одной рассрочки",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InstallmentPayment"
+                    }
+                },
+                "payments_count": {
+                    "type": "integer"
+                },
+                "remaining": {
+                    "description": "сумма еще не наступивших платежей",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
                 },
-                "bank_name": {
-                    "description": "\"Kaspi\", \"Halyk\", \"Cash\"",
-                    "type": "string"
+                "total_amount": {
+                    "$ref": "#/definitions/models.Money"
                 },
-                "created_at": {
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Money": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "\"KZT\", \"USD\"; пусто, если валюта определяется счетом",
                     "type": "string"
                 },
+                "minor": {
+                    "description": "сумма в сотых долях единицы валюты",
+                    "type": "integer",
+                    "format": "int64"
+                }
+            }
+        },
+        "models.MonthlyReport": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategorySpending"
+                    }
+                },
                 "currency": {
-                    "description": "\"KZT\", \"USD\", \"EUR\"",
+                    "description": "базовая валюта аккаунта, в нее пересчитаны суммы",
                     "type": "string"
                 },
-                "id": {
+                "month": {
                     "type": "integer"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "net_income": {
+                    "$ref": "#/definitions/models.Money"
                 },
-                "name": {
-                    "description": "\"Каспи рубли\", \"Халык доллары\", \"Наличные USD\"",
+                "top_expenses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transaction"
+                    }
+                },
+                "total_expense": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "total_income": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MoveBudgetRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "from_budget_id",
+                "to_budget_id"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "больше нуля",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "from_budget_id": {
+                    "type": "integer"
+                },
+                "to_budget_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NetWorth": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
                     "type": "string"
                 },
-                "updated_at": {
+                "currencies": {
+                    "description": "подытоги по валютам счетов",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NetWorthCurrency"
+                    }
+                },
+                "date": {
                     "type": "string"
+                },
+                "total": {
+                    "description": "сумма остатков в базовой валюте",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
                 }
             }
         },
-        "models.Budget": {
+        "models.NetWorthCurrency": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "сумма остатков в этой валюте",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "bank_accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BankAccountSummary"
+                    }
+                },
+                "converted": {
+                    "description": "то же в базовой валюте",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "примененный курс",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CurrencyRateQuote"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.RecurringTransaction": {
             "type": "object",
             "properties": {
                 "account_id": {
                     "type": "integer"
                 },
                 "amount": {
-                    "description": "лимит на период в базовой валюте аккаунта",
-                    "type": "number"
+                    "$ref": "#/definitions/models.Money"
                 },
-                "budget_limit_name": {
-                    "type": "string"
+                "bank_account_id": {
+                    "type": "integer"
                 },
                 "category_id": {
                     "type": "integer"
@@ -1391,120 +5861,273 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "day_of_month": {
+                    "description": "для monthly, иначе день из start_date",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
                 "end_date": {
                     "type": "string"
                 },
+                "frequency": {
+                    "description": "\"daily\", \"weekly\", \"monthly\", \"yearly\"",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "interval": {
+                    "description": "каждые N периодов",
+                    "type": "integer"
+                },
                 "is_active": {
                     "type": "boolean"
                 },
-                "period": {
-                    "description": "\"monthly\", \"weekly\", \"yearly\"",
+                "last_run_date": {
+                    "type": "string"
+                },
+                "next_run_date": {
                     "type": "string"
                 },
                 "start_date": {
                     "type": "string"
                 },
+                "transaction_type": {
+                    "description": "\"income\", \"expense\"",
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.BudgetStatus": {
+        "models.RecurringTransactionRequest": {
             "type": "object",
+            "required": [
+                "amount",
+                "bank_account_id",
+                "description",
+                "frequency",
+                "start_date",
+                "transaction_type"
+            ],
             "properties": {
-                "budget": {
-                    "$ref": "#/definitions/models.Budget"
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
                 },
-                "is_exceeded": {
-                    "description": "превышен ли бюджет",
-                    "type": "boolean"
+                "bank_account_id": {
+                    "type": "integer"
                 },
-                "progress": {
-                    "description": "процент использования (0-100)",
-                    "type": "number"
+                "category_id": {
+                    "type": "integer"
                 },
-                "remaining": {
-                    "description": "осталось",
-                    "type": "number"
+                "day_of_month": {
+                    "type": "integer",
+                    "maximum": 31,
+                    "minimum": 1
                 },
-                "spent": {
-                    "description": "потрачено в текущем периоде в валюте бюджета",
-                    "type": "number"
+                "description": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "end_date": {
+                    "description": "\"2025-10-01\", необязательно",
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "yearly"
+                    ]
+                },
+                "interval": {
+                    "description": "по умолчанию 1",
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 1
+                },
+                "is_active": {
+                    "description": "только при изменении",
+                    "type": "boolean"
+                },
+                "start_date": {
+                    "description": "\"2024-10-01\"",
+                    "type": "string"
+                },
+                "transaction_type": {
+                    "type": "string",
+                    "enum": [
+                        "income",
+                        "expense"
+                    ]
                 }
             }
         },
-        "models.BudgetSummary": {
+        "models.StatementReconciliation": {
             "type": "object",
             "properties": {
-                "budgets": {
-                    "description": "Детали по каждому бюджету",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.BudgetWithStatus"
-                    }
+                "closing_balance": {
+                    "description": "исходящий остаток из выписки",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
                 },
-                "is_over_budget": {
-                    "description": "Превышен ли общий бюджет",
+                "closing_difference": {
+                    "description": "ledger_balance - closing_balance",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "iban": {
+                    "type": "string"
+                },
+                "is_balanced": {
                     "type": "boolean"
                 },
-                "total_planned": {
-                    "description": "Общая планируемая сумма",
-                    "type": "number"
+                "ledger_balance": {
+                    "description": "баланс счета после импорта",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
                 },
-                "total_remaining": {
-                    "description": "Общая оставшаяся сумма",
-                    "type": "number"
+                "ledger_opening_balance": {
+                    "description": "баланс счета без операций выписки",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
                 },
-                "total_spent": {
-                    "description": "Общая потраченная сумма",
-                    "type": "number"
+                "opening_balance": {
+                    "description": "входящий остаток из выписки",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "opening_difference": {
+                    "description": "ledger_opening_balance - opening_balance",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
                 }
             }
         },
-        "models.BudgetWithStatus": {
+        "models.Transaction": {
             "type": "object",
             "properties": {
-                "budget": {
-                    "$ref": "#/definitions/models.Budget"
+                "amount": {
+                    "description": "положительное для доходов, отрицательное для расходов",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
                 },
-                "status": {
-                    "$ref": "#/definitions/models.BudgetStatus"
+                "bank_account_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "description": "может быть null для переводов",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "description": "id из выписки банка (FITID), повторный импорт его пропускает",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "installment_plan_id": {
+                    "description": "Платеж по рассрочке - меняется только через план",
+                    "type": "integer"
+                },
+                "is_planned": {
+                    "description": "запланированная, не влияет на баланс и бюджеты",
+                    "type": "boolean"
+                },
+                "splits": {
+                    "description": "Разбивка по категориям (чек из супермаркета), сумма строк = Amount",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionSplit"
+                    }
+                },
+                "to_account_id": {
+                    "description": "Для переводов между банковскими счетами",
+                    "type": "integer"
+                },
+                "transaction_type": {
+                    "description": "\"income\", \"expense\", \"transfer\", \"opening_balance\", \"debt\"",
+                    "type": "string"
+                },
+                "transfer_rate": {
+                    "description": "курс валют если перевод между валютами",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "models.Category": {
+        "models.TransactionResponse": {
             "type": "object",
             "properties": {
-                "account_id": {
-                    "description": "привязаны к аккаунту",
+                "amount": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "bank_account_id": {
                     "type": "integer"
                 },
-                "color": {
-                    "description": "для UI",
-                    "type": "string"
+                "category_id": {
+                    "type": "integer"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "icon": {
-                    "description": "для UI",
+                "date": {
+                    "type": "string"
+                },
+                "description": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "is_active": {
+                "is_planned": {
                     "type": "boolean"
                 },
-                "name": {
-                    "description": "\"Продукты\", \"Транспорт\", \"Зарплата\"",
-                    "type": "string"
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionSplit"
+                    }
                 },
-                "type": {
-                    "description": "\"income\", \"expense\"",
+                "transaction_type": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -1512,219 +6135,235 @@ const docTemplate = `{
                 }
             }
         },
-        "models.CreateAccountRequest": {
-            "type": "object",
-            "required": [
-                "display_name"
-            ],
-            "properties": {
-                "display_name": {
-                    "type": "string",
-                    "maxLength": 40,
-                    "minLength": 2
-                }
-            }
-        },
-        "models.CreateBankAccountRequest": {
+        "models.TransactionSplit": {
             "type": "object",
-            "required": [
-                "account_type",
-                "bank_name",
-                "currency",
-                "name"
-            ],
             "properties": {
-                "account_type": {
-                    "type": "string",
-                    "enum": [
-                        "cash",
-                        "debit",
-                        "credit",
-                        "savings"
+                "amount": {
+                    "description": "знак как у транзакции",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
                     ]
                 },
-                "bank_name": {
-                    "type": "string",
-                    "maxLength": 40,
-                    "minLength": 2
+                "category_id": {
+                    "type": "integer"
                 },
-                "currency": {
-                    "type": "string",
-                    "enum": [
-                        "KZT",
-                        "USD",
-                        "EUR",
-                        "RUB"
-                    ]
+                "description": {
+                    "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 40,
-                    "minLength": 2
+                "id": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateBudgetRequest": {
+        "models.TransactionSplitRequest": {
             "type": "object",
             "required": [
                 "amount",
-                "budget_name",
-                "category_id",
-                "month",
-                "year"
+                "category_id"
             ],
             "properties": {
                 "amount": {
-                    "description": "Планируемая сумма",
-                    "type": "number"
-                },
-                "budget_name": {
-                    "description": "\"Продукты на октябрь\"",
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 2
+                    "$ref": "#/definitions/models.Money"
                 },
                 "category_id": {
-                    "description": "ID категории",
                     "type": "integer"
                 },
-                "month": {
-                    "description": "Месяц (1-12)",
-                    "type": "integer",
-                    "maximum": 12,
-                    "minimum": 1
-                },
-                "year": {
-                    "description": "Год",
-                    "type": "integer",
-                    "minimum": 2020
-                }
-            }
-        },
-        "models.CreateCategoryRequest": {
-            "type": "object",
-            "required": [
-                "color",
-                "icon",
-                "name",
-                "type"
-            ],
-            "properties": {
-                "color": {
-                    "type": "string"
-                },
-                "icon": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 2
-                },
-                "type": {
+                "description": {
                     "type": "string",
-                    "enum": [
-                        "income",
-                        "expense"
-                    ]
+                    "maxLength": 255
                 }
             }
         },
-        "models.CreateTransactionRequest": {
+        "models.TransferRequest": {
             "type": "object",
             "required": [
                 "amount",
-                "bank_account_id",
                 "description",
-                "transaction_type"
+                "from_account_id",
+                "to_account_id"
             ],
             "properties": {
                 "amount": {
-                    "description": "Сумма транзакции",
-                    "type": "number"
-                },
-                "bank_account_id": {
-                    "description": "ID банковского счета",
-                    "type": "integer"
-                },
-                "category_id": {
-                    "description": "ID категории (может быть null)",
-                    "type": "integer"
+                    "description": "Сумма перевода в валюте счета-источника",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
                 },
                 "description": {
-                    "description": "Описание транзакции",
+                    "description": "Описание перевода",
                     "type": "string",
                     "maxLength": 255,
                     "minLength": 1
                 },
-                "transaction_type": {
-                    "description": "Тип: доход или расход",
-                    "type": "string",
-                    "enum": [
-                        "income",
-                        "expense"
+                "from_account_id": {
+                    "description": "Откуда переводим",
+                    "type": "integer"
+                },
+                "rate": {
+                    "description": "Курс: сколько единиц валюты получателя за единицу валюты источника",
+                    "type": "number"
+                },
+                "received_amount": {
+                    "description": "Или сумма зачисления в валюте получателя",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
                     ]
+                },
+                "to_account_id": {
+                    "description": "Куда переводим",
+                    "type": "integer"
                 }
             }
         },
-        "models.TransactionResponse": {
+        "models.TransferResult": {
             "type": "object",
             "properties": {
                 "amount": {
-                    "type": "number"
-                },
-                "bank_account_id": {
-                    "type": "integer"
+                    "description": "Списано со счета-источника",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
                 },
-                "category_id": {
+                "from_account_id": {
                     "type": "integer"
                 },
-                "created_at": {
+                "from_currency": {
                     "type": "string"
                 },
-                "date": {
-                    "type": "string"
+                "rate": {
+                    "description": "Фактический курс received_amount / amount",
+                    "type": "number"
                 },
-                "description": {
+                "rate_source": {
+                    "description": "\"same_currency\", \"request\", \"received_amount\", \"stored\"",
                     "type": "string"
                 },
-                "id": {
+                "received_amount": {
+                    "description": "Зачислено на счет получателя",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "to_account_id": {
                     "type": "integer"
                 },
-                "transaction_type": {
+                "to_currency": {
                     "type": "string"
+                }
+            }
+        },
+        "models.UpdateAccountRequest": {
+            "type": "object",
+            "required": [
+                "display_name",
+                "timezone"
+            ],
+            "properties": {
+                "base_currency": {
+                    "description": "не передана - не меняется",
+                    "type": "string",
+                    "enum": [
+                        "KZT",
+                        "USD",
+                        "EUR",
+                        "RUB"
+                    ]
                 },
-                "updated_at": {
+                "display_name": {
+                    "type": "string",
+                    "maxLength": 40,
+                    "minLength": 2
+                },
+                "timezone": {
                     "type": "string"
+                },
+                "week_start_day": {
+                    "description": "не передан - не меняется",
+                    "type": "integer",
+                    "maximum": 6,
+                    "minimum": 0
                 }
             }
         },
-        "models.TransferRequest": {
+        "models.UpdateBudgetRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "budget_name"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "лимит (больше нуля) или ориентир конверта",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Money"
+                        }
+                    ]
+                },
+                "budget_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2
+                },
+                "rollover": {
+                    "description": "не передан - не меняется",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.UpdateTransactionRequest": {
             "type": "object",
             "required": [
                 "amount",
                 "description",
-                "from_account_id",
-                "to_account_id"
+                "transaction_type"
             ],
             "properties": {
                 "amount": {
-                    "description": "Сумма перевода",
-                    "type": "number"
+                    "$ref": "#/definitions/models.Money"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "\"2024-10-01\" или \"2024-10-01T15:04:05\" в таймзоне аккаунта, RFC3339; если нет - дата не меняется",
+                    "type": "string"
                 },
                 "description": {
-                    "description": "Описание перевода",
                     "type": "string",
                     "maxLength": 255,
                     "minLength": 1
                 },
-                "from_account_id": {
-                    "description": "Откуда переводим",
-                    "type": "integer"
+                "is_planned": {
+                    "description": "false - провести запланированную транзакцию; если нет - не меняется",
+                    "type": "boolean"
                 },
-                "to_account_id": {
-                    "description": "Куда переводим",
-                    "type": "integer"
+                "splits": {
+                    "description": "если нет - разбивка сохраняется, [] - удаляется",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionSplitRequest"
+                    }
+                },
+                "transaction_type": {
+                    "type": "string",
+                    "enum": [
+                        "income",
+                        "expense"
+                    ]
                 }
             }
         }
@@ -71,7 +71,7 @@ func (c *Consumer) ConsumeTransactionCreated() error {
 			d.Nack(false, false)
 			continue
 		}
-		log.Printf("[Consumer]  Received transaction: ID=%d, UserID=%s, Amount=%.2f",
+		log.Printf("[Consumer]  Received transaction: ID=%d, UserID=%s, Amount=%s",
 			event.TransactionID, event.UserID, event.Amount)
 		// надо добавить транзакцию
 		if err := c.budgetService.CheckBudgetAfterTransaction(event); err != nil {
@@ -161,7 +161,7 @@ func (c *Consumer) ConsumeBudgetExceeded() error {
 			d.Nack(false, false)
 			continue
 		}
-		log.Printf("[Consumer]  Budget exceeded: UserID=%s, BudgetID=%d, Excess=%.2f",
+		log.Printf("[Consumer]  Budget exceeded: UserID=%s, BudgetID=%d, Excess=%s",
 			event.UserID, event.BudgetID, event.ExcessAmount)
 
 		if err := c.notificationService.HandleBudgetExceeded(event); err != nil {
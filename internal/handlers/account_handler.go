@@ -5,6 +5,7 @@ import (
 	"justTest/internal/services"
 	"justTest/internal/utils"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,6 +18,21 @@ func NewAccountHandler(accountService *services.AccountService) *AccountHandler
 	return &AccountHandler{accountService: accountService}
 }
 
+func accountToResponse(account *models.Account) models.AccountResponse {
+	return models.AccountResponse{
+		ID:           account.ID,
+		UserID:       account.UserID,
+		DisplayName:  account.DisplayName,
+		Name:         account.Name,
+		Timezone:     account.Timezone,
+		BaseCurrency: account.BaseCurrency,
+		WeekStartDay: account.WeekStartDay,
+		IsActive:     account.IsActive,
+		CreatedAt:    account.CreatedAt,
+		UpdatedAt:    account.UpdatedAt,
+	}
+}
+
 // CreateAccount POST /api/v1/accounts
 // CreateAccount godoc
 // @Summary Create a new account
@@ -59,16 +75,7 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
 		"message": "Account created successfully",
-		"data": models.AccountResponse{
-			ID:          account.ID,
-			UserID:      account.UserID,
-			DisplayName: account.DisplayName,
-			Name:        account.Name,
-			Timezone:    account.Timezone,
-			IsActive:    account.IsActive,
-			CreatedAt:   account.CreatedAt,
-			UpdatedAt:   account.UpdatedAt,
-		},
+		"data":    accountToResponse(account),
 	})
 }
 
@@ -105,52 +112,126 @@ func (h *AccountHandler) GetAccount(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data": models.AccountResponse{
-			ID:          account.ID,
-			UserID:      account.UserID,
-			DisplayName: account.DisplayName,
-			Name:        account.Name,
-			Timezone:    account.Timezone,
-			IsActive:    account.IsActive,
-			CreatedAt:   account.CreatedAt,
-			UpdatedAt:   account.UpdatedAt,
-		},
+		"data":    accountToResponse(account),
 	})
 }
 
-//func (h *AccountHandler) GetAccountSummary(c *gin.Context) {
-//	userID, exists := c.Get("user_id")
-//	if !exists {
-//		c.JSON(http.StatusUnauthorized, gin.H{
-//
-//			"success": false,
-//			"error":   "unauthorized",
-//		})
-//		return
-//	}
-//	userIDInt, ok := userID.(int64)
-//	if !ok {
-//		c.JSON(http.StatusUnauthorized, gin.H{
-//			"success": false,
-//			"error":   "invalid user id format",
-//		})
-//		return
-//	}
-//	account, err := h.accountService.GetUserAccount(userIDInt)
-//	if err != nil {
-//		if err.Error() == "Account not found" {
-//			c.JSON(http.StatusNotFound, gin.H{
-//				"success": false,
-//				"error":   "Account not found",
-//			})
-//			return
-//		}
-//		c.JSON(http.StatusInternalServerError, gin.H{"success": false,
-//			"error": "failed to get account",
-//		})
-//	}
-//}
+// GetNetWorth godoc
+// @Summary Get net worth
+// @Description Get balances of all active bank accounts at the end of a day converted to the account base currency, with per-currency subtotals and the rates used
+// @Tags accounts
+// @Produce json
+// @Param date query string false "Date (YYYY-MM-DD) in the account timezone, today by default"
+// @Success 200 {object} models.NetWorth
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /account/net-worth [get]
+func (h *AccountHandler) GetNetWorth(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	var date time.Time
+	if value := c.Query("date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "invalid date parameter, expected YYYY-MM-DD",
+			})
+			return
+		}
+		date = parsed
+	}
+	netWorth, err := h.accountService.GetNetWorth(userID, date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false,
+			"error":   "failed to get net worth",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    netWorth,
+	})
+}
+
+// GetAccountSummary godoc
+// @Summary Get account summary
+// @Description Get balances per currency and per active bank account, income and expenses for the current month per currency and the current month budget statuses in one request
+// @Tags accounts
+// @Produce json
+// @Success 200 {object} models.AccountSummary
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Account not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /account/summary [get]
+func (h *AccountHandler) GetAccountSummary(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	summary, err := h.accountService.GetAccountSummary(userID)
+	if err != nil {
+		if err.Error() == "Account not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Account not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false,
+			"error":   "failed to get account summary",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    summary,
+	})
+}
 
-// UpdateAccount PUT /api/v1/accounts
+// UpdateAccount godoc
+// @Summary Update account
+// @Description Update the display name, the timezone (IANA name, e.g. Asia/Almaty), the first day of the week (0 - Sunday, 1 - Monday) and the base currency. Month and week boundaries in budgets and reports are calculated in this timezone; net worth, the monthly report and budgets are calculated in the base currency
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param request body models.UpdateAccountRequest true "Account profile"
+// @Success 200 {object} models.AccountResponse
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /account [put]
+func (h *AccountHandler) UpdateAccount(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	var req models.UpdateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false,
+			"error": "invalid", "details": err.Error()})
+		return
+	}
+	account, err := h.accountService.UpdateAccount(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false,
+			"error":   "failed to update account",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Account updated",
+		"data":    accountToResponse(account),
+	})
+}
 
 // GetAccountByID GET /api/v1/accounts/:id (для админа или внутренних нужд)
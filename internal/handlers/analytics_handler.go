@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"justTest/internal/services"
+	"justTest/internal/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsHandler struct {
+	analyticsService *services.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetMonthlyReport godoc
+// @Summary Get monthly report
+// @Description Get total income, expense, spending by category and top expenses for a month (transfers excluded)
+// @Tags analytics
+// @Produce json
+// @Param year query int true "Year" default(2024)
+// @Param month query int true "Month (1-12)" default(10)
+// @Param limit query int false "Number of top expenses" default(5)
+// @Success 200 {object} models.MonthlyReport
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /analytics/monthly [get]
+func (h *AnalyticsHandler) GetMonthlyReport(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	year, err := strconv.Atoi(c.Query("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid year parameter",
+		})
+		return
+	}
+	month, err := strconv.Atoi(c.Query("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid month parameter",
+		})
+		return
+	}
+	if month < 1 || month > 12 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "month must be between 1 and 12",
+		})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid limit parameter",
+		})
+		return
+	}
+
+	report, err := h.analyticsService.GetMonthlyReport(userID, year, month, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+		"message": "monthly report",
+	})
+}
+
+// GetCategorySpending godoc
+// @Summary Get spending by category for a date range
+// @Description Get expense totals and percentages per category, one set per currency
+// @Tags analytics
+// @Produce json
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string true "End date, inclusive (YYYY-MM-DD)"
+// @Success 200 {array} models.CategorySpending
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /analytics/categories [get]
+func (h *AnalyticsHandler) GetCategorySpending(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	startDate, endDate, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	spending, err := h.analyticsService.GetCategorySpending(userID, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    spending,
+		"message": "category spending",
+	})
+}
+
+// GetIncomeVsExpenses godoc
+// @Summary Get income vs expenses for a date range
+// @Description Get income, expense, net income and savings rate per currency, optionally as a day/week/month time series
+// @Tags analytics
+// @Produce json
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string true "End date, inclusive (YYYY-MM-DD)"
+// @Param group_by query string false "Time series grouping" Enums(day, week, month)
+// @Success 200 {array} models.IncomeExpenseReport
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /analytics/income-expense [get]
+func (h *AnalyticsHandler) GetIncomeVsExpenses(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	startDate, endDate, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	groupBy := c.Query("group_by")
+	if groupBy != "" && groupBy != "day" && groupBy != "week" && groupBy != "month" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "group_by must be one of day, week, month",
+		})
+		return
+	}
+
+	reports, err := h.analyticsService.GetIncomeVsExpenses(userID, startDate, endDate, groupBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    reports,
+		"message": "income vs expenses",
+	})
+}
+
+// GetCashFlowForecast godoc
+// @Summary Get cash-flow forecast
+// @Description Get projected month-end balances: current balances of active bank accounts plus planned transactions, including installment payments, converted to the base currency at today's rate
+// @Tags analytics
+// @Produce json
+// @Param months query int false "Months to forecast, starting with the current one (1-24, default 6)"
+// @Success 200 {object} models.CashFlowForecast
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /analytics/cash-flow [get]
+func (h *AnalyticsHandler) GetCashFlowForecast(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	months, ok := monthsParam(c)
+	if !ok {
+		return
+	}
+	forecast, err := h.analyticsService.GetCashFlowForecast(userID, months)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    forecast,
+		"message": "cash-flow forecast",
+	})
+}
+
+// parseDateRange читает start_date и end_date (YYYY-MM-DD), при ошибке сам отвечает 400
+func parseDateRange(c *gin.Context) (time.Time, time.Time, bool) {
+	startDate, err := time.Parse("2006-01-02", c.Query("start_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid start_date parameter, expected YYYY-MM-DD",
+		})
+		return time.Time{}, time.Time{}, false
+	}
+	endDate, err := time.Parse("2006-01-02", c.Query("end_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid end_date parameter, expected YYYY-MM-DD",
+		})
+		return time.Time{}, time.Time{}, false
+	}
+	if endDate.Before(startDate) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "end_date must not be before start_date",
+		})
+		return time.Time{}, time.Time{}, false
+	}
+	return startDate, endDate, true
+}
+
+// monthsParam читает необязательный параметр months (0, если его нет), при ошибке сам отвечает 400
+func monthsParam(c *gin.Context) (int, bool) {
+	value := c.Query("months")
+	if value == "" {
+		return 0, true
+	}
+	months, err := strconv.Atoi(value)
+	if err != nil || months < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid months parameter, expected a positive number",
+		})
+		return 0, false
+	}
+	return months, true
+}
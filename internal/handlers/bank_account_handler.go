@@ -24,7 +24,7 @@ func NewBankAccountHandler(bankAccService *services.BankAccService) *BankAccount
 
 // CreateBankAccount godoc
 // @Summary Create a new bank account
-// @Description Create a new bank account for the authenticated user
+// @Description Create a new bank account for the authenticated user. An optional opening balance of an existing account is stored as an opening_balance transaction that is excluded from income, expenses and budgets
 // @Tags bank-accounts
 // @Accept json
 // @Produce json
@@ -51,12 +51,19 @@ func (h *BankAccountHandler) CreateBankAccount(c *gin.Context) {
 		})
 		return
 	}
+	var openingDate string
+	if req.OpeningDate != nil {
+		openingDate = *req.OpeningDate
+	}
 	bankAccount, err := h.BankAccService.CreateBankAccount(
 		userID,
 		req.Name,
 		req.Currency,
 		req.AccountType,
 		req.BankName,
+		req.IBAN,
+		req.OpeningBalance,
+		openingDate,
 	)
 	if err != nil {
 		if err.Error() == "bank account already exists" {
@@ -6,6 +6,7 @@ import (
 	"justTest/internal/utils"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,7 +23,7 @@ func NewBudgetHandler(budgetService *services.BudgetService) *BudgetHandler {
 
 // CreateBudget godoc
 // @Summary Create a new budget
-// @Description Create a new monthly budget for a category
+// @Description Create a weekly, monthly or yearly budget for a category (category_id), a set of categories (category_ids) or all expense categories of the account (all_categories). The period is the calendar week (starting on the account week start day), month or year containing date, or the month/year from year and month. Mode limit uses amount as the period limit, mode envelope makes available what is assigned to it. With rollover the remainder or overspend carries into the next period
 // @Tags budgets
 // @Accept json
 // @Produce json
@@ -64,12 +65,14 @@ func (h *BudgetHandler) CreateBudget(c *gin.Context) {
 }
 
 // GetBudgets godoc
-// @Summary Get all budgets for a month
-// @Description Get all budgets with status for a specific month
+// @Summary Get budgets for a date
+// @Description Get all budgets whose period contains the reference date, with status over each budget's own period
 // @Tags budgets
 // @Produce json
-// @Param year query int true "Year" default(2024)
-// @Param month query int true "Month (1-12)" default(10)
+// @Param date query string false "Reference date YYYY-MM-DD, defaults to today"
+// @Param period query string false "Budget period: weekly, monthly or yearly, all periods if empty"
+// @Param year query int false "Year, used with month when date is not set"
+// @Param month query int false "Month (1-12), used with year when date is not set"
 // @Success 200 {array} models.BudgetWithStatus
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
@@ -81,33 +84,12 @@ func (h *BudgetHandler) GetBudgets(c *gin.Context) {
 	if !ok {
 		return
 	}
-	yearStr := c.Query("year")
-	monthStr := c.Query("month")
-	year, err := strconv.Atoi(yearStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "invalid year parameter",
-		})
-		return
-	}
-	month, err := strconv.Atoi(monthStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "invalid month parameter",
-		})
-		return
-	}
-	if month < 1 || month > 12 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "month must be between 1 and 12",
-		})
+	period, date, ok := budgetPeriodParams(c)
+	if !ok {
 		return
 	}
 
-	budgets, err := h.budgetService.GetBudgets(userID, year, month)
+	budgets, err := h.budgetService.GetBudgets(userID, period, date)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -124,12 +106,14 @@ func (h *BudgetHandler) GetBudgets(c *gin.Context) {
 
 // GetBudgetStatus godoc
 // @Summary Get budget status for a category
-// @Description Get budget status (spent/remaining) for a specific category and month
+// @Description Get budget status (spent/remaining) for a budget covering the category whose period contains the reference date: a category budget, a multi-category budget or an all-categories budget. Without period the shortest period budget is used, a single category budget before broader ones
 // @Tags budgets
 // @Produce json
 // @Param category_id path int true "Category ID"
-// @Param year query int true "Year" default(2024)
-// @Param month query int true "Month (1-12)" default(10)
+// @Param date query string false "Reference date YYYY-MM-DD, defaults to today"
+// @Param period query string false "Budget period: weekly, monthly or yearly, all periods if empty"
+// @Param year query int false "Year, used with month when date is not set"
+// @Param month query int false "Month (1-12), used with year when date is not set"
 // @Success 200 {object} models.BudgetStatus
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
@@ -151,77 +135,333 @@ func (h *BudgetHandler) GetBudgetStatus(c *gin.Context) {
 		})
 		return
 	}
-	yearStr := c.Query("year")
-	monthStr := c.Query("month")
-	year, err := strconv.Atoi(yearStr)
+	period, date, ok := budgetPeriodParams(c)
+	if !ok {
+		return
+	}
+
+	budget, err := h.budgetService.GetBudgetStatus(userID, categoryId, period, date)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
+		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"error":   "invalid year parameter",
+			"error":   err.Error(),
 		})
 		return
 	}
-	month, err := strconv.Atoi(monthStr)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    budget,
+		"message": "budget status",
+	})
+
+}
+
+// GetBudgetSummary godoc
+// @Summary Get budget summary for a date
+// @Description Get overall budget summary (total planned vs spent) for the budgets whose period contains the reference date
+// @Tags budgets
+// @Produce json
+// @Param date query string false "Reference date YYYY-MM-DD, defaults to today"
+// @Param period query string false "Budget period: weekly, monthly or yearly, all periods if empty"
+// @Param year query int false "Year, used with month when date is not set"
+// @Param month query int false "Month (1-12), used with year when date is not set"
+// @Success 200 {object} models.BudgetSummary
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /budgets/summary [get]
+func (h *BudgetHandler) GetBudgetSummary(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+
+	}
+
+	period, date, ok := budgetPeriodParams(c)
+	if !ok {
+		return
+	}
+
+	budget, err := h.budgetService.GetBudgetSummary(userID, period, date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "something bad happened",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    budget,
+		"message": "budget summary",
+	})
+
+}
+
+// UpdateBudget godoc
+// @Summary Update a budget
+// @Description Update the name, the amount and the rollover flag of a budget. The period, dates and mode cannot be changed
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Param request body models.UpdateBudgetRequest true "Budget update request"
+// @Success 200 {object} models.Budget
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /budgets/{id} [put]
+func (h *BudgetHandler) UpdateBudget(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	budgetID, ok := budgetIDParam(c)
+	if !ok {
+		return
+	}
+	var req models.UpdateBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error()})
+		return
+	}
+	budget, err := h.budgetService.UpdateBudget(userID, budgetID, &req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"error":   "invalid month parameter",
+			"error":   err.Error(),
 		})
 		return
 	}
-	if month < 1 || month > 12 {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    budget,
+		"message": "budget updated",
+	})
+}
+
+// DeleteBudget godoc
+// @Summary Delete a budget
+// @Description Delete a budget. Money assigned to an envelope returns to "to be assigned", transactions are not affected
+// @Tags budgets
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /budgets/{id} [delete]
+func (h *BudgetHandler) DeleteBudget(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	budgetID, ok := budgetIDParam(c)
+	if !ok {
+		return
+	}
+	if err := h.budgetService.DeleteBudget(userID, budgetID); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"error":   "month must be between 1 and 12",
+			"error":   err.Error(),
 		})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "budget deleted",
+	})
+}
 
-	budget, err := h.budgetService.GetBudgetStatus(userID, categoryId, year, month)
+// CopyBudgets godoc
+// @Summary Copy monthly budgets to another month
+// @Description Clone every monthly budget of one month into another month, optionally adjusting amounts by a percentage. Budgets whose set of categories already has a monthly budget in the target month are skipped
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param request body models.CopyBudgetsRequest true "Copy request"
+// @Success 201 {object} models.CopyBudgetsResult
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /budgets/copy [post]
+func (h *BudgetHandler) CopyBudgets(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	var req models.CopyBudgetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error()})
+		return
+	}
+	result, err := h.budgetService.CopyBudgets(userID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
 			"error":   err.Error(),
 		})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{
+	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
-		"data":    budget,
-		"message": "budget status",
+		"data":    result,
+		"message": "budgets copied",
 	})
+}
 
+// AssignBudget godoc
+// @Summary Assign money to an envelope
+// @Description Assign money to an envelope budget in the account base currency. A negative amount returns money to "to be assigned" and cannot exceed what is left in the envelope
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param request body models.AssignBudgetRequest true "Assignment"
+// @Success 200 {object} models.BudgetStatus
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /budgets/envelopes/assign [post]
+func (h *BudgetHandler) AssignBudget(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	var req models.AssignBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error()})
+		return
+	}
+	status, err := h.budgetService.AssignBudget(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    status,
+		"message": "money assigned",
+	})
 }
 
-// GetBudgetSummary godoc
-// @Summary Get budget summary for a month
-// @Description Get overall budget summary (total planned vs spent) for a month
+// MoveBudget godoc
+// @Summary Move money between envelopes
+// @Description Move money from one envelope budget to another, no more than is left in the source envelope. Returns statuses of both envelopes
 // @Tags budgets
+// @Accept json
 // @Produce json
-// @Param year query int true "Year" default(2024)
-// @Param month query int true "Month (1-12)" default(10)
-// @Success 200 {object} models.BudgetSummary
+// @Param request body models.MoveBudgetRequest true "Move"
+// @Success 200 {array} models.BudgetStatus
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
-// @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Security BearerAuth
-// @Router /budgets/summary [get]
-func (h *BudgetHandler) GetBudgetSummary(c *gin.Context) {
+// @Router /budgets/envelopes/move [post]
+func (h *BudgetHandler) MoveBudget(c *gin.Context) {
 	userID, ok := utils.GetUserID(c)
 	if !ok {
 		return
+	}
+	var req models.MoveBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error()})
+		return
+	}
+	statuses, err := h.budgetService.MoveBudget(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    statuses,
+		"message": "money moved",
+	})
+}
 
+// GetAssignments godoc
+// @Summary Get envelope assignments
+// @Description Get the history of money assigned to and moved from an envelope budget, newest first
+// @Tags budgets
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Success 200 {array} models.BudgetAssignment
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Envelope not found"
+// @Security BearerAuth
+// @Router /budgets/envelopes/{id}/assignments [get]
+func (h *BudgetHandler) GetAssignments(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	budgetID, ok := budgetIDParam(c)
+	if !ok {
+		return
 	}
+	assignments, err := h.budgetService.GetAssignments(userID, budgetID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    assignments,
+	})
+}
 
+// budgetPeriodParams разбирает period и дату из запроса: date (YYYY-MM-DD) или year и month - первый день месяца,
+// без них - нулевая дата (сегодня в таймзоне аккаунта)
+func budgetPeriodParams(c *gin.Context) (string, time.Time, bool) {
+	period := c.Query("period")
+	if period != "" && period != "weekly" && period != "monthly" && period != "yearly" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "period must be weekly, monthly or yearly",
+		})
+		return "", time.Time{}, false
+	}
+	if value := c.Query("date"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "invalid date parameter, expected YYYY-MM-DD",
+			})
+			return "", time.Time{}, false
+		}
+		return period, date, true
+	}
 	yearStr := c.Query("year")
 	monthStr := c.Query("month")
+	if yearStr == "" && monthStr == "" {
+		return period, time.Time{}, true
+	}
 	year, err := strconv.Atoi(yearStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
 			"error":   "invalid year parameter",
 		})
-		return
+		return "", time.Time{}, false
 	}
 	month, err := strconv.Atoi(monthStr)
 	if err != nil {
@@ -229,28 +469,26 @@ func (h *BudgetHandler) GetBudgetSummary(c *gin.Context) {
 			"success": false,
 			"error":   "invalid month parameter",
 		})
-		return
+		return "", time.Time{}, false
 	}
 	if month < 1 || month > 12 {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
 			"error":   "month must be between 1 and 12",
 		})
-		return
+		return "", time.Time{}, false
 	}
+	return period, time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC), true
+}
 
-	budget, err := h.budgetService.GetBudgetSummary(userID, year, month)
+func budgetIDParam(c *gin.Context) (int64, bool) {
+	budgetID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"error":   "something bad happened",
+			"error":   "invalid budget id parameter",
 		})
-		return
+		return 0, false
 	}
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    budget,
-		"message": "budget summary",
-	})
-
+	return budgetID, true
 }
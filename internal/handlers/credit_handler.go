@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"justTest/internal/models"
+	"justTest/internal/services"
+	"justTest/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreditHandler struct {
+	creditService *services.CreditService
+}
+
+func NewCreditHandler(creditService *services.CreditService) *CreditHandler {
+	return &CreditHandler{
+		creditService: creditService,
+	}
+}
+
+// SetCreditTerms godoc
+// @Summary Set credit account terms
+// @Description Set the credit limit, statement closing day, payment due day, annual interest rate and minimum payment percent of a credit bank account. Days after the end of a month mean its last day
+// @Tags credit
+// @Accept json
+// @Produce json
+// @Param bank_account_id path int true "Bank account ID"
+// @Param request body models.CreditTermsRequest true "Credit terms"
+// @Success 200 {object} models.CreditTerms
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /bankAccounts/{bank_account_id}/credit [put]
+func (h *CreditHandler) SetCreditTerms(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	bankAccountID, err := strconv.ParseInt(c.Param("bank_account_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid bank account id",
+		})
+		return
+	}
+	var req models.CreditTermsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	terms, err := h.creditService.SetCreditTerms(userID, bankAccountID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to set credit terms",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    terms,
+		"message": "credit terms saved",
+	})
+}
+
+// GetCreditStatus godoc
+// @Summary Get credit account status
+// @Description Get available credit, the last closed statement balance, payments since the statement, the remaining minimum payment, the payment due date and the estimated interest if the statement is not repaid
+// @Tags credit
+// @Produce json
+// @Param bank_account_id path int true "Bank account ID"
+// @Success 200 {object} models.CreditStatus
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /bankAccounts/{bank_account_id}/credit [get]
+func (h *CreditHandler) GetCreditStatus(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	bankAccountID, err := strconv.ParseInt(c.Param("bank_account_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid bank account id",
+		})
+		return
+	}
+	status, err := h.creditService.GetCreditStatus(userID, bankAccountID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to get credit status",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    status,
+	})
+}
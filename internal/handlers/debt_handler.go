@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"justTest/internal/models"
+	"justTest/internal/services"
+	"justTest/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DebtHandler struct {
+	debtService *services.DebtService
+}
+
+func NewDebtHandler(debtService *services.DebtService) *DebtHandler {
+	return &DebtHandler{
+		debtService: debtService,
+	}
+}
+
+// CreateCounterparty godoc
+// @Summary Create a counterparty
+// @Description Create a person money is lent to or borrowed from. Names are unique per user, case-insensitive
+// @Tags debts
+// @Accept json
+// @Produce json
+// @Param request body models.CreateCounterpartyRequest true "Counterparty"
+// @Success 201 {object} models.Counterparty
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /counterparties [post]
+func (h *DebtHandler) CreateCounterparty(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	var req models.CreateCounterpartyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	counterparty, err := h.debtService.CreateCounterparty(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to create counterparty",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    counterparty,
+		"message": "counterparty created",
+	})
+}
+
+// GetCounterparties godoc
+// @Summary Get counterparties
+// @Description Get all counterparties of the user ordered by name
+// @Tags debts
+// @Produce json
+// @Success 200 {array} models.Counterparty
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /counterparties [get]
+func (h *DebtHandler) GetCounterparties(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	counterparties, err := h.debtService.GetCounterparties(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to get counterparties",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    counterparties,
+	})
+}
+
+// DeleteCounterparty godoc
+// @Summary Delete a counterparty
+// @Description Delete a counterparty without debts
+// @Tags debts
+// @Produce json
+// @Param id path int true "Counterparty ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /counterparties/{id} [delete]
+func (h *DebtHandler) DeleteCounterparty(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	counterpartyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid counterparty id",
+		})
+		return
+	}
+	if err := h.debtService.DeleteCounterparty(userID, counterpartyID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to delete counterparty",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "counterparty deleted",
+	})
+}
+
+// CreateDebt godoc
+// @Summary Record a debt
+// @Description Record money lent to or borrowed from a counterparty. With bank_account_id a debt transaction moves the money on that account; debt transactions change balances but are excluded from income, expenses and budgets
+// @Tags debts
+// @Accept json
+// @Produce json
+// @Param request body models.CreateDebtRequest true "Debt"
+// @Success 201 {object} models.Debt
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /debts [post]
+func (h *DebtHandler) CreateDebt(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	var req models.CreateDebtRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	debt, err := h.debtService.CreateDebt(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to create debt",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    debt,
+		"message": "debt created",
+	})
+}
+
+// GetDebts godoc
+// @Summary Get debts
+// @Description Get outstanding debts of the user, newest first
+// @Tags debts
+// @Produce json
+// @Param counterparty_id query int false "Only debts with this counterparty"
+// @Param include_settled query bool false "Include fully repaid debts"
+// @Success 200 {array} models.Debt
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /debts [get]
+func (h *DebtHandler) GetDebts(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	var counterpartyID *int64
+	if value := c.Query("counterparty_id"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "invalid counterparty_id parameter",
+			})
+			return
+		}
+		counterpartyID = &parsed
+	}
+	includeSettled := c.Query("include_settled") == "true"
+	debts, err := h.debtService.GetDebts(userID, counterpartyID, includeSettled)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to get debts",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    debts,
+	})
+}
+
+// GetDebtBalances godoc
+// @Summary Get outstanding debt balances
+// @Description Get outstanding amounts per counterparty and currency: owed to the user, owed by the user and the net
+// @Tags debts
+// @Produce json
+// @Success 200 {array} models.DebtBalance
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /debts/balances [get]
+func (h *DebtHandler) GetDebtBalances(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	balances, err := h.debtService.GetBalances(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to get debt balances",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    balances,
+	})
+}
+
+// GetDebt godoc
+// @Summary Get a debt
+// @Description Get a debt with its repayments
+// @Tags debts
+// @Produce json
+// @Param id path int true "Debt ID"
+// @Success 200 {object} models.Debt
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /debts/{id} [get]
+func (h *DebtHandler) GetDebt(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	debtID, ok := debtIDParam(c)
+	if !ok {
+		return
+	}
+	debt, err := h.debtService.GetDebt(userID, debtID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to get debt",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    debt,
+	})
+}
+
+// DeleteDebt godoc
+// @Summary Delete a debt
+// @Description Delete a debt, its repayments and their bank account transactions
+// @Tags debts
+// @Produce json
+// @Param id path int true "Debt ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /debts/{id} [delete]
+func (h *DebtHandler) DeleteDebt(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	debtID, ok := debtIDParam(c)
+	if !ok {
+		return
+	}
+	if err := h.debtService.DeleteDebt(userID, debtID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to delete debt",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "debt deleted",
+	})
+}
+
+// AddRepayment godoc
+// @Summary Repay a debt
+// @Description Record a partial or full repayment, not more than the outstanding amount. With bank_account_id (in the debt currency) a debt transaction moves the money on that account
+// @Tags debts
+// @Accept json
+// @Produce json
+// @Param id path int true "Debt ID"
+// @Param request body models.DebtRepaymentRequest true "Repayment"
+// @Success 201 {object} models.DebtRepayment
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /debts/{id}/repayments [post]
+func (h *DebtHandler) AddRepayment(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	debtID, ok := debtIDParam(c)
+	if !ok {
+		return
+	}
+	var req models.DebtRepaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	repayment, err := h.debtService.AddRepayment(userID, debtID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to add repayment",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    repayment,
+		"message": "repayment added",
+	})
+}
+
+func debtIDParam(c *gin.Context) (int64, bool) {
+	debtID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid debt id",
+		})
+		return 0, false
+	}
+	return debtID, true
+}
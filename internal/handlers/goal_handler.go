@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"justTest/internal/models"
+	"justTest/internal/services"
+	"justTest/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GoalHandler struct {
+	goalService *services.GoalService
+}
+
+func NewGoalHandler(goalService *services.GoalService) *GoalHandler {
+	return &GoalHandler{
+		goalService: goalService,
+	}
+}
+
+// CreateGoal godoc
+// @Summary Create a savings goal
+// @Description Create a goal with a target amount and date. With bank_account_id the progress is the balance of that savings bank account, otherwise contributions are logged manually and currency is required
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Param request body models.CreateGoalRequest true "Goal"
+// @Success 201 {object} models.Goal
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /goals [post]
+func (h *GoalHandler) CreateGoal(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	var req models.CreateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	goal, err := h.goalService.CreateGoal(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to create goal",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    goal,
+		"message": "goal created",
+	})
+}
+
+// GetGoals godoc
+// @Summary Get savings goals
+// @Description Get all goals of the user with progress, the monthly contribution needed to reach the target date and the projected completion date based on the average contribution of the last months
+// @Tags goals
+// @Produce json
+// @Param months query int false "Months for the average contribution (1-24, default 3)"
+// @Success 200 {array} models.GoalProgress
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /goals [get]
+func (h *GoalHandler) GetGoals(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	months, ok := monthsParam(c)
+	if !ok {
+		return
+	}
+	goals, err := h.goalService.GetGoals(userID, months)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to get goals",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    goals,
+	})
+}
+
+// GetGoal godoc
+// @Summary Get savings goal progress
+// @Description Get progress of a goal, the monthly contribution needed and the projected completion date
+// @Tags goals
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Param months query int false "Months for the average contribution (1-24, default 3)"
+// @Success 200 {object} models.GoalProgress
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /goals/{id} [get]
+func (h *GoalHandler) GetGoal(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	goalID, ok := goalIDParam(c)
+	if !ok {
+		return
+	}
+	months, ok := monthsParam(c)
+	if !ok {
+		return
+	}
+	progress, err := h.goalService.GetGoal(userID, goalID, months)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to get goal",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    progress,
+	})
+}
+
+// DeleteGoal godoc
+// @Summary Delete a savings goal
+// @Description Delete a goal and its contributions, the linked bank account is not changed
+// @Tags goals
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /goals/{id} [delete]
+func (h *GoalHandler) DeleteGoal(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	goalID, ok := goalIDParam(c)
+	if !ok {
+		return
+	}
+	if err := h.goalService.DeleteGoal(userID, goalID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to delete goal",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "goal deleted",
+	})
+}
+
+// AddContribution godoc
+// @Summary Add a contribution to a savings goal
+// @Description Log a contribution to a goal without a linked bank account, a negative amount is a withdrawal. Milestone notifications are sent at 25, 50, 75 and 100%
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Param request body models.GoalContributionRequest true "Contribution"
+// @Success 201 {object} models.GoalContribution
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /goals/{id}/contributions [post]
+func (h *GoalHandler) AddContribution(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	goalID, ok := goalIDParam(c)
+	if !ok {
+		return
+	}
+	var req models.GoalContributionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	contribution, err := h.goalService.AddContribution(userID, goalID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to add contribution",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    contribution,
+		"message": "contribution added",
+	})
+}
+
+// GetContributions godoc
+// @Summary Get savings goal contributions
+// @Description Get manually logged contributions of a goal, newest first
+// @Tags goals
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Success 200 {array} models.GoalContribution
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /goals/{id}/contributions [get]
+func (h *GoalHandler) GetContributions(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	goalID, ok := goalIDParam(c)
+	if !ok {
+		return
+	}
+	contributions, err := h.goalService.GetContributions(userID, goalID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to get contributions",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    contributions,
+	})
+}
+
+func goalIDParam(c *gin.Context) (int64, bool) {
+	goalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid goal id",
+		})
+		return 0, false
+	}
+	return goalID, true
+}
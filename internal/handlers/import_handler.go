@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"justTest/internal/models"
+	"justTest/internal/services"
+	"justTest/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxStatementSize - максимальный размер файла выписки
+const maxStatementSize = 10 << 20
+
+type ImportHandler struct {
+	importService *services.ImportService
+}
+
+func NewImportHandler(importService *services.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// ImportStatement godoc
+// @Summary Import a bank statement
+// @Description Import transactions from a CSV, OFX/QFX, QIF or camt.053/052 statement into a bank account. Rows with an already imported OFX FITID / camt reference or matching an existing transaction by date, amount and description are skipped
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param bank_account_id path int true "Bank account ID"
+// @Param file formData file true "Statement file"
+// @Param format formData string false "Statement format" Enums(csv, ofx, qfx, qif, camt053, camt052) default(csv)
+// @Param date_column formData string false "CSV: date column name or number (from 1), required for csv"
+// @Param amount_column formData string false "CSV: amount column name or number, negative amounts are expenses, required for csv"
+// @Param description_column formData string false "CSV: description column name or number, required for csv"
+// @Param category_column formData string false "CSV: category name column"
+// @Param date_format formData string false "Date format for csv and qif, e.g. DD.MM.YYYY"
+// @Param decimal_separator formData string false "Decimal separator" Enums(., ",")
+// @Param delimiter formData string false "CSV delimiter: , ; tab |" default(,)
+// @Success 200 {object} models.ImportSummary
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /bankAccounts/{bank_account_id}/import [post]
+func (h *ImportHandler) ImportStatement(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	bankAccountID, err := strconv.ParseInt(c.Param("bank_account_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid bank account id",
+		})
+		return
+	}
+	h.importStatement(c, userID, bankAccountID)
+}
+
+// ImportStatementByIBAN godoc
+// @Summary Import a camt statement by IBAN
+// @Description Import a camt.053 or camt.052 statement into the bank account whose IBAN matches the statement; opening and closing balances are reconciled with the account balance
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Statement file"
+// @Param format formData string true "Statement format" Enums(camt053, camt052)
+// @Success 200 {object} models.ImportSummary
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /bankAccounts/import [post]
+func (h *ImportHandler) ImportStatementByIBAN(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	format := c.PostForm("format")
+	if format != "camt053" && format != "camt052" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "matching by IBAN is supported only for camt053 and camt052",
+		})
+		return
+	}
+	h.importStatement(c, userID, 0)
+}
+
+// importStatement читает параметры и файл выписки и запускает импорт, bankAccountID = 0 - поиск счета по IBAN
+func (h *ImportHandler) importStatement(c *gin.Context, userID string, bankAccountID int64) {
+	var req models.ImportStatementRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "statement file is required",
+		})
+		return
+	}
+	if fileHeader.Size > maxStatementSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "statement file is too large",
+		})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to read statement file",
+		})
+		return
+	}
+	defer file.Close()
+
+	summary, err := h.importService.ImportStatement(userID, bankAccountID, file, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to import statement",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    summary,
+		"message": "statement imported",
+	})
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"justTest/internal/models"
+	"justTest/internal/services"
+	"justTest/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InstallmentHandler struct {
+	installmentService *services.InstallmentService
+}
+
+func NewInstallmentHandler(installmentService *services.InstallmentService) *InstallmentHandler {
+	return &InstallmentHandler{
+		installmentService: installmentService,
+	}
+}
+
+// CreateInstallmentPlan godoc
+// @Summary Create an installment plan
+// @Description Create an installment plan on a bank account. The total amount plus the fee is split into equal monthly payments starting from first_payment_date; every payment not paid yet is created as a planned expense transaction that becomes a real one on its due date
+// @Tags installments
+// @Accept json
+// @Produce json
+// @Param request body models.CreateInstallmentPlanRequest true "Installment plan"
+// @Success 201 {object} models.InstallmentPlan
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /installments [post]
+func (h *InstallmentHandler) CreateInstallmentPlan(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	var req models.CreateInstallmentPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	plan, err := h.installmentService.CreatePlan(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to create installment plan",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    plan,
+		"message": "installment plan created",
+	})
+}
+
+// GetInstallmentPlans godoc
+// @Summary Get installment plans
+// @Description Get all installment plans of the user
+// @Tags installments
+// @Produce json
+// @Success 200 {array} models.InstallmentPlan
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /installments [get]
+func (h *InstallmentHandler) GetInstallmentPlans(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	plans, err := h.installmentService.GetPlans(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to get installment plans",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    plans,
+	})
+}
+
+// GetInstallmentPlan godoc
+// @Summary Get an installment plan
+// @Description Get an installment plan with its payment schedule and the remaining amount
+// @Tags installments
+// @Produce json
+// @Param id path int true "Installment plan ID"
+// @Success 200 {object} models.InstallmentPlan
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /installments/{id} [get]
+func (h *InstallmentHandler) GetInstallmentPlan(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	planID, ok := installmentIDParam(c)
+	if !ok {
+		return
+	}
+	plan, err := h.installmentService.GetPlan(userID, planID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to get installment plan",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    plan,
+	})
+}
+
+// DeleteInstallmentPlan godoc
+// @Summary Delete an installment plan
+// @Description Delete an installment plan and its future planned payments; payments already made stay as regular transactions
+// @Tags installments
+// @Produce json
+// @Param id path int true "Installment plan ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /installments/{id} [delete]
+func (h *InstallmentHandler) DeleteInstallmentPlan(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	planID, ok := installmentIDParam(c)
+	if !ok {
+		return
+	}
+	if err := h.installmentService.DeletePlan(userID, planID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to delete installment plan",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "installment plan deleted",
+	})
+}
+
+// GetInstallmentObligations godoc
+// @Summary Get outstanding installment obligations by month
+// @Description Get installment payments not made yet grouped by month, starting with the current one. Month totals are in the base currency at today's rate
+// @Tags installments
+// @Produce json
+// @Param months query int false "Months ahead (1-60, default 12)"
+// @Success 200 {array} models.InstallmentObligations
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /installments/obligations [get]
+func (h *InstallmentHandler) GetInstallmentObligations(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	months, ok := monthsParam(c)
+	if !ok {
+		return
+	}
+	obligations, err := h.installmentService.GetObligations(userID, months)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to get installment obligations",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    obligations,
+	})
+}
+
+func installmentIDParam(c *gin.Context) (int64, bool) {
+	planID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid installment plan id",
+		})
+		return 0, false
+	}
+	return planID, true
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"justTest/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RateHandler struct {
+	rateService *services.RateService
+}
+
+func NewRateHandler(rateService *services.RateService) *RateHandler {
+	return &RateHandler{
+		rateService: rateService,
+	}
+}
+
+// GetRate godoc
+// @Summary Get a currency rate on a date
+// @Description Get the rate from one currency to another on a date: the latest stored rate not after the date, inverse rate or cross rate through a pivot currency (EUR, KZT, USD)
+// @Tags rates
+// @Produce json
+// @Param from query string true "From currency" default(USD)
+// @Param to query string true "To currency" default(KZT)
+// @Param date query string false "Date (YYYY-MM-DD), today by default"
+// @Success 200 {object} models.CurrencyRateQuote
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Rate not found"
+// @Security BearerAuth
+// @Router /rates [get]
+func (h *RateHandler) GetRate(c *gin.Context) {
+	from := c.Query("from")
+	to := c.Query("to")
+	if from == "" || to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "from and to parameters are required",
+		})
+		return
+	}
+	date := time.Now()
+	if value := c.Query("date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "invalid date parameter, expected YYYY-MM-DD",
+			})
+			return
+		}
+		date = parsed
+	}
+
+	quote, err := h.rateService.GetRate(from, to, date)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    quote,
+		"message": "currency rate",
+	})
+}
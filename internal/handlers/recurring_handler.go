@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"justTest/internal/models"
+	"justTest/internal/services"
+	"justTest/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecurringHandler struct {
+	recurringService *services.RecurringService
+}
+
+func NewRecurringHandler(recurringService *services.RecurringService) *RecurringHandler {
+	return &RecurringHandler{
+		recurringService: recurringService,
+	}
+}
+
+// CreateRecurring godoc
+// @Summary Create a recurring transaction
+// @Description Create a rule that materializes income or expense transactions daily, weekly, monthly or yearly
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Param request body models.RecurringTransactionRequest true "Recurring transaction request"
+// @Success 201 {object} models.RecurringTransaction
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /recurring [post]
+func (h *RecurringHandler) CreateRecurring(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	var req models.RecurringTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	recurring, err := h.recurringService.CreateRecurring(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    recurring,
+		"message": "recurring transaction created",
+	})
+}
+
+// GetAllRecurring godoc
+// @Summary Get recurring transactions
+// @Description Get all recurring transaction rules of the authenticated user
+// @Tags recurring
+// @Produce json
+// @Success 200 {array} models.RecurringTransaction
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /recurring [get]
+func (h *RecurringHandler) GetAllRecurring(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	recurring, err := h.recurringService.GetAllRecurring(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    recurring,
+	})
+}
+
+// GetRecurring godoc
+// @Summary Get a recurring transaction
+// @Description Get a recurring transaction rule by ID
+// @Tags recurring
+// @Produce json
+// @Param id path int true "Recurring transaction ID"
+// @Success 200 {object} models.RecurringTransaction
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Recurring transaction not found"
+// @Security BearerAuth
+// @Router /recurring/{id} [get]
+func (h *RecurringHandler) GetRecurring(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	recurringID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid recurring transaction id",
+		})
+		return
+	}
+	recurring, err := h.recurringService.GetRecurring(userID, recurringID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    recurring,
+	})
+}
+
+// UpdateRecurring godoc
+// @Summary Update a recurring transaction
+// @Description Change a recurring transaction rule; the schedule is recalculated without repeating past runs
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Param id path int true "Recurring transaction ID"
+// @Param request body models.RecurringTransactionRequest true "Recurring transaction request"
+// @Success 200 {object} models.RecurringTransaction
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /recurring/{id} [put]
+func (h *RecurringHandler) UpdateRecurring(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	recurringID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid recurring transaction id",
+		})
+		return
+	}
+	var req models.RecurringTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	recurring, err := h.recurringService.UpdateRecurring(userID, recurringID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    recurring,
+		"message": "recurring transaction updated",
+	})
+}
+
+// DeleteRecurring godoc
+// @Summary Delete a recurring transaction
+// @Description Delete a recurring transaction rule; already created transactions are kept
+// @Tags recurring
+// @Produce json
+// @Param id path int true "Recurring transaction ID"
+// @Success 200 {object} map[string]interface{} "Success message"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /recurring/{id} [delete]
+func (h *RecurringHandler) DeleteRecurring(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	recurringID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid recurring transaction id",
+		})
+		return
+	}
+	if err := h.recurringService.DeleteRecurring(userID, recurringID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "recurring transaction deleted",
+	})
+}
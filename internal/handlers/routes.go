@@ -16,6 +16,14 @@ func RegisterRoutes(
 	categoryHandler *CategoryHandler,
 	budgetHandler *BudgetHandler,
 	notificationHandler *NotificationHandler,
+	analyticsHandler *AnalyticsHandler,
+	recurringHandler *RecurringHandler,
+	importHandler *ImportHandler,
+	rateHandler *RateHandler,
+	creditHandler *CreditHandler,
+	goalHandler *GoalHandler,
+	installmentHandler *InstallmentHandler,
+	debtHandler *DebtHandler,
 ) {
 	router.Use(middleware.CORSMiddleware())
 	v1 := router.Group("/api/v1")
@@ -33,24 +41,42 @@ func RegisterRoutes(
 	{
 		protected.POST("/account", accountHandler.CreateAccount)
 		protected.GET("/account", accountHandler.GetAccount)
+		protected.PUT("/account", accountHandler.UpdateAccount)
+		protected.GET("/account/summary", accountHandler.GetAccountSummary)
+		protected.GET("/account/net-worth", accountHandler.GetNetWorth) // ?date=2024-01-31
 
 		bankAccounts := protected.Group("/bankAccounts")
 		{
 			bankAccounts.GET("", bankAccountHandler.GetBankAccounts)                 // все банк аккаунты дсотаются
 			bankAccounts.POST("", bankAccountHandler.CreateBankAccount)              // просто создание
+			bankAccounts.POST("/import", importHandler.ImportStatementByIBAN)        // camt: счет по IBAN из выписки
 			bankAccounts.GET("/:bank_account_id", bankAccountHandler.GetBankAccount) // достается конкретный по банк аккаунт айди
 			bankAccounts.DELETE("/:bank_account_id", bankAccountHandler.DeleteBankAccount)
 			bankAccounts.PUT("/:bank_account_id/deactivate", bankAccountHandler.DeactivateBankAccount)
 			bankAccounts.PUT("/:bank_account_id/activate", bankAccountHandler.ActivateBankAccount)
+			bankAccounts.POST("/:bank_account_id/import", importHandler.ImportStatement)                // multipart: file + маппинг колонок
+			bankAccounts.GET("/:bank_account_id/balance-history", transactionHandler.GetBalanceHistory) // ?from=2024-01-01&to=2024-01-31
+			bankAccounts.PUT("/:bank_account_id/credit", creditHandler.SetCreditTerms)                  // только account_type = credit
+			bankAccounts.GET("/:bank_account_id/credit", creditHandler.GetCreditStatus)
 		}
 		transactions := protected.Group("/transactions")
 		{
 			transactions.POST("", transactionHandler.CreateTransaction)
 			transactions.GET("", transactionHandler.GetAllTransactions)
 			transactions.GET("/:id", transactionHandler.GetTransaction)
+			transactions.PUT("/:id", transactionHandler.UpdateTransaction)
+			transactions.DELETE("/:id", transactionHandler.DeleteTransaction)
 			transactions.GET("/by-category/:category_id", transactionHandler.GetAllTransactionsByCategoryID)
 
 		}
+		recurring := protected.Group("/recurring")
+		{
+			recurring.POST("", recurringHandler.CreateRecurring)
+			recurring.GET("", recurringHandler.GetAllRecurring)
+			recurring.GET("/:id", recurringHandler.GetRecurring)
+			recurring.PUT("/:id", recurringHandler.UpdateRecurring)
+			recurring.DELETE("/:id", recurringHandler.DeleteRecurring)
+		}
 		protected.POST("/transfer", transactionHandler.TransferBetweenAccounts)
 
 		protected.GET("/account/:account_id/transactions", transactionHandler.GetTransactionHistory) //  по сути удалить надо
@@ -69,8 +95,46 @@ func RegisterRoutes(
 			budgets.GET("", budgetHandler.GetBudgets)
 			budgets.GET("/:category_id/status", budgetHandler.GetBudgetStatus)
 			budgets.GET("/summary", budgetHandler.GetBudgetSummary)
+			budgets.PUT("/:id", budgetHandler.UpdateBudget)
+			budgets.DELETE("/:id", budgetHandler.DeleteBudget)
+			budgets.POST("/copy", budgetHandler.CopyBudgets)
+			budgets.POST("/envelopes/assign", budgetHandler.AssignBudget)
+			budgets.POST("/envelopes/move", budgetHandler.MoveBudget)
+			budgets.GET("/envelopes/:id/assignments", budgetHandler.GetAssignments)
 
 		}
+		installments := protected.Group("/installments")
+		{
+			installments.POST("", installmentHandler.CreateInstallmentPlan)
+			installments.GET("", installmentHandler.GetInstallmentPlans)
+			installments.GET("/obligations", installmentHandler.GetInstallmentObligations) // ?months=12
+			installments.GET("/:id", installmentHandler.GetInstallmentPlan)
+			installments.DELETE("/:id", installmentHandler.DeleteInstallmentPlan)
+		}
+		counterparties := protected.Group("/counterparties")
+		{
+			counterparties.POST("", debtHandler.CreateCounterparty)
+			counterparties.GET("", debtHandler.GetCounterparties)
+			counterparties.DELETE("/:id", debtHandler.DeleteCounterparty) // только без долгов
+		}
+		debts := protected.Group("/debts")
+		{
+			debts.POST("", debtHandler.CreateDebt)
+			debts.GET("", debtHandler.GetDebts) // ?counterparty_id=1&include_settled=true
+			debts.GET("/balances", debtHandler.GetDebtBalances)
+			debts.GET("/:id", debtHandler.GetDebt)
+			debts.DELETE("/:id", debtHandler.DeleteDebt)
+			debts.POST("/:id/repayments", debtHandler.AddRepayment)
+		}
+		goals := protected.Group("/goals")
+		{
+			goals.POST("", goalHandler.CreateGoal)
+			goals.GET("", goalHandler.GetGoals)    // ?months=3
+			goals.GET("/:id", goalHandler.GetGoal) // ?months=3
+			goals.DELETE("/:id", goalHandler.DeleteGoal)
+			goals.POST("/:id/contributions", goalHandler.AddContribution) // только цели без bank_account_id
+			goals.GET("/:id/contributions", goalHandler.GetContributions)
+		}
 		notification := protected.Group("/notification")
 		{
 
@@ -81,6 +145,14 @@ func RegisterRoutes(
 			notification.PUT("/settings", notificationHandler.SaveSettings)  // PUT /notifications/settings
 
 		}
+		analytics := protected.Group("/analytics")
+		{
+			analytics.GET("/monthly", analyticsHandler.GetMonthlyReport)           // ?year=2024&month=10&limit=5
+			analytics.GET("/categories", analyticsHandler.GetCategorySpending)     // ?start_date=2024-01-01&end_date=2024-03-31
+			analytics.GET("/income-expense", analyticsHandler.GetIncomeVsExpenses) // ?start_date=...&end_date=...&group_by=month
+			analytics.GET("/cash-flow", analyticsHandler.GetCashFlowForecast)      // ?months=6
+		}
+		protected.GET("/rates", rateHandler.GetRate) // ?from=USD&to=KZT&date=2024-01-02
 	}
 	optional := v1.Group("/public")
 	optional.Use(middleware.OptionalAuthMiddleware(authClient))
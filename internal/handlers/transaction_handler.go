@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"justTest/internal/events"
 	"justTest/internal/models"
 	events2 "justTest/internal/models/events"
@@ -16,12 +17,14 @@ import (
 
 type TransactionHandler struct {
 	transactionService *services.TransactionService
+	creditService      *services.CreditService
 	publisher          *events.Publisher
 }
 
-func NewTransactionHandler(transactionService *services.TransactionService, publisher *events.Publisher) *TransactionHandler {
+func NewTransactionHandler(transactionService *services.TransactionService, creditService *services.CreditService, publisher *events.Publisher) *TransactionHandler {
 	return &TransactionHandler{
 		transactionService: transactionService,
+		creditService:      creditService,
 		publisher:          publisher,
 	}
 }
@@ -34,12 +37,113 @@ func (h *TransactionHandler) transactionToResponse(transaction *models.Transacti
 		Amount:          transaction.Amount,
 		Description:     transaction.Description,
 		TransactionType: transaction.TransactionType,
+		IsPlanned:       transaction.IsPlanned,
+		Splits:          transaction.Splits,
 		Date:            transaction.Date.Format("2006-01-02T15:04:05Z07:00"),
 		CreatedAt:       transaction.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:       transaction.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
 
+// publishBudgetCheck отправляет транзакцию на проверку бюджета (только расходы с категорией)
+// для разбитой транзакции событие одно со всеми категориями разбивки, чтобы общий бюджет проверялся один раз
+func (h *TransactionHandler) publishBudgetCheck(userID string, transaction *models.Transaction) {
+	if transaction.TransactionType != "expense" || transaction.IsPlanned || h.publisher == nil {
+		return
+	}
+	categoryIDs := transactionCategoryIDs(transaction)
+	if len(categoryIDs) == 0 {
+		return
+	}
+	err := h.publisher.PublishTransactionCreated(events2.TransactionCreatedEvent{
+		TransactionID: transaction.ID,
+		UserID:        userID,
+		CategoryID:    categoryIDs[0],
+		CategoryIDs:   categoryIDs,
+		Amount:        transaction.Amount,
+		Description:   transaction.Description,
+		Date:          transaction.Date,
+		Timestamp:     time.Now(),
+	})
+	if err != nil {
+		log.Printf("Error publishing TransactionCreated event: %v", err)
+	} else {
+		log.Printf("Published TransactionCreated event for transaction %d", transaction.ID)
+	}
+}
+
+// publishLowBalanceCheck отправляет остаток счета на проверку низкого баланса после списания
+// для кредитного счета проверяется доступный лимит, а не остаток
+func (h *TransactionHandler) publishLowBalanceCheck(userID string, bankAccountID int64) {
+	if h.publisher == nil {
+		return
+	}
+	bankAccount, balance, err := h.creditService.AvailableBalance(userID, bankAccountID)
+	if err != nil {
+		log.Printf("Error getting balance for low balance check: %v", err)
+		return
+	}
+	err = h.publisher.PublishLowBalance(events2.LowBalanceEvent{
+		UserID:         userID,
+		BankAccountID:  bankAccountID,
+		AccountName:    bankAccount.Name,
+		CurrentBalance: balance,
+		IsCredit:       bankAccount.AccountType == "credit",
+		Timestamp:      time.Now(),
+	})
+	if err != nil {
+		log.Printf("Error publishing LowBalance event: %v", err)
+	}
+}
+
+// transactionCategoryIDs - категории транзакции: из разбивки или основная
+func transactionCategoryIDs(transaction *models.Transaction) []int64 {
+	if len(transaction.Splits) == 0 {
+		if transaction.CategoryID == nil {
+			return nil
+		}
+		return []int64{*transaction.CategoryID}
+	}
+	seen := make(map[int64]bool)
+	categoryIDs := make([]int64, 0, len(transaction.Splits))
+	for _, split := range transaction.Splits {
+		if !seen[split.CategoryID] {
+			seen[split.CategoryID] = true
+			categoryIDs = append(categoryIDs, split.CategoryID)
+		}
+	}
+	return categoryIDs
+}
+
+// balanceEffect - сколько транзакция добавляет к остатку счета, запланированная - ничего
+func balanceEffect(transaction *models.Transaction) models.Money {
+	if transaction.IsPlanned {
+		return models.Money{}
+	}
+	return transaction.Amount
+}
+
+// serviceErrorStatus - HTTP статус по виду ошибки сервиса: неверный запрос, чужой объект, не найден
+func serviceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
 // TODO Добавить проверку низкого баланса в TransactionService
 // CreateTransaction godoc
 // @Summary Create a new transaction
@@ -73,26 +177,18 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 		req.Description,
 		req.CategoryID,
 		req.TransactionType,
+		derefString(req.Date),
+		req.IsPlanned,
+		req.Splits,
 	)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if transaction.CategoryID != nil && transaction.TransactionType == "expense" && h.publisher != nil {
-		err := h.publisher.PublishTransactionCreated(events2.TransactionCreatedEvent{
-			TransactionID: transaction.ID,
-			UserID:        userID,
-			CategoryID:    *transaction.CategoryID,
-			Amount:        transaction.Amount,
-			Description:   transaction.Description,
-			Timestamp:     time.Now(),
-		})
-		if err != nil {
-			log.Printf("Error publishing TransactionCreated event: %v", err)
-		} else {
-			log.Printf("Published TransactionCreated event for transaction %d", transaction.ID)
-		}
+	h.publishBudgetCheck(userID, transaction)
+	if transaction.Amount.IsNegative() && !transaction.IsPlanned {
+		h.publishLowBalanceCheck(userID, transaction.BankAccountID)
 	}
 
 	response := h.transactionToResponse(transaction)
@@ -105,12 +201,12 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 
 // TransferBetweenAccounts godoc
 // @Summary Transfer money between bank accounts
-// @Description Create a transfer transaction between two bank accounts
+// @Description Create a transfer transaction between two bank accounts. For accounts in different currencies pass rate or received_amount, otherwise the stored rate is used
 // @Tags transactions
 // @Accept json
 // @Produce json
 // @Param request body models.TransferRequest true "Transfer request"
-// @Success 201 {object} map[string]interface{} "Success message"
+// @Success 200 {object} models.TransferResult
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
@@ -128,20 +224,24 @@ func (h *TransactionHandler) TransferBetweenAccounts(c *gin.Context) {
 		return
 	}
 
-	err := h.transactionService.TransferBetweenAccounts(
+	result, err := h.transactionService.TransferBetweenAccounts(
 		userID,
 		req.FromAccountID,
 		req.ToAccountID,
 		req.Description,
 		req.Amount,
+		req.Rate,
+		req.ReceivedAmount,
 	)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.publishLowBalanceCheck(userID, result.FromAccountID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
+		"data":    result,
 		"message": "Transfer completed successfully",
 	})
 }
@@ -261,6 +361,66 @@ func (h *TransactionHandler) GetBankAccountBalance(c *gin.Context) {
 	})
 }
 
+// GetBalanceHistory godoc
+// @Summary Get bank account balance history
+// @Description Get the end-of-day balance of a bank account for every day of a period; days are in the account timezone
+// @Tags transactions
+// @Produce json
+// @Param bank_account_id path int true "Bank account ID"
+// @Param from query string false "First day (YYYY-MM-DD), 30 days before to by default"
+// @Param to query string false "Last day (YYYY-MM-DD), today by default"
+// @Success 200 {object} models.BalanceHistory
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Security BearerAuth
+// @Router /bankAccounts/{bank_account_id}/balance-history [get]
+func (h *TransactionHandler) GetBalanceHistory(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	bankAccountID, err := strconv.ParseInt(c.Param("bank_account_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid bank account id",
+		})
+		return
+	}
+	var from, to time.Time
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "invalid from parameter, expected YYYY-MM-DD",
+			})
+			return
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "invalid to parameter, expected YYYY-MM-DD",
+			})
+			return
+		}
+	}
+	history, err := h.transactionService.GetBalanceHistory(userID, bankAccountID, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to get balance history",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    history,
+	})
+}
+
 // GetTransaction godoc
 // @Summary Get a specific transaction
 // @Description Get a specific transaction by ID for the authenticated user
@@ -324,3 +484,96 @@ func (h *TransactionHandler) GetAllTransactionsByCategoryID(c *gin.Context) {
 		"data":    response,
 	})
 }
+
+// UpdateTransaction godoc
+// @Summary Update a transaction
+// @Description Change amount, category, description, date and type of an income or expense transaction. is_planned false realizes a planned transaction, which then counts towards balances and budgets
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Param request body models.UpdateTransactionRequest true "Transaction update request"
+// @Success 200 {object} models.TransactionResponse
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Transaction belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Transaction not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /transactions/{id} [put]
+func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	transactionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+	var req models.UpdateTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transaction, previous, err := h.transactionService.UpdateTransaction(userID, transactionID, &req)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	// перепроверяется только новое состояние: у старой категории трат стало меньше, новых превышений там быть не может
+	h.publishBudgetCheck(userID, transaction)
+	if balanceEffect(transaction).Cmp(balanceEffect(previous)) < 0 {
+		h.publishLowBalanceCheck(userID, transaction.BankAccountID)
+	}
+
+	response := h.transactionToResponse(transaction)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+		"message": "Transaction updated",
+	})
+}
+
+// DeleteTransaction godoc
+// @Summary Delete a transaction
+// @Description Delete an income or expense transaction by ID
+// @Tags transactions
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Success 200 {object} map[string]interface{} "Success message"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Transaction belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Transaction not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /transactions/{id} [delete]
+func (h *TransactionHandler) DeleteTransaction(c *gin.Context) {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return
+	}
+	transactionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	// бюджет после удаления не перепроверяется: трат стало меньше, новых превышений быть не может
+	transaction, err := h.transactionService.DeleteTransaction(userID, transactionID)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	// удаленный доход уменьшает остаток счета
+	if transaction.Amount.IsPositive() && !transaction.IsPlanned {
+		h.publishLowBalanceCheck(userID, transaction.BankAccountID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Transaction deleted",
+	})
+}
@@ -7,26 +7,75 @@ import (
 
 type TransactionRepository interface {
 	Create(transaction *models.Transaction) (*models.Transaction, error)
+	CreateBatch(transactions []*models.Transaction) error
+	Update(transaction *models.Transaction) (*models.Transaction, error)
+	Delete(transactionID int64) error
+	GetSplitsByTransactionID(transactionID int64) ([]*models.TransactionSplit, error)
 	GetByBankAccountID(BankAccountID int64, limit, offset int) ([]*models.Transaction, error)
+	GetByBankAccountAndDateRange(bankAccountID int64, startDate, endDate time.Time) ([]*models.Transaction, error)
+	GetExistingExternalIDs(bankAccountID int64, externalIDs []string) (map[string]bool, error)
 	GetByCategoryID(CategoryID int64, limit, offset int) ([]*models.Transaction, error)
 	GetByTransactionID(TransactionID int64) (*models.Transaction, error)
 	GetByAccountID(AccountID int64, limit, offset int) ([]*models.Transaction, error)
 	GetTransfersByAccountID(AccountID int64) ([]*models.Transaction, error)
-	CreateTransaction(AccountID, FromBankAccountID, categoryID *int64, toAccountID int64, amount float64, description string, transferRate *float64) error
-	GetTotalAmountByBankAccountID(BankAccountID int64) (float64, error)
-	GetSpentAmountByCategoryAndMonth(categoryID int64, year, month int) (float64, error)
-	GetTransactionsByCategoryAndMonth(categoryID int64, year, month int, limit, offset int) ([]*models.Transaction, error)
+	CreateTransaction(AccountID, FromBankAccountID, categoryID *int64, toAccountID int64, amount, receivedAmount models.Money, description string, transferRate *float64) error
+	GetTotalAmountByBankAccountID(BankAccountID int64) (models.Money, error)
+	GetInflowByBankAccountAndDateRange(bankAccountID int64, startDate, endDate time.Time) (models.Money, error)
+	GetBalancesByAccountID(accountID int64) (map[int64]models.Money, error)
+	GetBalancesByAccountAsOf(accountID int64, before time.Time) (map[int64]models.Money, error)
+	GetSpentAmountByCategoryAndPeriod(categoryID int64, periodStart, periodEnd time.Time) ([]*models.CurrencyBalance, error)
+	GetSpentAmountByAccountAndPeriod(accountID int64, periodStart, periodEnd time.Time) ([]*models.CategorySpending, error)
+	GetTransactionsByCategoryAndMonth(categoryID int64, monthStart, monthEnd time.Time, limit, offset int) ([]*models.Transaction, error)
 	GetTransactionsByDateRangeWithCategory(categoryID int64, startDate, endDate time.Time, limit, offset int) ([]*models.Transaction, error)
 	GetTransactionsByDateRange(startDate, endDate time.Time, limit, offset int) ([]*models.Transaction, error)
+	GetIncomeExpenseByAccountAndDateRange(accountID int64, startDate, endDate time.Time) (models.Money, models.Money, error)
+	GetCategorySpendingByAccountAndDateRange(accountID int64, startDate, endDate time.Time) ([]*models.CategorySpending, error)
+	GetTopExpensesByAccountAndDateRange(accountID int64, startDate, endDate time.Time, limit int) ([]*models.Transaction, error)
+	GetIncomeExpenseByCurrency(accountID int64, startDate, endDate time.Time) ([]*models.IncomeExpenseReport, error)
+	GetIncomeExpenseSeries(accountID int64, startDate, endDate time.Time, groupBy, timezone string, weekStart time.Weekday) ([]*models.IncomeExpensePoint, error)
+	GetCategorySpendingByCurrency(accountID int64, startDate, endDate time.Time) ([]*models.CategorySpending, error)
+	GetPlannedFlowSeries(accountID int64, startDate, endDate time.Time, timezone string) ([]*models.PlannedFlowPoint, error)
+}
+
+type BalanceRepository interface {
+	GetDailyBalances(bankAccountID int64, from, to time.Time) ([]*models.DailyBalance, error)
+	RebuildBalances(accountID int64) error
+}
+
+type CreditTermsRepository interface {
+	Save(terms *models.CreditTerms) (*models.CreditTerms, error)
+	GetByBankAccountID(bankAccountID int64) (*models.CreditTerms, error)
+	GetAll() ([]*models.CreditTerms, error)
+	MarkReminderSent(bankAccountID int64, dueDate time.Time) error
+}
+
+type GoalRepository interface {
+	Create(goal *models.Goal) (*models.Goal, error)
+	GetByID(goalID int64) (*models.Goal, error)
+	GetByAccountID(accountID int64) ([]*models.Goal, error)
+	GetAll() ([]*models.Goal, error)
+	Delete(goalID int64) error
+	SetLastMilestone(goalID int64, milestone int) error
+	AddContribution(contribution *models.GoalContribution) (*models.GoalContribution, error)
+	GetContributions(goalID int64) ([]*models.GoalContribution, error)
+	GetContributionTotal(goalID int64, before time.Time) (models.Money, error)
+}
+
+type CurrencyRateRepository interface {
+	SaveRates(rates []*models.CurrencyRate) error
+	GetRateOnDate(fromCurrency, toCurrency string, date time.Time) (*models.CurrencyRate, error)
 }
 
 type AccountRepository interface {
 	Create(account *models.Account) (*models.Account, error)
 	GetByUserID(userID string) (*models.Account, error)
 	GetByID(id int64) (*models.Account, error)
+	Update(account *models.Account) (*models.Account, error)
+	UpdateAndRebuildBalances(account *models.Account) (*models.Account, error)
 }
 type BankAccountRepository interface {
 	Create(bankAccount *models.BankAccount) (*models.BankAccount, error)
+	CreateWithOpeningBalance(bankAccount *models.BankAccount, opening *models.Transaction) (*models.BankAccount, error)
 	GetByAccountID(accountID int64) ([]*models.BankAccount, error)
 	GetActiveBankAccounts(accountID int64) ([]*models.BankAccount, error)
 	GetBankAccountByCurrency(accountID int64, currency string) ([]*models.BankAccount, error)
@@ -47,9 +96,29 @@ type CategoryRepository interface {
 type BudgetRepository interface {
 	CreateBudget(budget *models.Budget) (*models.Budget, error)
 	GetBudget(budgetID int64) (*models.Budget, error)
-	GetBudgetByCategoryID(categoryID int64) (*models.Budget, error)
-	GetBudgetByCategoryAndMonth(categoryID int64, year, month int) (*models.Budget, error)
-	GetBudgetsByAccountAndMonth(accountID int64, year, month int) ([]*models.Budget, error)
+	GetBudgetsByCategoryAndDate(categoryID int64, date time.Time) ([]*models.Budget, error)
+	GetBudgetsByAccountAndDate(accountID int64, date time.Time) ([]*models.Budget, error)
+	GetOverlappingBudget(accountID int64, categoryID *int64, period string, startDate, endDate time.Time) (*models.Budget, error)
+	CreateBudgets(budgets []*models.Budget) error
+	UpdateBudget(budget *models.Budget) (*models.Budget, error)
+	DeleteBudget(budgetID int64) error
+	GetPreviousBudgets(accountID int64, before time.Time) ([]*models.Budget, error)
+	GetSpentByBudgets(budgetIDs []int64) ([]*models.BudgetSpending, error)
+	CreateAssignments(assignments []*models.BudgetAssignment) error
+	GetAssignments(budgetID int64) ([]*models.BudgetAssignment, error)
+	GetAssignedByBudgets(budgetIDs []int64) (map[int64]models.Money, error)
+	GetEnvelopeTotals(accountID int64) (*time.Time, models.Money, error)
+}
+
+type RecurringTransactionRepository interface {
+	Create(recurring *models.RecurringTransaction) (*models.RecurringTransaction, error)
+	Update(recurring *models.RecurringTransaction) (*models.RecurringTransaction, error)
+	Delete(recurringID int64) error
+	GetByID(recurringID int64) (*models.RecurringTransaction, error)
+	GetByAccountID(accountID int64) ([]*models.RecurringTransaction, error)
+	GetDue(date time.Time) ([]*models.RecurringTransaction, error)
+	UpdateSchedule(recurringID int64, nextRunDate time.Time, lastRunDate *time.Time, isActive bool) error
+	CreateRun(recurringID int64, runDate time.Time, transaction *models.Transaction) (bool, error)
 }
 
 type NotificationRepository interface {
@@ -64,6 +133,31 @@ type NotificationRepository interface {
 	DeleteNotification(id int64) error
 	//DeleteOldNotifications(days int) error
 }
+type InstallmentRepository interface {
+	Create(plan *models.InstallmentPlan, payments []*models.Transaction) (*models.InstallmentPlan, error)
+	GetByID(planID int64) (*models.InstallmentPlan, error)
+	GetByAccountID(accountID int64) ([]*models.InstallmentPlan, error)
+	Delete(planID int64) error
+	GetPayments(planID int64) ([]*models.InstallmentPayment, error)
+	GetOutstandingPayments(accountID int64, startDate, endDate time.Time) ([]*models.InstallmentPayment, error)
+	GetOutstandingByCategory(accountID int64, startDate, endDate time.Time) ([]*models.CategorySpending, error)
+	RealizeDuePayments(now time.Time) ([]*models.InstallmentPayment, error)
+}
+type DebtRepository interface {
+	CreateCounterparty(counterparty *models.Counterparty) (*models.Counterparty, error)
+	GetCounterpartyByID(counterpartyID int64) (*models.Counterparty, error)
+	GetCounterparties(accountID int64) ([]*models.Counterparty, error)
+	DeleteCounterparty(counterpartyID int64) error
+	Create(debt *models.Debt, transaction *models.Transaction) (*models.Debt, error)
+	GetByID(debtID int64) (*models.Debt, error)
+	GetByAccountID(accountID int64, counterpartyID *int64, includeSettled bool) ([]*models.Debt, error)
+	Delete(debtID int64) error
+	AddRepayment(repayment *models.DebtRepayment, transaction *models.Transaction) (*models.DebtRepayment, error)
+	GetRepayments(debtID int64) ([]*models.DebtRepayment, error)
+	GetBalances(accountID int64) ([]*models.DebtBalance, error)
+	GetDueForReminder(before time.Time) ([]*models.Debt, error)
+	MarkReminderSent(debtID int64, dueDate time.Time) error
+}
 type UserNotificationSettingsRepository interface {
 	GetSettings(userID string) (*models.UserNotificationSettings, error)
 	SaveSettings(settings *models.UserNotificationSettings) error
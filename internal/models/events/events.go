@@ -1,51 +1,90 @@
 package events
 
-import "time"
+import (
+	"justTest/internal/models"
+	"time"
+)
 
 type TransactionCreatedEvent struct {
-	TransactionID int64     `json:"transaction_id"`
-	UserID        string    `json:"user_id"`
-	CategoryID    int64     `json:"category_id"`
-	Amount        float64   `json:"amount"`
-	Description   string    `json:"description"`
-	Timestamp     time.Time `json:"timestamp"`
+	TransactionID int64        `json:"transaction_id"`
+	UserID        string       `json:"user_id"`
+	CategoryID    int64        `json:"category_id"`
+	CategoryIDs   []int64      `json:"category_ids,omitempty"` // все категории разбитой транзакции, пусто - только CategoryID
+	Amount        models.Money `json:"amount"`
+	Description   string       `json:"description"`
+	Date          time.Time    `json:"date"` // дата транзакции, по ней выбирается месяц бюджета
+	Timestamp     time.Time    `json:"timestamp"`
 }
 
 type BudgetExceededEvent struct {
-	UserID       string    `json:"user_id"`
-	BudgetID     int64     `json:"budget_id"`
-	BudgetName   string    `json:"budget_name"`
-	BudgetAmount float64   `json:"budget_amount"`
-	SpentAmount  float64   `json:"spent_amount"`
-	ExcessAmount float64   `json:"excess_amount"`
-	CategoryID   int64     `json:"category_id"`
-	Timestamp    time.Time `json:"timestamp"`
+	UserID       string       `json:"user_id"`
+	BudgetID     int64        `json:"budget_id"`
+	BudgetName   string       `json:"budget_name"`
+	BudgetAmount models.Money `json:"budget_amount"`
+	SpentAmount  models.Money `json:"spent_amount"`
+	ExcessAmount models.Money `json:"excess_amount"`
+	CategoryID   int64        `json:"category_id"`
+	Timestamp    time.Time    `json:"timestamp"`
 }
 
 type LowBalanceEvent struct {
-	UserID         string    `json:"user_id"`
-	BankAccountID  int64     `json:"bank_account_id"`
-	AccountName    string    `json:"account_name"`
-	CurrentBalance float64   `json:"current_balance"`
-	AlertThreshold float64   `json:"alert_threshold"`
-	Timestamp      time.Time `json:"timestamp"`
+	UserID         string       `json:"user_id"`
+	BankAccountID  int64        `json:"bank_account_id"`
+	AccountName    string       `json:"account_name"`
+	CurrentBalance models.Money `json:"current_balance"` // для кредитного счета - доступный лимит
+	AlertThreshold models.Money `json:"alert_threshold"`
+	IsCredit       bool         `json:"is_credit"`
+	Timestamp      time.Time    `json:"timestamp"`
+}
+
+// PaymentDueEvent - напоминание о сроке платежа по кредитному счету
+type PaymentDueEvent struct {
+	UserID           string       `json:"user_id"`
+	BankAccountID    int64        `json:"bank_account_id"`
+	AccountName      string       `json:"account_name"`
+	StatementBalance models.Money `json:"statement_balance"`
+	MinimumPayment   models.Money `json:"minimum_payment"`
+	DueDate          time.Time    `json:"due_date"`
+	Timestamp        time.Time    `json:"timestamp"`
 }
 
 type BudgetWarningEvent struct {
-	UserID         string    `json:"user_id"`
-	BudgetID       int64     `json:"budget_id"`
-	BudgetName     string    `json:"budget_name"`
-	BudgetAmount   float64   `json:"budget_amount"`
-	SpentAmount    float64   `json:"spent_amount"`
-	WarningPercent float64   `json:"warning_percent"`
-	ExcessAmount   float64   `json:"excess_amount"`
-	CategoryID     int64     `json:"category_id"`
-	Timestamp      time.Time `json:"timestamp"`
+	UserID         string       `json:"user_id"`
+	BudgetID       int64        `json:"budget_id"`
+	BudgetName     string       `json:"budget_name"`
+	BudgetAmount   models.Money `json:"budget_amount"`
+	SpentAmount    models.Money `json:"spent_amount"`
+	WarningPercent float64      `json:"warning_percent"`
+	ExcessAmount   models.Money `json:"excess_amount"`
+	CategoryID     int64        `json:"category_id"`
+	Timestamp      time.Time    `json:"timestamp"`
+}
+
+// DebtDueEvent - напоминание о сроке возврата долга (Direction: lent - вернуть должны нам, borrowed - мы)
+type DebtDueEvent struct {
+	UserID           string       `json:"user_id"`
+	DebtID           int64        `json:"debt_id"`
+	CounterpartyName string       `json:"counterparty_name"`
+	Direction        string       `json:"direction"`
+	Outstanding      models.Money `json:"outstanding"`
+	DueDate          time.Time    `json:"due_date"`
+	Timestamp        time.Time    `json:"timestamp"`
+}
+
+// GoalMilestoneEvent - цель накоплений достигла рубежа (25, 50, 75 или 100%)
+type GoalMilestoneEvent struct {
+	UserID       string       `json:"user_id"`
+	GoalID       int64        `json:"goal_id"`
+	GoalName     string       `json:"goal_name"`
+	Milestone    int          `json:"milestone"`
+	SavedAmount  models.Money `json:"saved_amount"`
+	TargetAmount models.Money `json:"target_amount"`
+	Timestamp    time.Time    `json:"timestamp"`
 }
 
 type NotificationEvent struct {
 	UserID    string                 `json:"user_id"`
-	Type      string                 `json:"type"` // "budget_exceeded", "low_balance", "budget_warning"
+	Type      string                 `json:"type"` // "budget_exceeded", "low_balance", "budget_warning", "payment_due", "goal_milestone", "debt_due"
 	Title     string                 `json:"title"`
 	Message   string                 `json:"message"`
 	Data      map[string]interface{} `json:"data"`
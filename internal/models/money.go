@@ -0,0 +1,255 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Money - денежная сумма в минимальных единицах (сотых долях) валюты, без ошибок округления float64
+// в JSON и БД - десятичное число с двумя знаками после запятой, как DECIMAL(15,2)
+// округление всегда до точности валюты, половина - от нуля (1.005 -> 1.01, -1.005 -> -1.01)
+type Money struct {
+	Minor    int64  // сумма в сотых долях единицы валюты
+	Currency string // "KZT", "USD"; пусто, если валюта определяется счетом
+}
+
+// moneyDecimals - знаков после запятой в Minor (масштаб DECIMAL(15,2))
+const moneyDecimals = 2
+
+// currencyDecimals - валюты, у которых меньше двух знаков после запятой; остальные - 2
+var currencyDecimals = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+}
+
+// CurrencyDecimals - точность валюты: сколько знаков после запятой остается после округления
+func CurrencyDecimals(currency string) int {
+	if decimals, ok := currencyDecimals[strings.ToUpper(currency)]; ok {
+		return decimals
+	}
+	return moneyDecimals
+}
+
+// decimalPattern - простая десятичная запись: без дробей "1/3" и экспонент "1e9", которые понимает big.Rat
+var decimalPattern = regexp.MustCompile(`^[+-]?\d+(\.\d+)?$`)
+
+// maxJSONExponent - предел экспоненты в JSON-числе: big.Rat строит 10^exp целиком, а DECIMAL(15,2) больше 1e13 не вмещает
+const maxJSONExponent = 20
+
+// NewMoney создает сумму из минимальных единиц, округляя до точности валюты
+func NewMoney(minor int64, currency string) Money {
+	money, _ := moneyFromRat(new(big.Rat).SetFrac64(minor, 100), currency)
+	return money
+}
+
+// ParseMoney точно разбирает десятичную строку ("-1234.5", "10") и округляет до точности валюты
+func ParseMoney(value string, currency string) (Money, error) {
+	value = strings.TrimSpace(value)
+	if !decimalPattern.MatchString(value) {
+		return Money{}, fmt.Errorf("invalid money amount %q", value)
+	}
+	amount, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid money amount %q", value)
+	}
+	return moneyFromRat(amount, currency)
+}
+
+// moneyFromRat округляет amount до точности валюты (половина - от нуля)
+func moneyFromRat(amount *big.Rat, currency string) (Money, error) {
+	step := int64(1)
+	for i := CurrencyDecimals(currency); i < moneyDecimals; i++ {
+		step *= 10
+	}
+	units := new(big.Rat).Mul(amount, big.NewRat(100/step, 1))
+	rounded := roundHalfAwayFromZero(units)
+	rounded.Mul(rounded, big.NewInt(step))
+	if !rounded.IsInt64() {
+		return Money{}, fmt.Errorf("money amount %s is out of range", amount.FloatString(2))
+	}
+	return Money{Minor: rounded.Int64(), Currency: currency}, nil
+}
+
+func roundHalfAwayFromZero(value *big.Rat) *big.Int {
+	num := new(big.Int).Abs(value.Num())
+	quotient, remainder := new(big.Int).QuoRem(num, value.Denom(), new(big.Int))
+	if remainder.Mul(remainder, big.NewInt(2)).Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if value.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+	return quotient
+}
+
+// Add складывает суммы одной валюты, валюта берется у первой непустой
+func (m Money) Add(other Money) Money {
+	return Money{Minor: m.Minor + other.Minor, Currency: m.currencyWith(other)}
+}
+
+func (m Money) Sub(other Money) Money {
+	return Money{Minor: m.Minor - other.Minor, Currency: m.currencyWith(other)}
+}
+
+func (m Money) Neg() Money {
+	return Money{Minor: -m.Minor, Currency: m.Currency}
+}
+
+func (m Money) Abs() Money {
+	if m.Minor < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+func (m Money) IsZero() bool {
+	return m.Minor == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Minor < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Minor > 0
+}
+
+// Cmp возвращает -1, 0 или 1
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.Minor < other.Minor:
+		return -1
+	case m.Minor > other.Minor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// WithCurrency - та же сумма с указанной валютой, округленная до ее точности
+func (m Money) WithCurrency(currency string) Money {
+	return NewMoney(m.Minor, currency)
+}
+
+// Convert умножает сумму на курс и округляет до точности валюты currency
+// курс переводится в десятичное число без потерь float64 (самое короткое представление)
+func (m Money) Convert(rate float64, currency string) (Money, error) {
+	rateRat, ok := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+	if !ok {
+		return Money{}, fmt.Errorf("invalid rate %v", rate)
+	}
+	amount := new(big.Rat).SetFrac64(m.Minor, 100)
+	return moneyFromRat(amount.Mul(amount, rateRat), currency)
+}
+
+// Percent - сколько процентов m составляет от total, 0 если total нулевой
+func (m Money) Percent(total Money) float64 {
+	if total.Minor == 0 {
+		return 0
+	}
+	return float64(m.Minor) / float64(total.Minor) * 100
+}
+
+// Float64 - приближенное значение, только для отображения и соотношений
+func (m Money) Float64() float64 {
+	return float64(m.Minor) / 100
+}
+
+// String - десятичная запись с двумя знаками: "-1234.50"
+func (m Money) String() string {
+	minor := m.Minor
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+	}
+	abs := new(big.Int).Abs(big.NewInt(minor))
+	units, cents := new(big.Int).QuoRem(abs, big.NewInt(100), new(big.Int))
+	return fmt.Sprintf("%s%s.%02d", sign, units.String(), cents.Int64())
+}
+
+func (m Money) currencyWith(other Money) string {
+	if m.Currency != "" {
+		return m.Currency
+	}
+	return other.Currency
+}
+
+// MarshalJSON пишет сумму JSON-числом с двумя знаками, чтобы клиентам не менять формат
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON принимает число или строку ("12.30"), разбирает без float64
+// число может быть в любой записи JSON (1e3, 1.5E2), строка - только простая десятичная, как в ParseMoney
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value := strings.TrimSpace(string(data))
+	if value == "null" {
+		return nil
+	}
+	var (
+		parsed Money
+		err    error
+	)
+	if strings.HasPrefix(value, `"`) {
+		parsed, err = ParseMoney(strings.Trim(value, `"`), m.Currency)
+	} else {
+		parsed, err = parseJSONNumber(value, m.Currency)
+	}
+	if err != nil {
+		return err
+	}
+	m.Minor = parsed.Minor
+	return nil
+}
+
+// parseJSONNumber разбирает JSON-число; синтаксис уже проверен encoding/json, проверяется только экспонента
+func parseJSONNumber(value string, currency string) (Money, error) {
+	if i := strings.IndexAny(value, "eE"); i >= 0 {
+		exponent, err := strconv.Atoi(value[i+1:])
+		if err != nil || exponent > maxJSONExponent || exponent < -maxJSONExponent {
+			return Money{}, fmt.Errorf("money amount %s is out of range", value)
+		}
+	}
+	amount, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid money amount %q", value)
+	}
+	return moneyFromRat(amount, currency)
+}
+
+// Value - для записи в DECIMAL колонку
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan читает DECIMAL/NUMERIC из БД (lib/pq отдает []byte), валюта не меняется
+func (m *Money) Scan(src interface{}) error {
+	var (
+		parsed Money
+		err    error
+	)
+	switch value := src.(type) {
+	case nil:
+		m.Minor = 0
+		return nil
+	case []byte:
+		parsed, err = ParseMoney(string(value), "")
+	case string:
+		parsed, err = ParseMoney(value, "")
+	case int64:
+		parsed = Money{Minor: value * 100}
+	case float64:
+		parsed, err = ParseMoney(strconv.FormatFloat(value, 'f', -1, 64), "")
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+	if err != nil {
+		return err
+	}
+	m.Minor = parsed.Minor
+	return nil
+}
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		currency string
+		want     int64
+	}{
+		{"integer", "10", "KZT", 1000},
+		{"two decimals", "1234.50", "KZT", 123450},
+		{"one decimal", "-1234.5", "KZT", -123450},
+		{"plus sign", "+7.25", "USD", 725},
+		{"spaces trimmed", "  3.10 ", "USD", 310},
+		{"half up", "1.005", "USD", 101},
+		{"half negative away from zero", "-1.005", "USD", -101},
+		{"below half", "1.0049", "USD", 100},
+		{"below half negative", "-1.0049", "USD", -100},
+		{"zero decimals currency", "1234.5", "JPY", 123500},
+		{"zero decimals negative half", "-0.5", "JPY", -100},
+		{"empty currency keeps cents", "0.01", "", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.value, tt.currency)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Minor)
+			assert.Equal(t, tt.currency, got.Currency)
+		})
+	}
+}
+
+func TestParseMoneyInvalid(t *testing.T) {
+	for _, value := range []string{"", "abc", "1/3", "1e3", "1e100000000", ".5", "5.", "1,5", "--1", "0x10", "NaN"} {
+		t.Run(value, func(t *testing.T) {
+			_, err := ParseMoney(value, "KZT")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseMoneyOutOfRange(t *testing.T) {
+	_, err := ParseMoney("100000000000000000000", "KZT")
+	assert.Error(t, err)
+}
+
+func TestMoneyConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   Money
+		rate     float64
+		currency string
+		want     int64
+	}{
+		{"exact", Money{Minor: 10000, Currency: "USD"}, 450.5, "KZT", 4505000},
+		{"half rounds up", Money{Minor: 1, Currency: "USD"}, 0.5, "EUR", 1},
+		{"negative half rounds away from zero", Money{Minor: -1, Currency: "USD"}, 0.5, "EUR", -1},
+		{"below half rounds down", Money{Minor: 100, Currency: "KZT"}, 0.00214, "USD", 0},
+		{"shortest rate representation", Money{Minor: 1000, Currency: "USD"}, 0.1, "EUR", 100},
+		{"to zero decimals currency", Money{Minor: 1000, Currency: "USD"}, 149.95, "JPY", 150000},
+		{"percent adjust", Money{Minor: 80000, Currency: "KZT"}, 1.05, "KZT", 84000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.amount.Convert(tt.rate, tt.currency)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Minor)
+			assert.Equal(t, tt.currency, got.Currency)
+		})
+	}
+}
+
+func TestMoneyMarshalJSON(t *testing.T) {
+	tests := []struct {
+		amount Money
+		want   string
+	}{
+		{Money{Minor: 0}, "0.00"},
+		{Money{Minor: 5}, "0.05"},
+		{Money{Minor: -5}, "-0.05"},
+		{Money{Minor: 123450}, "1234.50"},
+		{Money{Minor: -123456}, "-1234.56"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			data, err := json.Marshal(struct {
+				Amount Money `json:"amount"`
+			}{tt.amount})
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"amount":`+tt.want+`}`, string(data))
+			assert.Contains(t, string(data), tt.want)
+		})
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data string
+		want int64
+	}{
+		{`12.3`, 1230},
+		{`"12.30"`, 1230},
+		{`-0.005`, -1},
+		{`100`, 10000},
+		{`1e3`, 100000},
+		{`1.5E2`, 15000},
+		{`-2.5e-1`, -25},
+		{`1E+2`, 10000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			var got Money
+			require.NoError(t, json.Unmarshal([]byte(tt.data), &got))
+			assert.Equal(t, tt.want, got.Minor)
+		})
+	}
+
+	var got Money
+	assert.Error(t, json.Unmarshal([]byte(`"1/3"`), &got))
+	// в строке экспонента не допускается, как и в ParseMoney
+	assert.Error(t, json.Unmarshal([]byte(`"1e3"`), &got))
+	assert.Error(t, json.Unmarshal([]byte(`1e100000000`), &got))
+	assert.Error(t, json.Unmarshal([]byte(`1e20`), &got))
+}
+
+func TestMoneyJSONRoundTrip(t *testing.T) {
+	for _, minor := range []int64{0, 1, -1, 99, -100, 123456789} {
+		data, err := json.Marshal(Money{Minor: minor})
+		require.NoError(t, err)
+		var got Money
+		require.NoError(t, json.Unmarshal(data, &got))
+		assert.Equal(t, minor, got.Minor)
+	}
+}
+
+func TestMoneyDBRoundTrip(t *testing.T) {
+	for _, minor := range []int64{0, 7, -7, 123450, -987654321} {
+		value, err := Money{Minor: minor, Currency: "KZT"}.Value()
+		require.NoError(t, err)
+		got := Money{Currency: "KZT"}
+		require.NoError(t, got.Scan([]byte(value.(string))))
+		assert.Equal(t, minor, got.Minor)
+		assert.Equal(t, "KZT", got.Currency)
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  interface{}
+		want int64
+	}{
+		{"nil", nil, 0},
+		{"bytes", []byte("-12.34"), -1234},
+		{"string", "5.5", 550},
+		{"int64", int64(3), 300},
+		{"float64", 0.1, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Money{Minor: 42}
+			require.NoError(t, got.Scan(tt.src))
+			assert.Equal(t, tt.want, got.Minor)
+		})
+	}
+
+	var got Money
+	assert.Error(t, got.Scan(true))
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := Money{Minor: 1050, Currency: "KZT"}
+	b := Money{Minor: 250}
+
+	assert.Equal(t, Money{Minor: 1300, Currency: "KZT"}, a.Add(b))
+	assert.Equal(t, Money{Minor: 1300, Currency: "KZT"}, b.Add(a))
+	assert.Equal(t, Money{Minor: 800, Currency: "KZT"}, a.Sub(b))
+	assert.Equal(t, Money{Minor: -1050, Currency: "KZT"}, a.Neg())
+	assert.Equal(t, a, a.Neg().Abs())
+	assert.Equal(t, 1, a.Cmp(b))
+	assert.Equal(t, -1, b.Cmp(a))
+	assert.Equal(t, 0, a.Cmp(a))
+	assert.InDelta(t, 25.0, Money{Minor: 250}.Percent(Money{Minor: 1000}), 1e-9)
+	assert.Zero(t, a.Percent(Money{}))
+	assert.Equal(t, Money{Minor: 1100, Currency: "JPY"}, a.WithCurrency("JPY"))
+}
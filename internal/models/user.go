@@ -7,11 +7,12 @@ import (
 // Account - единственный финансовый аккаунт пользователя
 type Account struct {
 	ID           int64     `json:"id" db:"id"`
-	UserID       string    `json:"user_id" db:"user_id"`             // ID из auth-сервиса (Node.js)
-	Name         string    `json:"name" db:"name"`                   // "Мой аккаунт", или имя пользователя
-	DisplayName  string    `json:"display_name" db:"display_name"`   // имя для отображения в UI
-	Timezone     string    `json:"timezone" db:"timezone"`           // для корректного отображения времени
-	BaseCurrency string    `json:"base_currency" db:"base_currency"` // основная валюта для расчетов (KZT, USD, EUR)
+	UserID       string    `json:"user_id" db:"user_id"`               // ID из auth-сервиса (Node.js)
+	Name         string    `json:"name" db:"name"`                     // "Мой аккаунт", или имя пользователя
+	DisplayName  string    `json:"display_name" db:"display_name"`     // имя для отображения в UI
+	Timezone     string    `json:"timezone" db:"timezone"`             // для корректного отображения времени
+	BaseCurrency string    `json:"base_currency" db:"base_currency"`   // основная валюта для расчетов (KZT, USD, EUR)
+	WeekStartDay int       `json:"week_start_day" db:"week_start_day"` // первый день недели для недельных бюджетов: 0 - воскресенье, 1 - понедельник
 	IsActive     bool      `json:"is_active" db:"is_active"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
@@ -25,6 +26,7 @@ type BankAccount struct {
 	Currency    string    `json:"currency" db:"currency"`         // "KZT", "USD", "EUR"
 	AccountType string    `json:"account_type" db:"account_type"` // "cash", "debit", "credit", "savings"
 	BankName    string    `json:"bank_name" db:"bank_name"`       // "Kaspi", "Halyk", "Cash"
+	IBAN        string    `json:"iban,omitempty" db:"iban"`       // для сопоставления выписок camt.053/052
 	IsActive    bool      `json:"is_active" db:"is_active"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
@@ -35,15 +37,30 @@ type Transaction struct {
 	ID              int64     `json:"id" db:"id"`
 	BankAccountID   int64     `json:"bank_account_id" db:"bank_account_id"`
 	CategoryID      *int64    `json:"category_id" db:"category_id"` // может быть null для переводов
-	Amount          float64   `json:"amount" db:"amount"`           // положительное для доходов, отрицательное для расходов
+	Amount          Money     `json:"amount" db:"amount"`           // положительное для доходов, отрицательное для расходов
 	Description     string    `json:"description" db:"description"`
-	TransactionType string    `json:"transaction_type" db:"transaction_type"` // "income", "expense", "transfer"
+	TransactionType string    `json:"transaction_type" db:"transaction_type"` // "income", "expense", "transfer", "opening_balance", "debt"
 	Date            time.Time `json:"date" db:"date"`
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 	// Для переводов между банковскими счетами
-	ToAccountID  *int64   `json:"to_account_id" db:"to_account_id"` // ID другого банковского счета
-	TransferRate *float64 `json:"transfer_rate" db:"transfer_rate"` // курс валют если перевод между валютами
+	ToAccountID  *int64   `json:"to_account_id" db:"to_account_id"`       // ID другого банковского счета
+	TransferRate *float64 `json:"transfer_rate" db:"transfer_rate"`       // курс валют если перевод между валютами
+	IsPlanned    bool     `json:"is_planned" db:"is_planned"`             // запланированная, не влияет на баланс и бюджеты
+	ExternalID   *string  `json:"external_id,omitempty" db:"external_id"` // id из выписки банка (FITID), повторный импорт его пропускает
+	// Разбивка по категориям (чек из супермаркета), сумма строк = Amount
+	Splits []*TransactionSplit `json:"splits,omitempty" db:"-"`
+	// Платеж по рассрочке - меняется только через план
+	InstallmentPlanID *int64 `json:"installment_plan_id,omitempty" db:"installment_plan_id"`
+}
+
+// TransactionSplit - строка разбивки транзакции со своей категорией
+type TransactionSplit struct {
+	ID            int64  `json:"id" db:"id"`
+	TransactionID int64  `json:"transaction_id" db:"transaction_id"`
+	CategoryID    int64  `json:"category_id" db:"category_id"`
+	Amount        Money  `json:"amount" db:"amount"` // знак как у транзакции
+	Description   string `json:"description" db:"description"`
 }
 
 // Category - категории транзакций
@@ -59,14 +76,18 @@ type Category struct {
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
-// Budget - бюджеты на категории
+// Budget - бюджет на одну категорию, на несколько категорий или на все категории аккаунта
 type Budget struct {
 	ID              int64     `json:"id" db:"id"`
 	AccountID       int64     `json:"account_id" db:"account_id"`
 	BudgetLimitName string    `json:"budget_limit_name" db:"budget_limit_name"`
-	CategoryID      int64     `json:"category_id" db:"category_id"`
-	Amount          float64   `json:"amount" db:"amount"` // лимит на период в базовой валюте аккаунта
-	Period          string    `json:"period" db:"period"` // "monthly", "weekly", "yearly"
+	CategoryID      *int64    `json:"category_id" db:"category_id"` // у бюджета нескольких категорий и по всем категориям - nil
+	CategoryIDs     []int64   `json:"category_ids" db:"category_ids"`
+	AllCategories   bool      `json:"all_categories" db:"all_categories"` // по всем категориям аккаунта
+	Amount          Money     `json:"amount" db:"amount"`                 // лимит на период в базовой валюте аккаунта
+	Period          string    `json:"period" db:"period"`                 // "monthly", "weekly", "yearly"
+	Mode            string    `json:"mode" db:"mode"`                     // "limit" - лимит amount на период, "envelope" - конверт
+	Rollover        bool      `json:"rollover" db:"rollover"`             // остаток или перерасход переносится в следующий период
 	StartDate       time.Time `json:"start_date" db:"start_date"`
 	EndDate         time.Time `json:"end_date" db:"end_date"`
 	IsActive        bool      `json:"is_active" db:"is_active"`
@@ -74,23 +95,243 @@ type Budget struct {
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 }
 
-// BankAccountBalance - кэшированные балансы банковских счетов
+// RecurringTransaction - правило повторяющейся транзакции (аренда, зарплата, подписки)
+type RecurringTransaction struct {
+	ID              int64      `json:"id" db:"id"`
+	AccountID       int64      `json:"account_id" db:"account_id"`
+	BankAccountID   int64      `json:"bank_account_id" db:"bank_account_id"`
+	CategoryID      *int64     `json:"category_id" db:"category_id"`
+	Amount          Money      `json:"amount" db:"amount"`
+	Description     string     `json:"description" db:"description"`
+	TransactionType string     `json:"transaction_type" db:"transaction_type"` // "income", "expense"
+	Frequency       string     `json:"frequency" db:"frequency"`               // "daily", "weekly", "monthly", "yearly"
+	Interval        int        `json:"interval" db:"interval_count"`           // каждые N периодов
+	DayOfMonth      *int       `json:"day_of_month" db:"day_of_month"`         // для monthly, иначе день из start_date
+	StartDate       time.Time  `json:"start_date" db:"start_date"`
+	EndDate         *time.Time `json:"end_date" db:"end_date"`
+	NextRunDate     time.Time  `json:"next_run_date" db:"next_run_date"`
+	LastRunDate     *time.Time `json:"last_run_date" db:"last_run_date"`
+	IsActive        bool       `json:"is_active" db:"is_active"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// BankAccountBalance - текущий остаток счета из bank_account_balances (без запланированных транзакций)
 type BankAccountBalance struct {
 	BankAccountID int64     `json:"bank_account_id" db:"bank_account_id"`
-	Balance       float64   `json:"balance" db:"balance"`
+	Balance       Money     `json:"balance" db:"balance"`
 	Currency      string    `json:"currency" db:"currency"`
 	LastUpdated   time.Time `json:"last_updated" db:"last_updated"`
 }
 
+// DailyBalance - остаток счета на конец дня (день - в таймзоне аккаунта)
+type DailyBalance struct {
+	Date    time.Time `json:"date" db:"balance_date"`
+	Balance Money     `json:"balance" db:"balance"`
+}
+
+// BalanceHistory - остатки счета на конец каждого дня периода [From, To]
+type BalanceHistory struct {
+	BankAccountID int64           `json:"bank_account_id"`
+	Currency      string          `json:"currency"`
+	From          time.Time       `json:"from"`
+	To            time.Time       `json:"to"`
+	Days          []*DailyBalance `json:"days"`
+}
+
+// CreditTerms - условия кредитного счета (account_type = "credit")
+// дни больше длины месяца означают последний день месяца
+type CreditTerms struct {
+	BankAccountID     int64      `json:"bank_account_id" db:"bank_account_id"`
+	CreditLimit       Money      `json:"credit_limit" db:"credit_limit"`
+	StatementDay      int        `json:"statement_day" db:"statement_day"`             // день закрытия выписки, 1-31
+	PaymentDueDay     int        `json:"payment_due_day" db:"payment_due_day"`         // день платежа, 1-31
+	InterestRate      float64    `json:"interest_rate" db:"interest_rate"`             // годовая ставка, %
+	MinPaymentPercent float64    `json:"min_payment_percent" db:"min_payment_percent"` // минимальный платеж, % от выписки
+	LastReminderDate  *time.Time `json:"-" db:"last_reminder_date"`                    // срок платежа, о котором уже напомнили
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreditStatus - состояние кредитного счета: доступный лимит и последняя закрытая выписка
+// суммы задолженности положительные, Balance - остаток счета (отрицательный - долг)
+type CreditStatus struct {
+	BankAccountID      int64        `json:"bank_account_id"`
+	Currency           string       `json:"currency"`
+	Terms              *CreditTerms `json:"terms"`
+	Balance            Money        `json:"balance"`
+	AvailableCredit    Money        `json:"available_credit"`     // лимит + остаток, отрицательный - лимит превышен
+	StatementStart     time.Time    `json:"statement_start"`      // первый день периода выписки
+	StatementEnd       time.Time    `json:"statement_end"`        // день закрытия выписки
+	StatementBalance   Money        `json:"statement_balance"`    // задолженность на конец дня закрытия
+	PaidSinceStatement Money        `json:"paid_since_statement"` // пополнения после закрытия выписки
+	RemainingStatement Money        `json:"remaining_statement"`  // сколько еще погасить до срока, чтобы не платить проценты
+	MinimumPayment     Money        `json:"minimum_payment"`      // оставшийся минимальный платеж
+	DueDate            time.Time    `json:"due_date"`
+	EstimatedInterest  Money        `json:"estimated_interest"` // проценты за месяц, если не погасить остаток выписки
+}
+
+// Goal - цель накоплений: к TargetDate накопить TargetAmount
+// с BankAccountID накоплено = остаток сберегательного счета, без него - сумма взносов (GoalContribution)
+type Goal struct {
+	ID            int64     `json:"id" db:"id"`
+	AccountID     int64     `json:"account_id" db:"account_id"`
+	BankAccountID *int64    `json:"bank_account_id" db:"bank_account_id"`
+	Name          string    `json:"name" db:"name"`
+	TargetAmount  Money     `json:"target_amount" db:"target_amount"`
+	Currency      string    `json:"currency" db:"currency"`
+	TargetDate    time.Time `json:"target_date" db:"target_date"`
+	LastMilestone int       `json:"last_milestone" db:"last_milestone"` // последний отмеченный рубеж, %
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// GoalContribution - взнос в цель без привязанного счета, отрицательный - снятие
+type GoalContribution struct {
+	ID        int64     `json:"id" db:"id"`
+	GoalID    int64     `json:"goal_id" db:"goal_id"`
+	Amount    Money     `json:"amount" db:"amount"`
+	Date      time.Time `json:"date" db:"date"`
+	Note      string    `json:"note" db:"note"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// GoalProgress - прогресс цели и прогноз по среднему взносу за последние Months месяцев
+type GoalProgress struct {
+	Goal           *Goal      `json:"goal"`
+	Saved          Money      `json:"saved"`
+	Remaining      Money      `json:"remaining"`
+	Percent        float64    `json:"percent"`
+	IsCompleted    bool       `json:"is_completed"`
+	MonthsLeft     int        `json:"months_left"`     // полных месяцев до TargetDate, не меньше 1
+	MonthlyNeeded  Money      `json:"monthly_needed"`  // сколько откладывать в месяц, чтобы успеть к сроку
+	Months         int        `json:"months"`          // за сколько последних месяцев считается средний взнос
+	AverageMonthly Money      `json:"average_monthly"` // средний прирост накоплений в месяц
+	ProjectedDate  *time.Time `json:"projected_date"`  // когда цель будет достигнута при таком взносе, nil - никогда
+	OnTrack        bool       `json:"on_track"`        // прогноз не позже срока
+}
+
+// InstallmentPlan - рассрочка: (TotalAmount + Fee) делится на PaymentsCount ежемесячных платежей со счета
+// каждый платеж - запланированная транзакция, в день платежа она становится обычной
+type InstallmentPlan struct {
+	ID               int64     `json:"id" db:"id"`
+	AccountID        int64     `json:"account_id" db:"account_id"`
+	BankAccountID    int64     `json:"bank_account_id" db:"bank_account_id"`
+	CategoryID       *int64    `json:"category_id" db:"category_id"`
+	Description      string    `json:"description" db:"description"`
+	TotalAmount      Money     `json:"total_amount" db:"total_amount"`
+	Fee              Money     `json:"fee" db:"fee_amount"`
+	PaymentsCount    int       `json:"payments_count" db:"payments_count"`
+	PaidPayments     int       `json:"paid_payments" db:"paid_payments"` // оплачено до заведения рассрочки, транзакций нет
+	FirstPaymentDate time.Time `json:"first_payment_date" db:"first_payment_date"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+	// заполняются при чтении одной рассрочки
+	Payments  []*InstallmentPayment `json:"payments,omitempty" db:"-"`
+	Remaining *Money                `json:"remaining,omitempty" db:"-"` // сумма еще не наступивших платежей
+}
+
+// InstallmentPayment - платеж по рассрочке (транзакция с installment_plan_id)
+type InstallmentPayment struct {
+	PlanID        int64     `json:"plan_id"`
+	TransactionID int64     `json:"transaction_id"`
+	BankAccountID int64     `json:"bank_account_id"`
+	CategoryID    *int64    `json:"category_id"`
+	Description   string    `json:"description"`
+	DueDate       time.Time `json:"due_date"`
+	Amount        Money     `json:"amount"` // отрицательная, как у транзакции расхода
+	Currency      string    `json:"currency"`
+	IsPaid        bool      `json:"is_paid"` // транзакция уже не запланированная
+}
+
+// InstallmentObligations - еще не наступившие платежи по рассрочкам за один месяц
+type InstallmentObligations struct {
+	Year     int                   `json:"year"`
+	Month    int                   `json:"month"`
+	Currency string                `json:"currency"` // базовая валюта аккаунта
+	Total    Money                 `json:"total"`    // сумма платежей в базовой валюте по текущему курсу
+	Payments []*InstallmentPayment `json:"payments"`
+}
+
+// Counterparty - человек, с которым есть долги
+type Counterparty struct {
+	ID        int64     `json:"id" db:"id"`
+	AccountID int64     `json:"account_id" db:"account_id"`
+	Name      string    `json:"name" db:"name"`
+	Note      string    `json:"note" db:"note"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Debt - долг: Direction "lent" - дали в долг, "borrowed" - взяли в долг
+// с BankAccountID выдача/получение денег - транзакция типа "debt" на этом счете
+type Debt struct {
+	ID               int64      `json:"id" db:"id"`
+	AccountID        int64      `json:"account_id" db:"account_id"`
+	CounterpartyID   int64      `json:"counterparty_id" db:"counterparty_id"`
+	CounterpartyName string     `json:"counterparty_name" db:"-"`
+	Direction        string     `json:"direction" db:"direction"`
+	Amount           Money      `json:"amount" db:"amount"`
+	Currency         string     `json:"currency" db:"currency"`
+	Description      string     `json:"description" db:"description"`
+	Date             time.Time  `json:"date" db:"date"`
+	DueDate          *time.Time `json:"due_date" db:"due_date"`
+	BankAccountID    *int64     `json:"bank_account_id" db:"bank_account_id"`
+	TransactionID    *int64     `json:"transaction_id" db:"transaction_id"`
+	LastReminderDate *time.Time `json:"-" db:"last_reminder_date"` // срок, о котором уже напомнили
+	Repaid           Money      `json:"repaid" db:"-"`             // сумма возвратов
+	Outstanding      Money      `json:"outstanding" db:"-"`        // Amount - Repaid
+	IsSettled        bool       `json:"is_settled" db:"-"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+	// заполняется при чтении одного долга
+	Repayments []*DebtRepayment `json:"repayments,omitempty" db:"-"`
+}
+
+// DebtRepayment - частичный или полный возврат долга
+type DebtRepayment struct {
+	ID            int64     `json:"id" db:"id"`
+	DebtID        int64     `json:"debt_id" db:"debt_id"`
+	Amount        Money     `json:"amount" db:"amount"`
+	Date          time.Time `json:"date" db:"date"`
+	Note          string    `json:"note" db:"note"`
+	BankAccountID *int64    `json:"bank_account_id" db:"bank_account_id"`
+	TransactionID *int64    `json:"transaction_id" db:"transaction_id"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// DebtBalance - непогашенные долги с одним человеком в одной валюте
+type DebtBalance struct {
+	CounterpartyID   int64  `json:"counterparty_id"`
+	CounterpartyName string `json:"counterparty_name"`
+	Currency         string `json:"currency"`
+	OwedToMe         Money  `json:"owed_to_me"` // остаток по lent
+	IOwe             Money  `json:"i_owe"`      // остаток по borrowed
+	Net              Money  `json:"net"`        // > 0 - должны нам
+}
+
 // CurrencyRate - курсы валют для расчета общего баланса
+// Rate - сколько единиц ToCurrency дают за одну единицу FromCurrency на дату RateDate
 type CurrencyRate struct {
 	ID           int64     `json:"id" db:"id"`
 	FromCurrency string    `json:"from_currency" db:"from_currency"`
 	ToCurrency   string    `json:"to_currency" db:"to_currency"`
 	Rate         float64   `json:"rate" db:"rate"`
+	RateDate     time.Time `json:"rate_date" db:"rate_date"`
+	Source       string    `json:"source" db:"source"` // "ecb", "nbk", "manual"
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// CurrencyRateQuote - курс на дату: напрямую, через обратный курс или через опорную валюту
+type CurrencyRateQuote struct {
+	FromCurrency string          `json:"from_currency"`
+	ToCurrency   string          `json:"to_currency"`
+	Date         time.Time       `json:"date"`
+	Rate         float64         `json:"rate"`
+	Pivot        string          `json:"pivot,omitempty"` // опорная валюта, если прямого курса нет
+	Rates        []*CurrencyRate `json:"rates"`           // сохраненные курсы, из которых посчитан Rate
+}
+
 // DTO для взаимодействия с auth-сервисом
 type AuthUser struct {
 	ID       string `json:"id"` // ← изменить на string
@@ -100,21 +341,47 @@ type AuthUser struct {
 
 // Value objects для бизнес-логики
 type CurrencyBalance struct {
-	Currency string  `json:"currency"`
-	Amount   float64 `json:"amount"`
+	Currency string `json:"currency"`
+	Amount   Money  `json:"amount"`
 }
 
 type BudgetStatus struct {
 	Budget     *Budget `json:"budget"`
-	Spent      float64 `json:"spent"`       // потрачено в текущем периоде в валюте бюджета
-	Remaining  float64 `json:"remaining"`   // осталось
+	Spent      Money   `json:"spent"`       // потрачено в текущем периоде в валюте бюджета
+	Remaining  Money   `json:"remaining"`   // осталось
 	Progress   float64 `json:"progress"`    // процент использования (0-100)
 	IsExceeded bool    `json:"is_exceeded"` // превышен ли бюджет
+	// платежи по рассрочкам категории, которые еще наступят в периоде бюджета
+	Installments        Money `json:"installments"`
+	Projected           Money `json:"projected"`             // потрачено + платежи по рассрочкам
+	IsProjectedExceeded bool  `json:"is_projected_exceeded"` // будет ли бюджет превышен с учетом рассрочек
+	// перенесено из прошлых периодов (rollover), отрицательное - перерасход
+	CarriedIn Money `json:"carried_in"`
+	Assigned  Money `json:"assigned"`  // лимит периода или распределенное в конверт
+	Available Money `json:"available"` // assigned + carried_in, от него считаются remaining и progress
+}
+
+// BudgetSpending - траты категории бюджета за его период в валюте счетов
+type BudgetSpending struct {
+	BudgetID int64  `json:"budget_id"`
+	Currency string `json:"currency"`
+	Amount   Money  `json:"amount"`
+}
+
+// BudgetAssignment - распределение денег в конверт (envelope-бюджет) или из него
+type BudgetAssignment struct {
+	ID              int64     `json:"id" db:"id"`
+	AccountID       int64     `json:"account_id" db:"account_id"`
+	BudgetID        int64     `json:"budget_id" db:"budget_id"`
+	RelatedBudgetID *int64    `json:"related_budget_id,omitempty" db:"related_budget_id"` // другой конверт при перемещении
+	Amount          Money     `json:"amount" db:"amount"`                                 // в базовой валюте, отрицательная - из конверта
+	Description     *string   `json:"description,omitempty" db:"description"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 }
 
 type BankAccountSummary struct {
 	BankAccount *BankAccount `json:"bank_account"`
-	Balance     float64      `json:"balance"`
+	Balance     Money        `json:"balance"`
 }
 
 type AccountSummary struct {
@@ -126,22 +393,43 @@ type AccountSummary struct {
 	Budgets         []*BudgetStatus       `json:"budgets"`
 }
 
+// NetWorth - чистые активы: остатки активных счетов на дату в базовой валюте
+type NetWorth struct {
+	Date         time.Time           `json:"date"`
+	BaseCurrency string              `json:"base_currency"`
+	Total        Money               `json:"total"`      // сумма остатков в базовой валюте
+	Currencies   []*NetWorthCurrency `json:"currencies"` // подытоги по валютам счетов
+}
+
+// NetWorthCurrency - подытог по одной валюте и курс, по которому он пересчитан
+type NetWorthCurrency struct {
+	Currency     string                `json:"currency"`
+	Amount       Money                 `json:"amount"`    // сумма остатков в этой валюте
+	Converted    Money                 `json:"converted"` // то же в базовой валюте
+	Rate         *CurrencyRateQuote    `json:"rate"`      // примененный курс
+	BankAccounts []*BankAccountSummary `json:"bank_accounts"`
+}
+
 type CreateAccountRequest struct {
 	DisplayName string `json:"display_name" binding:"required,min=2,max=40"`
 }
 type UpdateAccountRequest struct {
-	DisplayName string `json:"display_name" binding:"required,min=2,max=40"`
-	Timezone    string `json:"timezone" binding:"required"`
+	DisplayName  string  `json:"display_name" binding:"required,min=2,max=40"`
+	Timezone     string  `json:"timezone" binding:"required"`
+	WeekStartDay *int    `json:"week_start_day" binding:"omitempty,min=0,max=6"`          // не передан - не меняется
+	BaseCurrency *string `json:"base_currency" binding:"omitempty,oneof=KZT USD EUR RUB"` // не передана - не меняется
 }
 type AccountResponse struct {
-	ID          int64     `json:"id"`
-	UserID      string    `json:"user_id"`
-	Name        string    `json:"name"`
-	DisplayName string    `json:"display_name"`
-	Timezone    string    `json:"timezone"`
-	IsActive    bool      `json:"is_active"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           int64     `json:"id"`
+	UserID       string    `json:"user_id"`
+	Name         string    `json:"name"`
+	DisplayName  string    `json:"display_name"`
+	Timezone     string    `json:"timezone"`
+	BaseCurrency string    `json:"base_currency"`
+	WeekStartDay int       `json:"week_start_day"`
+	IsActive     bool      `json:"is_active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 type Notification struct {
@@ -162,17 +450,17 @@ type UserNotificationSettings struct {
 	BudgetAlertsEnabled  bool      `json:"budget_alerts_enabled" db:"budget_alerts_enabled"`
 	BalanceAlertsEnabled bool      `json:"balance_alerts_enabled" db:"balance_alerts_enabled"`
 	BudgetWarningPercent int       `json:"budget_warning_percent" db:"budget_warning_percent"`
-	LowBalanceThreshold  float64   `json:"low_balance_threshold" db:"low_balance_threshold"`
+	LowBalanceThreshold  Money     `json:"low_balance_threshold" db:"low_balance_threshold"`
 	PreferredChannel     string    `json:"preferred_channel" db:"preferred_channel"`
 	CreatedAt            time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt            time.Time `json:"updated_at" db:"updated_at"`
 }
 type SaveSettingsRequest struct {
-	BudgetAlertsEnabled  bool    `json:"budget_alerts_enabled"`
-	BalanceAlertsEnabled bool    `json:"balance_alerts_enabled"`
-	BudgetWarningPercent int     `json:"budget_warning_percent" binding:"min=0,max=100"`
-	LowBalanceThreshold  float64 `json:"low_balance_threshold" binding:"min=0"`
-	PreferredChannel     string  `json:"preferred_channel" binding:"required,oneof=email push sms"`
+	BudgetAlertsEnabled  bool   `json:"budget_alerts_enabled"`
+	BalanceAlertsEnabled bool   `json:"balance_alerts_enabled"`
+	BudgetWarningPercent int    `json:"budget_warning_percent" binding:"min=0,max=100"`
+	LowBalanceThreshold  Money  `json:"low_balance_threshold"` // не меньше 0
+	PreferredChannel     string `json:"preferred_channel" binding:"required,oneof=email push sms"`
 }
 
 // ниже по аналитике
@@ -181,9 +469,10 @@ type SaveSettingsRequest struct {
 type MonthlyReport struct {
 	Month        int                 `json:"month"`
 	Year         int                 `json:"year"`
-	TotalIncome  float64             `json:"total_income"`
-	TotalExpense float64             `json:"total_expense"`
-	NetIncome    float64             `json:"net_income"`
+	Currency     string              `json:"currency"` // базовая валюта аккаунта, в нее пересчитаны суммы
+	TotalIncome  Money               `json:"total_income"`
+	TotalExpense Money               `json:"total_expense"`
+	NetIncome    Money               `json:"net_income"`
 	Categories   []*CategorySpending `json:"categories"`
 	TopExpenses  []*Transaction      `json:"top_expenses"`
 }
@@ -192,33 +481,72 @@ type MonthlyReport struct {
 type CategorySpending struct {
 	CategoryID   int64   `json:"category_id"`
 	CategoryName string  `json:"category_name"`
-	Amount       float64 `json:"amount"`
+	Currency     string  `json:"currency,omitempty"` // заполняется в отчетах по произвольному периоду
+	Amount       Money   `json:"amount"`
 	Percentage   float64 `json:"percentage"`
 }
 
-// IncomeExpenseReport - отчет доходы vs расходы
+// IncomeExpenseReport - отчет доходы vs расходы (один на каждую валюту)
 type IncomeExpenseReport struct {
-	TotalIncome  float64 `json:"total_income"`
-	TotalExpense float64 `json:"total_expense"`
-	NetIncome    float64 `json:"net_income"`
-	SavingsRate  float64 `json:"savings_rate"` // процент сбережений
+	Currency     string                `json:"currency"`
+	TotalIncome  Money                 `json:"total_income"`
+	TotalExpense Money                 `json:"total_expense"`
+	NetIncome    Money                 `json:"net_income"`
+	SavingsRate  float64               `json:"savings_rate"`      // процент сбережений
+	Periods      []*IncomeExpensePoint `json:"periods,omitempty"` // временной ряд, если задан group_by
+}
+
+// IncomeExpensePoint - доходы и расходы за один день/неделю/месяц
+type IncomeExpensePoint struct {
+	PeriodStart  time.Time `json:"period_start"`
+	Currency     string    `json:"currency"`
+	TotalIncome  Money     `json:"total_income"`
+	TotalExpense Money     `json:"total_expense"`
+	NetIncome    Money     `json:"net_income"`
+}
+
+// CashFlowForecast - прогноз остатков по месяцам: текущие остатки активных счетов плюс запланированные транзакции,
+// включая платежи по рассрочкам; все в базовой валюте по текущему курсу
+type CashFlowForecast struct {
+	Currency     string           `json:"currency"`
+	StartBalance Money            `json:"start_balance"` // остаток активных счетов сейчас
+	Months       []*CashFlowMonth `json:"months"`
+}
+
+// CashFlowMonth - запланированные поступления и списания за месяц и остаток на его конец
+type CashFlowMonth struct {
+	PeriodStart    time.Time `json:"period_start"`
+	PlannedIncome  Money     `json:"planned_income"`
+	PlannedExpense Money     `json:"planned_expense"` // включая платежи по рассрочкам
+	Installments   Money     `json:"installments"`    // из них платежи по рассрочкам
+	NetFlow        Money     `json:"net_flow"`
+	EndBalance     Money     `json:"end_balance"`
+}
+
+// PlannedFlowPoint - запланированные транзакции за месяц в одной валюте
+type PlannedFlowPoint struct {
+	PeriodStart  time.Time `json:"period_start"`
+	Currency     string    `json:"currency"`
+	Income       Money     `json:"income"`
+	Expense      Money     `json:"expense"`      // по модулю
+	Installments Money     `json:"installments"` // часть Expense - платежи по рассрочкам
 }
 
 // BudgetAlert - уведомление о превышении бюджета
 type BudgetAlert struct {
-	BudgetID     int64   `json:"budget_id"`
-	BudgetName   string  `json:"budget_name"`
-	BudgetAmount float64 `json:"budget_amount"`
-	SpentAmount  float64 `json:"spent_amount"`
-	ExcessAmount float64 `json:"excess_amount"`
+	BudgetID     int64  `json:"budget_id"`
+	BudgetName   string `json:"budget_name"`
+	BudgetAmount Money  `json:"budget_amount"`
+	SpentAmount  Money  `json:"spent_amount"`
+	ExcessAmount Money  `json:"excess_amount"`
 }
 
 // BalanceAlert - уведомление о низком балансе
 type BalanceAlert struct {
-	BankAccountID  int64   `json:"bank_account_id"`
-	AccountName    string  `json:"account_name"`
-	CurrentBalance float64 `json:"current_balance"`
-	AlertThreshold float64 `json:"alert_threshold"`
+	BankAccountID  int64  `json:"bank_account_id"`
+	AccountName    string `json:"account_name"`
+	CurrentBalance Money  `json:"current_balance"`
+	AlertThreshold Money  `json:"alert_threshold"`
 }
 
 // Requests
@@ -228,6 +556,70 @@ type CreateBankAccountRequest struct {
 	Currency    string `json:"currency" binding:"required,oneof=KZT USD EUR RUB"`
 	AccountType string `json:"account_type" binding:"required,oneof=cash debit credit savings"`
 	BankName    string `json:"bank_name" binding:"required,min=2,max=40"`
+	IBAN        string `json:"iban" binding:"omitempty,max=42"` // необязательно, пробелы допускаются
+	// начальный остаток уже существующего счета, может быть отрицательным; не считается доходом
+	OpeningBalance *Money  `json:"opening_balance"`
+	OpeningDate    *string `json:"opening_date"` // "2024-10-01" в таймзоне аккаунта, по умолчанию - сейчас
+}
+
+// CreditTermsRequest - условия кредитного счета, лимит - в валюте счета
+type CreditTermsRequest struct {
+	CreditLimit       Money    `json:"credit_limit" binding:"required"` // больше нуля
+	StatementDay      int      `json:"statement_day" binding:"required,min=1,max=31"`
+	PaymentDueDay     int      `json:"payment_due_day" binding:"required,min=1,max=31"`
+	InterestRate      float64  `json:"interest_rate" binding:"min=0,max=100"`
+	MinPaymentPercent *float64 `json:"min_payment_percent" binding:"omitempty,min=0,max=100"` // по умолчанию 5
+}
+
+type CreateCounterpartyRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+	Note string `json:"note" binding:"max=255"`
+}
+
+// CreateDebtRequest - долг; с bank_account_id деньги списываются со счета (lent) или зачисляются на него (borrowed)
+type CreateDebtRequest struct {
+	CounterpartyID int64   `json:"counterparty_id" binding:"required"`
+	Direction      string  `json:"direction" binding:"required,oneof=lent borrowed"`
+	Amount         Money   `json:"amount" binding:"required"` // больше нуля
+	Currency       string  `json:"currency" binding:"omitempty,oneof=KZT USD EUR RUB"`
+	BankAccountID  *int64  `json:"bank_account_id"`               // валюта долга - валюта счета
+	Description    string  `json:"description" binding:"max=255"` // по умолчанию "Lent to <имя>" / "Borrowed from <имя>"
+	Date           *string `json:"date"`                          // "2024-10-01" в таймзоне аккаунта, по умолчанию - сейчас
+	DueDate        *string `json:"due_date"`                      // "2024-12-01", срок возврата
+}
+
+// DebtRepaymentRequest - возврат долга; с bank_account_id деньги зачисляются на счет (lent) или списываются с него (borrowed)
+type DebtRepaymentRequest struct {
+	Amount        Money   `json:"amount" binding:"required"` // больше нуля, не больше остатка долга
+	BankAccountID *int64  `json:"bank_account_id"`           // счет в валюте долга
+	Date          *string `json:"date"`                      // "2024-10-01" в таймзоне аккаунта, по умолчанию - сейчас
+	Note          string  `json:"note" binding:"max=255"`
+}
+
+// CreateInstallmentPlanRequest - рассрочка: первый платеж first_payment_date, дальше каждый месяц в тот же день
+type CreateInstallmentPlanRequest struct {
+	BankAccountID    int64  `json:"bank_account_id" binding:"required"`
+	CategoryID       *int64 `json:"category_id"`
+	Description      string `json:"description" binding:"required,min=1,max=255"`
+	TotalAmount      Money  `json:"total_amount" binding:"required"`                // стоимость покупки, больше нуля
+	Fee              *Money `json:"fee"`                                            // комиссия за весь срок
+	PaymentsCount    int    `json:"payments_count" binding:"required,min=1,max=60"` // число платежей
+	FirstPaymentDate string `json:"first_payment_date" binding:"required"`          // "2024-11-05"
+	PaidPayments     int    `json:"paid_payments" binding:"min=0"`                  // сколько платежей уже внесено до заведения рассрочки
+}
+
+// CreateGoalRequest - цель с привязкой к сберегательному счету (валюта - его) или со взносами вручную (нужна currency)
+type CreateGoalRequest struct {
+	Name          string `json:"name" binding:"required,min=2,max=100"`
+	TargetAmount  Money  `json:"target_amount" binding:"required"` // больше нуля
+	TargetDate    string `json:"target_date" binding:"required"`   // "2025-06-01"
+	BankAccountID *int64 `json:"bank_account_id"`                  // счет с account_type = savings
+	Currency      string `json:"currency" binding:"omitempty,oneof=KZT USD EUR RUB"`
+}
+type GoalContributionRequest struct {
+	Amount Money   `json:"amount" binding:"required"` // отрицательная - снятие
+	Date   *string `json:"date"`                      // "2024-10-01" в таймзоне аккаунта, по умолчанию - сейчас
+	Note   string  `json:"note" binding:"max=255"`
 }
 type CreateCategoryRequest struct {
 	Name  string `json:"name" binding:"required,min=2,max=50"`
@@ -236,20 +628,142 @@ type CreateCategoryRequest struct {
 	Icon  string `json:"icon" binding:"required"`
 }
 type CreateTransactionRequest struct {
-	BankAccountID   int64   `json:"bank_account_id" binding:"required"`                       // ID банковского счета
-	Amount          float64 `json:"amount" binding:"required"`                                // Сумма транзакции
-	Description     string  `json:"description" binding:"required,min=1,max=255"`             // Описание транзакции
-	CategoryID      *int64  `json:"category_id"`                                              // ID категории (может быть null)
-	TransactionType string  `json:"transaction_type" binding:"required,oneof=income expense"` // Тип: доход или расход
+	BankAccountID   int64                     `json:"bank_account_id" binding:"required"`                       // ID банковского счета
+	Amount          Money                     `json:"amount" binding:"required"`                                // Сумма транзакции
+	Description     string                    `json:"description" binding:"required,min=1,max=255"`             // Описание транзакции
+	CategoryID      *int64                    `json:"category_id"`                                              // ID категории (может быть null)
+	TransactionType string                    `json:"transaction_type" binding:"required,oneof=income expense"` // Тип: доход или расход
+	Date            *string                   `json:"date"`                                                     // "2024-10-01" или "2024-10-01T15:04:05" в таймзоне аккаунта, RFC3339; если нет - сейчас
+	IsPlanned       bool                      `json:"is_planned"`                                               // запланированная транзакция, только для нее дата может быть в будущем
+	Splits          []TransactionSplitRequest `json:"splits" binding:"omitempty,dive"`                          // разбивка по категориям, сумма = amount
+}
+
+// TransactionSplitRequest - строка разбивки в запросе
+type TransactionSplitRequest struct {
+	CategoryID  int64  `json:"category_id" binding:"required"`
+	Amount      Money  `json:"amount" binding:"required"`
+	Description string `json:"description" binding:"max=255"`
+}
+
+// UpdateTransactionRequest - запрос на изменение транзакции (переводы не редактируются)
+type UpdateTransactionRequest struct {
+	Amount          Money                     `json:"amount" binding:"required"`
+	Description     string                    `json:"description" binding:"required,min=1,max=255"`
+	CategoryID      *int64                    `json:"category_id"`
+	TransactionType string                    `json:"transaction_type" binding:"required,oneof=income expense"`
+	Date            *string                   `json:"date"`                            // "2024-10-01" или "2024-10-01T15:04:05" в таймзоне аккаунта, RFC3339; если нет - дата не меняется
+	Splits          []TransactionSplitRequest `json:"splits" binding:"omitempty,dive"` // если нет - разбивка сохраняется, [] - удаляется
+	IsPlanned       *bool                     `json:"is_planned"`                      // false - провести запланированную транзакцию; если нет - не меняется
+}
+
+// RecurringTransactionRequest - запрос на создание/изменение повторяющейся транзакции
+type RecurringTransactionRequest struct {
+	BankAccountID   int64   `json:"bank_account_id" binding:"required"`
+	Amount          Money   `json:"amount" binding:"required"`
+	Description     string  `json:"description" binding:"required,min=1,max=255"`
+	CategoryID      *int64  `json:"category_id"`
+	TransactionType string  `json:"transaction_type" binding:"required,oneof=income expense"`
+	Frequency       string  `json:"frequency" binding:"required,oneof=daily weekly monthly yearly"`
+	Interval        int     `json:"interval" binding:"omitempty,min=1,max=365"` // по умолчанию 1
+	DayOfMonth      *int    `json:"day_of_month" binding:"omitempty,min=1,max=31"`
+	StartDate       string  `json:"start_date" binding:"required"` // "2024-10-01"
+	EndDate         *string `json:"end_date"`                      // "2025-10-01", необязательно
+	IsActive        *bool   `json:"is_active"`                     // только при изменении
+}
+
+// ImportStatementRequest - параметры импорта выписки (multipart form вместе с файлом)
+// колонки задаются именем из строки заголовка или номером, начиная с 1 (только для csv)
+type ImportStatementRequest struct {
+	Format            string `form:"format" binding:"omitempty,oneof=csv ofx qfx qif camt053 camt052"` // по умолчанию csv
+	DateColumn        string `form:"date_column"`
+	AmountColumn      string `form:"amount_column"` // отрицательные суммы - расходы
+	DescriptionColumn string `form:"description_column"`
+	CategoryColumn    string `form:"category_column"`                                 // необязательно, имя категории пользователя
+	DateFormat        string `form:"date_format"`                                     // "DD.MM.YYYY", по умолчанию "YYYY-MM-DD" (csv) или американский MM/DD/YY (qif)
+	DecimalSeparator  string `form:"decimal_separator" binding:"omitempty,oneof=. ,"` // по умолчанию "."
+	Delimiter         string `form:"delimiter"`                                       // по умолчанию ",", можно ";" или "tab"
+}
+
+// ImportSummary - итог импорта выписки, номера строк как в файле
+type ImportSummary struct {
+	BankAccountID  int64                    `json:"bank_account_id"`
+	Reconciliation *StatementReconciliation `json:"reconciliation,omitempty"` // только если в выписке есть остатки (camt)
+	Imported       int                      `json:"imported"`
+	Skipped        int                      `json:"skipped"`
+	Failed         int                      `json:"failed"`
+	ImportedRows   []int                    `json:"imported_rows"`
+	SkippedRows    []*ImportRowIssue        `json:"skipped_rows"`
+	FailedRows     []*ImportRowIssue        `json:"failed_rows"`
+}
+
+// StatementReconciliation - сверка остатков выписки с балансом счета после импорта
+type StatementReconciliation struct {
+	IBAN                 string `json:"iban,omitempty"`
+	OpeningBalance       *Money `json:"opening_balance,omitempty"`    // входящий остаток из выписки
+	ClosingBalance       *Money `json:"closing_balance,omitempty"`    // исходящий остаток из выписки
+	LedgerBalance        Money  `json:"ledger_balance"`               // баланс счета после импорта
+	LedgerOpeningBalance Money  `json:"ledger_opening_balance"`       // баланс счета без операций выписки
+	OpeningDifference    *Money `json:"opening_difference,omitempty"` // ledger_opening_balance - opening_balance
+	ClosingDifference    *Money `json:"closing_difference,omitempty"` // ledger_balance - closing_balance
+	IsBalanced           bool   `json:"is_balanced"`
+}
+
+// ImportRowIssue - пропущенная или ошибочная строка выписки
+type ImportRowIssue struct {
+	Row    int    `json:"row"`
+	Reason string `json:"reason"`
 }
 
 // CreateBudgetRequest - запрос на создание бюджета
 type CreateBudgetRequest struct {
-	BudgetName string  `json:"budget_name" binding:"required,min=2,max=100"` // "Продукты на октябрь"
-	CategoryID int64   `json:"category_id" binding:"required"`               // ID категории
-	Amount     float64 `json:"amount" binding:"required,gt=0"`               // Планируемая сумма
-	Month      int     `json:"month" binding:"required,min=1,max=12"`        // Месяц (1-12)
-	Year       int     `json:"year" binding:"required,min=2020"`             // Год
+	BudgetName    string  `json:"budget_name" binding:"required,min=2,max=100"`           // "Продукты на октябрь"
+	CategoryID    *int64  `json:"category_id"`                                            // ID категории
+	CategoryIDs   []int64 `json:"category_ids"`                                           // или несколько категорий
+	AllCategories bool    `json:"all_categories"`                                         // или все категории аккаунта
+	Amount        Money   `json:"amount" binding:"required"`                              // Планируемая сумма, больше нуля
+	Period        string  `json:"period" binding:"omitempty,oneof=monthly weekly yearly"` // по умолчанию monthly
+	Month         int     `json:"month" binding:"omitempty,min=1,max=12"`                 // Месяц (1-12) для monthly без date
+	Year          int     `json:"year" binding:"omitempty,min=2020"`                      // Год для monthly и yearly без date
+	Date          string  `json:"date" binding:"omitempty,datetime=2006-01-02"`           // любой день периода, YYYY-MM-DD
+	Mode          string  `json:"mode" binding:"omitempty,oneof=limit envelope"`          // по умолчанию limit
+	Rollover      bool    `json:"rollover"`                                               // переносить остаток в следующий период
+}
+
+// UpdateBudgetRequest - изменить название, сумму и перенос остатка; период, даты и режим не меняются
+type UpdateBudgetRequest struct {
+	BudgetName string `json:"budget_name" binding:"required,min=2,max=100"`
+	Amount     Money  `json:"amount" binding:"required"` // лимит (больше нуля) или ориентир конверта
+	Rollover   *bool  `json:"rollover"`                  // не передан - не меняется
+}
+
+// CopyBudgetsRequest - скопировать месячные бюджеты одного месяца в другой
+type CopyBudgetsRequest struct {
+	FromYear      int     `json:"from_year" binding:"required,min=2020"`
+	FromMonth     int     `json:"from_month" binding:"required,min=1,max=12"`
+	ToYear        int     `json:"to_year" binding:"required,min=2020"`
+	ToMonth       int     `json:"to_month" binding:"required,min=1,max=12"`
+	AdjustPercent float64 `json:"adjust_percent" binding:"min=-100,max=1000"` // изменение сумм, %: 10 - на 10% больше, -5 - меньше
+}
+
+// CopyBudgetsResult - созданные бюджеты и исходные бюджеты, для набора категорий которых бюджет в целевом месяце уже был
+type CopyBudgetsResult struct {
+	Created []*Budget `json:"created"`
+	Skipped []*Budget `json:"skipped"`
+}
+
+// AssignBudgetRequest - распределить деньги в конверт, отрицательная сумма возвращает их в "к распределению"
+type AssignBudgetRequest struct {
+	BudgetID    int64  `json:"budget_id" binding:"required"`
+	Amount      Money  `json:"amount" binding:"required"`
+	Description string `json:"description" binding:"max=255"`
+}
+
+// MoveBudgetRequest - переместить деньги между конвертами
+type MoveBudgetRequest struct {
+	FromBudgetID int64  `json:"from_budget_id" binding:"required"`
+	ToBudgetID   int64  `json:"to_budget_id" binding:"required"`
+	Amount       Money  `json:"amount" binding:"required"` // больше нуля
+	Description  string `json:"description" binding:"max=255"`
 }
 
 // BudgetWithStatus - бюджет со статусом
@@ -260,30 +774,50 @@ type BudgetWithStatus struct {
 
 // BudgetSummary - общая сводка по всем бюджетам
 type BudgetSummary struct {
-	TotalPlanned   float64             `json:"total_planned"`   // Общая планируемая сумма
-	TotalSpent     float64             `json:"total_spent"`     // Общая потраченная сумма
-	TotalRemaining float64             `json:"total_remaining"` // Общая оставшаяся сумма
-	IsOverBudget   bool                `json:"is_over_budget"`  // Превышен ли общий бюджет
-	Budgets        []*BudgetWithStatus `json:"budgets"`         // Детали по каждому бюджету
+	TotalPlanned      Money               `json:"total_planned"`      // Общая планируемая сумма
+	TotalSpent        Money               `json:"total_spent"`        // Общая потраченная сумма
+	TotalRemaining    Money               `json:"total_remaining"`    // Общая оставшаяся сумма
+	TotalInstallments Money               `json:"total_installments"` // Предстоящие платежи по рассрочкам в категориях бюджетов
+	TotalCarriedIn    Money               `json:"total_carried_in"`   // Перенесено из прошлых периодов
+	TotalAvailable    Money               `json:"total_available"`    // Доступно с учетом переносов и конвертов
+	ToBeAssigned      Money               `json:"to_be_assigned"`     // Доход, еще не распределенный по конвертам
+	IsOverBudget      bool                `json:"is_over_budget"`     // Превышен ли общий бюджет
+	Budgets           []*BudgetWithStatus `json:"budgets"`            // Детали по каждому бюджету
 }
 
 type TransferRequest struct {
-	FromAccountID int64   `json:"from_account_id" binding:"required"`           // Откуда переводим
-	ToAccountID   int64   `json:"to_account_id" binding:"required"`             // Куда переводим
-	Amount        float64 `json:"amount" binding:"required"`                    // Сумма перевода
-	Description   string  `json:"description" binding:"required,min=1,max=255"` // Описание перевода
+	FromAccountID  int64    `json:"from_account_id" binding:"required"`           // Откуда переводим
+	ToAccountID    int64    `json:"to_account_id" binding:"required"`             // Куда переводим
+	Amount         Money    `json:"amount" binding:"required"`                    // Сумма перевода в валюте счета-источника
+	Description    string   `json:"description" binding:"required,min=1,max=255"` // Описание перевода
+	Rate           *float64 `json:"rate" binding:"omitempty,gt=0"`                // Курс: сколько единиц валюты получателя за единицу валюты источника
+	ReceivedAmount *Money   `json:"received_amount"`                              // Или сумма зачисления в валюте получателя
+}
+
+// TransferResult - итог перевода: обе суммы и примененный курс
+type TransferResult struct {
+	FromAccountID  int64   `json:"from_account_id"`
+	ToAccountID    int64   `json:"to_account_id"`
+	Amount         Money   `json:"amount"` // Списано со счета-источника
+	FromCurrency   string  `json:"from_currency"`
+	ReceivedAmount Money   `json:"received_amount"` // Зачислено на счет получателя
+	ToCurrency     string  `json:"to_currency"`
+	Rate           float64 `json:"rate"`        // Фактический курс received_amount / amount
+	RateSource     string  `json:"rate_source"` // "same_currency", "request", "received_amount", "stored"
 }
 
 type TransactionResponse struct {
-	ID              int64   `json:"id"`
-	BankAccountID   int64   `json:"bank_account_id"`
-	CategoryID      *int64  `json:"category_id"`
-	Amount          float64 `json:"amount"`
-	Description     string  `json:"description"`
-	TransactionType string  `json:"transaction_type"`
-	Date            string  `json:"date"`
-	CreatedAt       string  `json:"created_at"`
-	UpdatedAt       string  `json:"updated_at"`
+	ID              int64               `json:"id"`
+	BankAccountID   int64               `json:"bank_account_id"`
+	CategoryID      *int64              `json:"category_id"`
+	Amount          Money               `json:"amount"`
+	Description     string              `json:"description"`
+	TransactionType string              `json:"transaction_type"`
+	IsPlanned       bool                `json:"is_planned"`
+	Splits          []*TransactionSplit `json:"splits,omitempty"`
+	Date            string              `json:"date"`
+	CreatedAt       string              `json:"created_at"`
+	UpdatedAt       string              `json:"updated_at"`
 }
 
 type ErrorResponse struct {
@@ -17,14 +17,16 @@ func NewAccountRepository(db *sql.DB) *AccountRepository {
 }
 func (r *AccountRepository) Create(account *models.Account) (*models.Account, error) {
 	query := ` 
-	insert into accounts (user_id, name, display_name, timezone, is_active ,created_at, updated_at )
-	values ($1, $2, $3, $4, $5, $6, $7)
+	insert into accounts (user_id, name, display_name, timezone, base_currency, week_start_day, is_active ,created_at, updated_at )
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	returning id;`
 	err := r.db.QueryRow(query,
 		account.UserID,
 		account.Name,
 		account.DisplayName,
 		account.Timezone,
+		account.BaseCurrency,
+		account.WeekStartDay,
 		account.IsActive,
 		account.CreatedAt,
 		account.UpdatedAt).Scan(&account.ID)
@@ -37,7 +39,7 @@ func (r *AccountRepository) Create(account *models.Account) (*models.Account, er
 
 func (r *AccountRepository) GetByUserID(userID string) (*models.Account, error) {
 	query := `
-    SELECT id, user_id, name, display_name, timezone, is_active, created_at, updated_at 
+    SELECT id, user_id, name, display_name, timezone, base_currency, week_start_day, is_active, created_at, updated_at 
     FROM accounts
     WHERE user_id = $1 AND is_active = true`
 
@@ -48,6 +50,8 @@ func (r *AccountRepository) GetByUserID(userID string) (*models.Account, error)
 		&account.Name,
 		&account.DisplayName,
 		&account.Timezone,
+		&account.BaseCurrency,
+		&account.WeekStartDay,
 		&account.IsActive,
 		&account.CreatedAt,
 		&account.UpdatedAt,
@@ -64,7 +68,7 @@ func (r *AccountRepository) GetByUserID(userID string) (*models.Account, error)
 }
 func (r *AccountRepository) GetByID(id int64) (*models.Account, error) {
 	query := `
-	select id, user_id, name, display_name, timezone, is_active, created_at, updated_at 
+	select id, user_id, name, display_name, timezone, base_currency, week_start_day, is_active, created_at, updated_at 
 	from accounts 
 	where id = $1`
 	account := &models.Account{}
@@ -75,6 +79,8 @@ func (r *AccountRepository) GetByID(id int64) (*models.Account, error) {
 		&account.Name,
 		&account.DisplayName,
 		&account.Timezone,
+		&account.BaseCurrency,
+		&account.WeekStartDay,
 		&account.IsActive,
 		&account.CreatedAt,
 		&account.UpdatedAt,
@@ -88,3 +94,51 @@ func (r *AccountRepository) GetByID(id int64) (*models.Account, error) {
 	return account, nil
 
 }
+
+// Update сохраняет изменяемые поля профиля: отображаемое имя, таймзону, базовую валюту и первый день недели
+func (r *AccountRepository) Update(account *models.Account) (*models.Account, error) {
+	return r.update(account, false)
+}
+
+// UpdateAndRebuildBalances сохраняет аккаунт и пересчитывает дневные остатки его счетов одной транзакцией БД
+// нужно при смене таймзоны: границы дней меняются вместе с ней или не меняются вовсе
+func (r *AccountRepository) UpdateAndRebuildBalances(account *models.Account) (*models.Account, error) {
+	return r.update(account, true)
+}
+
+func (r *AccountRepository) update(account *models.Account, withBalances bool) (*models.Account, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update account: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+	update accounts
+	set display_name = $1, timezone = $2, base_currency = $3, week_start_day = $4, updated_at = $5
+	where id = $6
+	returning updated_at`
+	err = tx.QueryRow(query,
+		account.DisplayName,
+		account.Timezone,
+		account.BaseCurrency,
+		account.WeekStartDay,
+		account.UpdatedAt,
+		account.ID,
+	).Scan(&account.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to update account: %w", err)
+	}
+	if withBalances {
+		if err := rebuildBalances(tx, account.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to update account: %w", err)
+	}
+	return account, nil
+}
@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"justTest/internal/models"
+	"time"
+)
+
+// BalanceRepository - остатки счетов: текущий (bank_account_balances) и на конец каждого дня (bank_account_daily_balances)
+// таблицы обновляет TransactionRepository в той же транзакции БД, что и сами транзакции (applyBalanceChange)
+type BalanceRepository struct {
+	db *sql.DB
+}
+
+func NewBalanceRepository(db *sql.DB) *BalanceRepository {
+	return &BalanceRepository{
+		db: db,
+	}
+}
+
+// applyBalanceChange добавляет amount к остатку счета и ко всем остаткам на конец дня начиная с дня date
+// запланированные транзакции на остатки не влияют
+func applyBalanceChange(tx *sql.Tx, bankAccountID int64, amount models.Money, date time.Time, isPlanned bool) error {
+	if isPlanned || amount.IsZero() {
+		return nil
+	}
+	// upsert текущего остатка первым: блокировка строки упорядочивает изменения одного счета
+	_, err := tx.Exec(`
+	insert into bank_account_balances (bank_account_id, balance, currency, last_updated)
+	select id, $2, currency, $3 from bank_accounts where id = $1
+	on conflict (bank_account_id)
+	do update set balance = bank_account_balances.balance + excluded.balance, last_updated = excluded.last_updated`,
+		bankAccountID, amount, time.Now())
+	if err != nil {
+		return fmt.Errorf("error updating balance: %v", err)
+	}
+
+	var day string
+	// день - в таймзоне аккаунта, как и границы месяцев в отчетах
+	err = tx.QueryRow(`
+	select to_char(($2::timestamptz at time zone coalesce(a.timezone, 'Asia/Almaty'))::date, 'YYYY-MM-DD')
+	from bank_accounts ba
+	    join accounts a on a.id = ba.account_id
+	where ba.id = $1`, bankAccountID, date).Scan(&day)
+	if err != nil {
+		return fmt.Errorf("error getting balance date: %v", err)
+	}
+	// новый день начинается с остатка на конец предыдущего дня с транзакциями
+	_, err = tx.Exec(`
+	insert into bank_account_daily_balances (bank_account_id, balance_date, balance)
+	values ($1, $2::date, coalesce((
+	    select balance from bank_account_daily_balances
+	    where bank_account_id = $1 and balance_date < $2::date
+	    order by balance_date desc
+	    limit 1), 0))
+	on conflict (bank_account_id, balance_date) do nothing`, bankAccountID, day)
+	if err != nil {
+		return fmt.Errorf("error creating daily balance: %v", err)
+	}
+	_, err = tx.Exec(`
+	update bank_account_daily_balances set balance = balance + $3
+	where bank_account_id = $1 and balance_date >= $2::date`, bankAccountID, day, amount)
+	if err != nil {
+		return fmt.Errorf("error updating daily balances: %v", err)
+	}
+	return nil
+}
+
+// GetDailyBalances - остатки на конец дня за [from, to] (даты в таймзоне аккаунта) по возрастанию даты
+// первым идет последний остаток до from, если в from не было транзакций; дни без транзакций не возвращаются
+func (r *BalanceRepository) GetDailyBalances(bankAccountID int64, from, to time.Time) ([]*models.DailyBalance, error) {
+	query := `
+	select balance_date, balance
+	from bank_account_daily_balances
+	where bank_account_id = $1
+	    and balance_date >= coalesce((
+	        select max(balance_date) from bank_account_daily_balances
+	        where bank_account_id = $1 and balance_date <= $2::date), $2::date)
+	    and balance_date <= $3::date
+	order by balance_date`
+	rows, err := r.db.Query(query, bankAccountID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("error getting daily balances: %v", err)
+	}
+	defer rows.Close()
+	balances := make([]*models.DailyBalance, 0)
+	for rows.Next() {
+		balance := &models.DailyBalance{}
+		if err := rows.Scan(&balance.Date, &balance.Balance); err != nil {
+			return balances, fmt.Errorf("error scanning daily balance: %v", err)
+		}
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
+
+// RebuildBalances пересчитывает текущие и дневные остатки счетов аккаунта по всем транзакциям, accountID = 0 - всех аккаунтов
+func (r *BalanceRepository) RebuildBalances(accountID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error rebuilding balances: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err = rebuildBalances(tx, accountID); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing balances: %v", err)
+	}
+	return nil
+}
+
+// rebuildBalances пересчитывает остатки внутри транзакции БД tx, дни - в текущей таймзоне аккаунта из tx
+func rebuildBalances(tx *sql.Tx, accountID int64) error {
+	_, err := tx.Exec(`
+	delete from bank_account_daily_balances
+	where bank_account_id in (select id from bank_accounts where $1::bigint = 0 or account_id = $1)`, accountID)
+	if err != nil {
+		return fmt.Errorf("error deleting daily balances: %v", err)
+	}
+	_, err = tx.Exec(`
+	insert into bank_account_daily_balances (bank_account_id, balance_date, balance)
+	select bank_account_id, balance_date, sum(sum(amount)) over (partition by bank_account_id order by balance_date)
+	from (
+	    select t.bank_account_id, t.amount, (t.date at time zone coalesce(a.timezone, 'Asia/Almaty'))::date as balance_date
+	    from transactions t
+	        join bank_accounts ba on ba.id = t.bank_account_id
+	        join accounts a on a.id = ba.account_id
+	    where t.is_planned = false
+	        and ($1::bigint = 0 or ba.account_id = $1)
+	) lines
+	group by bank_account_id, balance_date`, accountID)
+	if err != nil {
+		return fmt.Errorf("error rebuilding daily balances: %v", err)
+	}
+	_, err = tx.Exec(`
+	insert into bank_account_balances (bank_account_id, balance, currency, last_updated)
+	select ba.id, coalesce(sum(t.amount) filter (where t.is_planned = false), 0), ba.currency, $2
+	from bank_accounts ba
+	    left join transactions t on t.bank_account_id = ba.id
+	where $1::bigint = 0 or ba.account_id = $1
+	group by ba.id, ba.currency
+	on conflict (bank_account_id)
+	do update set balance = excluded.balance, currency = excluded.currency, last_updated = excluded.last_updated`,
+		accountID, time.Now())
+	if err != nil {
+		return fmt.Errorf("error rebuilding balances: %v", err)
+	}
+	return nil
+}
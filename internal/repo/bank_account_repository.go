@@ -16,18 +16,31 @@ func NewBankAccountRepository(db *sql.DB) *BankAccountRepository {
 	}
 }
 func (r *BankAccountRepository) Create(bankAccount *models.BankAccount) (*models.BankAccount, error) {
+	return r.CreateWithOpeningBalance(bankAccount, nil)
+}
+
+// CreateWithOpeningBalance создает счет и его начальный остаток (транзакцию opening_balance) в одной транзакции БД
+// opening = nil - счет без начального остатка
+func (r *BankAccountRepository) CreateWithOpeningBalance(bankAccount *models.BankAccount, opening *models.Transaction) (*models.BankAccount, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating bank account: %v", err)
+	}
+	defer tx.Rollback()
+
 	query := `
-		insert into bank_accounts  ( account_id, name, currency, account_type, bank_name, is_active, created_at, updated_at)
-		values ($1, $2, $3, $4, $5, $6, $7, $8)
+		insert into bank_accounts  ( account_id, name, currency, account_type, bank_name, iban, is_active, created_at, updated_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		returning id;
 		
 `
-	err := r.db.QueryRow(query,
+	err = tx.QueryRow(query,
 		bankAccount.AccountID,
 		bankAccount.Name,
 		bankAccount.Currency,
 		bankAccount.AccountType,
 		bankAccount.BankName,
+		bankAccount.IBAN,
 		bankAccount.IsActive,
 		bankAccount.CreatedAt,
 		bankAccount.UpdatedAt,
@@ -36,12 +49,21 @@ func (r *BankAccountRepository) Create(bankAccount *models.BankAccount) (*models
 		return nil, fmt.Errorf("Error creating bank account: %v", err)
 
 	}
+	if opening != nil {
+		opening.BankAccountID = bankAccount.ID
+		if err = insertTransaction(tx, opening); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Error committing bank account: %v", err)
+	}
 	return bankAccount, nil
 }
 
 func (r *BankAccountRepository) GetByAccountID(accountID int64) ([]*models.BankAccount, error) {
 	query := `
-	select id, account_id, name, currency, account_type, bank_name, is_active, created_at, updated_at
+	select id, account_id, name, currency, account_type, bank_name, iban, is_active, created_at, updated_at
 	from bank_accounts 
 	where account_id = $1;
 `
@@ -60,6 +82,7 @@ func (r *BankAccountRepository) GetByAccountID(accountID int64) ([]*models.BankA
 			&account.Currency,
 			&account.AccountType,
 			&account.BankName,
+			&account.IBAN,
 			&account.IsActive,
 			&account.CreatedAt,
 			&account.UpdatedAt,
@@ -75,7 +98,7 @@ func (r *BankAccountRepository) GetByAccountID(accountID int64) ([]*models.BankA
 
 func (r *BankAccountRepository) GetActiveBankAccounts(accountID int64) ([]*models.BankAccount, error) {
 	query := `
-	select id, account_id, name, currency, account_type, bank_name, is_active, created_at, updated_at
+	select id, account_id, name, currency, account_type, bank_name, iban, is_active, created_at, updated_at
 	from bank_accounts
 	where account_id = $1 and is_active = true;
 `
@@ -94,6 +117,7 @@ func (r *BankAccountRepository) GetActiveBankAccounts(accountID int64) ([]*model
 			&account.Currency,
 			&account.AccountType,
 			&account.BankName,
+			&account.IBAN,
 			&account.IsActive,
 			&account.CreatedAt,
 			&account.UpdatedAt,
@@ -109,7 +133,7 @@ func (r *BankAccountRepository) GetActiveBankAccounts(accountID int64) ([]*model
 }
 func (r *BankAccountRepository) GetBankAccountByCurrency(accountID int64, currency string) ([]*models.BankAccount, error) {
 	query := `
-select id, account_id, name, currency, account_type, bank_name, is_active, created_at, updated_at
+select id, account_id, name, currency, account_type, bank_name, iban, is_active, created_at, updated_at
 from bank_accounts
 where account_id = $1 and currency = $2;
 `
@@ -128,6 +152,7 @@ where account_id = $1 and currency = $2;
 			&account.Currency,
 			&account.AccountType,
 			&account.BankName,
+			&account.IBAN,
 
 			&account.IsActive,
 			&account.CreatedAt,
@@ -145,7 +170,7 @@ where account_id = $1 and currency = $2;
 }
 func (r *BankAccountRepository) GetByBankAccountID(BankAccountID int64) (*models.BankAccount, error) {
 	query := `
-		select id, account_id, name, currency, account_type, bank_name, is_active, created_at, updated_at
+		select id, account_id, name, currency, account_type, bank_name, iban, is_active, created_at, updated_at
 from bank_accounts
 where id = $1; `
 	bankAccount := &models.BankAccount{}
@@ -156,6 +181,7 @@ where id = $1; `
 		&bankAccount.Currency,
 		&bankAccount.AccountType,
 		&bankAccount.BankName,
+		&bankAccount.IBAN,
 		&bankAccount.IsActive,
 		&bankAccount.CreatedAt,
 		&bankAccount.UpdatedAt,
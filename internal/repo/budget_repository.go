@@ -4,6 +4,9 @@ import (
 	"database/sql"
 	"fmt"
 	"justTest/internal/models"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type BudgetRepository struct {
@@ -16,36 +19,62 @@ func NewBudgetRepository(db *sql.DB) *BudgetRepository {
 	}
 }
 
-func (r *BudgetRepository) CreateBudget(budget *models.Budget) (*models.Budget, error) {
+// insertBudget сохраняет бюджет и его категории в транзакции БД tx
+func insertBudget(tx *sql.Tx, budget *models.Budget) error {
 	query := ` insert into budgets (
                      account_id , budget_limit_name, category_id, amount, 
-                     period, start_date, end_date,is_active, created_at, updated_at
+                     period, mode, rollover, all_categories, start_date, end_date,is_active, created_at, updated_at
  ) 
- values ($1, $2,$3,$4,$5,$6,$7,$8,$9, $10)
+ values ($1, $2,$3,$4,$5,$6,$7,$8,$9, $10, $11, $12, $13)
  returning id `
-
-	err := r.db.QueryRow(query,
+	err := tx.QueryRow(query,
 		budget.AccountID,
 		budget.BudgetLimitName,
 		budget.CategoryID,
 		budget.Amount,
 		budget.Period,
+		budget.Mode,
+		budget.Rollover,
+		budget.AllCategories,
 		budget.StartDate,
 		budget.EndDate,
 		budget.IsActive,
 		budget.CreatedAt,
 		budget.UpdatedAt,
 	).Scan(&budget.ID)
+	if err != nil {
+		return err
+	}
+	for _, categoryID := range budget.CategoryIDs {
+		_, err := tx.Exec(`insert into budget_categories (budget_id, category_id) values ($1, $2)`, budget.ID, categoryID)
+		if err != nil {
+			return fmt.Errorf("error adding category %d to budget: %v", categoryID, err)
+		}
+	}
+	return nil
+}
+
+func (r *BudgetRepository) CreateBudget(budget *models.Budget) (*models.Budget, error) {
+	tx, err := r.db.Begin()
 	if err != nil {
 		return nil, err
+	}
+	defer tx.Rollback()
 
+	if err := insertBudget(tx, budget); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
 	}
 	return budget, nil
 
 }
 func (r *BudgetRepository) GetBudget(budgetID int64) (*models.Budget, error) {
 	query := ` select id , account_id , budget_limit_name, category_id, amount, 
-                     period, start_date, end_date,is_active, created_at, updated_at from budgets where id = $1`
+                     period, mode, rollover, all_categories,
+		       array(select bc.category_id from budget_categories bc where bc.budget_id = budgets.id order by bc.category_id),
+		       start_date, end_date,is_active, created_at, updated_at from budgets where id = $1`
 	row := r.db.QueryRow(query, budgetID)
 	budget := &models.Budget{}
 	err := row.Scan(&budget.ID,
@@ -54,6 +83,10 @@ func (r *BudgetRepository) GetBudget(budgetID int64) (*models.Budget, error) {
 		&budget.CategoryID,
 		&budget.Amount,
 		&budget.Period,
+		&budget.Mode,
+		&budget.Rollover,
+		&budget.AllCategories,
+		pq.Array(&budget.CategoryIDs),
 		&budget.StartDate,
 		&budget.EndDate,
 		&budget.IsActive,
@@ -67,42 +100,69 @@ func (r *BudgetRepository) GetBudget(budgetID int64) (*models.Budget, error) {
 	}
 	return budget, nil
 }
-func (r *BudgetRepository) GetBudgetByCategoryID(categoryID int64) (*models.Budget, error) {
-	query := ` select id , account_id , budget_limit_name, category_id, amount, 
-                     period, start_date, end_date,is_active, created_at, updated_at from budgets where category_id = $1`
-	row := r.db.QueryRow(query, categoryID)
-	budget := &models.Budget{}
-	err := row.Scan(&budget.ID,
-		&budget.AccountID,
-		&budget.BudgetLimitName,
-		&budget.CategoryID,
-		&budget.Amount,
-		&budget.Period,
-		&budget.StartDate,
-		&budget.EndDate,
-		&budget.IsActive,
-		&budget.CreatedAt,
-		&budget.UpdatedAt)
+func (r *BudgetRepository) GetBudgetsByCategoryAndDate(categoryID int64, date time.Time) ([]*models.Budget, error) {
+	query := `
+		SELECT id, account_id, budget_limit_name, category_id, amount, 
+		       period, mode, rollover, all_categories,
+		       array(select bc.category_id from budget_categories bc where bc.budget_id = budgets.id order by bc.category_id),
+		       start_date, end_date, is_active, created_at, updated_at 
+		FROM budgets 
+		WHERE (EXISTS (SELECT 1 FROM budget_categories bc WHERE bc.budget_id = budgets.id AND bc.category_id = $1)
+		    OR (all_categories AND account_id = (SELECT account_id FROM categories WHERE id = $1)))
+		AND start_date <= $2::date
+		AND end_date >= $2::date
+		AND is_active = true
+		ORDER BY end_date - start_date, all_categories,
+		         (SELECT count(*) FROM budget_categories bc WHERE bc.budget_id = budgets.id), id
+	`
+	rows, err := r.db.Query(query, categoryID, date.Format("2006-01-02"))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf(`no budget found with id %d`, categoryID)
-		}
-		return nil, err
+		return nil, fmt.Errorf("error getting budgets by category and date: %v", err)
 	}
-	return budget, nil
+	defer rows.Close()
+	return scanBudgets(rows)
+}
+
+// GetBudgetsByAccountAndDate - активные бюджеты аккаунта, в период которых попадает календарная дата date
+func (r *BudgetRepository) GetBudgetsByAccountAndDate(accountID int64, date time.Time) ([]*models.Budget, error) {
+	query := `
+		SELECT id, account_id, budget_limit_name, category_id, amount, 
+		       period, mode, rollover, all_categories,
+		       array(select bc.category_id from budget_categories bc where bc.budget_id = budgets.id order by bc.category_id),
+		       start_date, end_date, is_active, created_at, updated_at 
+		FROM budgets 
+		WHERE account_id = $1 
+		AND start_date <= $2::date
+		AND end_date >= $2::date
+		AND is_active = true
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(query, accountID, date.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("error getting budgets by account and date: %v", err)
+	}
+	defer rows.Close()
+	return scanBudgets(rows)
 }
 
-func (r *BudgetRepository) GetBudgetByCategoryAndMonth(categoryID int64, year, month int) (*models.Budget, error) {
+// GetOverlappingBudget - активный бюджет одной категории (categoryID) или по всем категориям аккаунта (categoryID = nil)
+// с тем же периодом, пересекающийся с [startDate, endDate]; недели могут пересекаться, если первый день недели аккаунта поменялся
+func (r *BudgetRepository) GetOverlappingBudget(accountID int64, categoryID *int64, period string, startDate, endDate time.Time) (*models.Budget, error) {
 	query := `
 		SELECT id, account_id, budget_limit_name, category_id, amount, 
-		       period, start_date, end_date, is_active, created_at, updated_at 
+		       period, mode, rollover, all_categories,
+		       array(select bc.category_id from budget_categories bc where bc.budget_id = budgets.id order by bc.category_id),
+		       start_date, end_date, is_active, created_at, updated_at 
 		FROM budgets 
-		WHERE category_id = $1 
-		AND EXTRACT(YEAR FROM start_date) = $2 
-		AND EXTRACT(MONTH FROM start_date) = $3
+		WHERE account_id = $1
+		AND (category_id = $2 OR ($2::bigint IS NULL AND all_categories))
+		AND period = $3
+		AND start_date <= $5::date
+		AND end_date >= $4::date
 		AND is_active = true
+		LIMIT 1
 	`
-	row := r.db.QueryRow(query, categoryID, year, month)
+	row := r.db.QueryRow(query, accountID, categoryID, period, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
 	budget := &models.Budget{}
 	err := row.Scan(
 		&budget.ID,
@@ -111,6 +171,10 @@ func (r *BudgetRepository) GetBudgetByCategoryAndMonth(categoryID int64, year, m
 		&budget.CategoryID,
 		&budget.Amount,
 		&budget.Period,
+		&budget.Mode,
+		&budget.Rollover,
+		&budget.AllCategories,
+		pq.Array(&budget.CategoryIDs),
 		&budget.StartDate,
 		&budget.EndDate,
 		&budget.IsActive,
@@ -119,30 +183,14 @@ func (r *BudgetRepository) GetBudgetByCategoryAndMonth(categoryID int64, year, m
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("no budget found for category %d in %d-%02d", categoryID, year, month)
+			return nil, fmt.Errorf("no overlapping %s budget found", period)
 		}
 		return nil, err
 	}
 	return budget, nil
 }
 
-func (r *BudgetRepository) GetBudgetsByAccountAndMonth(accountID int64, year, month int) ([]*models.Budget, error) {
-	query := `
-		SELECT id, account_id, budget_limit_name, category_id, amount, 
-		       period, start_date, end_date, is_active, created_at, updated_at 
-		FROM budgets 
-		WHERE account_id = $1 
-		AND EXTRACT(YEAR FROM start_date) = $2 
-		AND EXTRACT(MONTH FROM start_date) = $3
-		AND is_active = true
-		ORDER BY created_at DESC
-	`
-	rows, err := r.db.Query(query, accountID, year, month)
-	if err != nil {
-		return nil, fmt.Errorf("error getting budgets by account and month: %v", err)
-	}
-	defer rows.Close()
-
+func scanBudgets(rows *sql.Rows) ([]*models.Budget, error) {
 	var budgets []*models.Budget
 	for rows.Next() {
 		budget := &models.Budget{}
@@ -153,6 +201,10 @@ func (r *BudgetRepository) GetBudgetsByAccountAndMonth(accountID int64, year, mo
 			&budget.CategoryID,
 			&budget.Amount,
 			&budget.Period,
+			&budget.Mode,
+			&budget.Rollover,
+			&budget.AllCategories,
+			pq.Array(&budget.CategoryIDs),
 			&budget.StartDate,
 			&budget.EndDate,
 			&budget.IsActive,
@@ -167,4 +219,220 @@ func (r *BudgetRepository) GetBudgetsByAccountAndMonth(accountID int64, year, mo
 	return budgets, nil
 }
 
-//func (r *BudgetRepository) UpdateBudget()
+// CreateBudgets создает бюджеты одной транзакцией БД: либо все, либо ни одного
+func (r *BudgetRepository) CreateBudgets(budgets []*models.Budget) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error creating budgets: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, budget := range budgets {
+		if err := insertBudget(tx, budget); err != nil {
+			return fmt.Errorf("error creating budget %q: %v", budget.BudgetLimitName, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing budgets: %v", err)
+	}
+	return nil
+}
+
+// UpdateBudget сохраняет название, сумму и перенос остатка
+func (r *BudgetRepository) UpdateBudget(budget *models.Budget) (*models.Budget, error) {
+	query := `
+	update budgets
+	set budget_limit_name = $1, amount = $2, rollover = $3, updated_at = $4
+	where id = $5
+	returning updated_at`
+	err := r.db.QueryRow(query,
+		budget.BudgetLimitName,
+		budget.Amount,
+		budget.Rollover,
+		budget.UpdatedAt,
+		budget.ID,
+	).Scan(&budget.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no budget found with id %d", budget.ID)
+		}
+		return nil, fmt.Errorf("error updating budget: %v", err)
+	}
+	return budget, nil
+}
+
+// DeleteBudget удаляет бюджет вместе с распределениями конверта, транзакции не затрагиваются
+func (r *BudgetRepository) DeleteBudget(budgetID int64) error {
+	res, err := r.db.Exec(`delete from budgets where id = $1`, budgetID)
+	if err != nil {
+		return fmt.Errorf("error deleting budget: %v", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting budget: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no budget found with id %d", budgetID)
+	}
+	return nil
+}
+
+// GetPreviousBudgets - активные бюджеты аккаунта, закончившиеся до before, от последнего к первому
+// по ним считается перенос остатка (rollover) в бюджеты, начинающиеся не позже before
+func (r *BudgetRepository) GetPreviousBudgets(accountID int64, before time.Time) ([]*models.Budget, error) {
+	query := `
+		SELECT id, account_id, budget_limit_name, category_id, amount, 
+		       period, mode, rollover, all_categories,
+		       array(select bc.category_id from budget_categories bc where bc.budget_id = budgets.id order by bc.category_id),
+		       start_date, end_date, is_active, created_at, updated_at 
+		FROM budgets 
+		WHERE account_id = $1 
+		AND end_date < $2::date
+		AND is_active = true
+		ORDER BY start_date DESC, id
+	`
+	rows, err := r.db.Query(query, accountID, before.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("error getting previous budgets: %v", err)
+	}
+	defer rows.Close()
+	return scanBudgets(rows)
+}
+
+// GetSpentByBudgets - траты бюджетов (по всем их категориям) за их собственные периоды отдельно по валютам счетов
+// траты без категории не учитываются и в бюджетах по всем категориям
+// границы периодов переводятся в таймзону аккаунта в запросе, чтобы посчитать все бюджеты одним запросом
+func (r *BudgetRepository) GetSpentByBudgets(budgetIDs []int64) ([]*models.BudgetSpending, error) {
+	query := `
+	select b.id, ba.currency, COALESCE(SUM(ABS(t.amount)), 0)
+	from budgets b
+	    join accounts a on a.id = b.account_id
+	    join transaction_lines t on t.date >= (b.start_date::timestamp at time zone coalesce(a.timezone, 'Asia/Almaty'))
+	        and t.date < ((b.end_date + 1)::timestamp at time zone coalesce(a.timezone, 'Asia/Almaty'))
+	    join bank_accounts ba on ba.id = t.bank_account_id and ba.account_id = b.account_id
+	where b.id = any($1)
+	    and t.category_id is not null
+	    and (b.all_categories
+	        or exists (select 1 from budget_categories bc where bc.budget_id = b.id and bc.category_id = t.category_id))
+	    and t.transaction_type = 'expense'
+	    and t.is_planned = false
+	group by b.id, ba.currency
+`
+	rows, err := r.db.Query(query, pq.Array(budgetIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error getting spent amount by budgets: %v", err)
+	}
+	defer rows.Close()
+	spending := make([]*models.BudgetSpending, 0)
+	for rows.Next() {
+		item := &models.BudgetSpending{}
+		if err := rows.Scan(&item.BudgetID, &item.Currency, &item.Amount); err != nil {
+			return spending, fmt.Errorf("error scanning spent amount: %v", err)
+		}
+		item.Amount.Currency = item.Currency
+		spending = append(spending, item)
+	}
+	return spending, nil
+}
+
+// CreateAssignments сохраняет распределения одной транзакцией БД (перемещение между конвертами - две строки)
+func (r *BudgetRepository) CreateAssignments(assignments []*models.BudgetAssignment) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error creating budget assignments: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+	insert into budget_assignments (account_id, budget_id, related_budget_id, amount, description, created_at)
+	values ($1, $2, $3, $4, $5, $6)
+	returning id`
+	for _, assignment := range assignments {
+		err := tx.QueryRow(query,
+			assignment.AccountID,
+			assignment.BudgetID,
+			assignment.RelatedBudgetID,
+			assignment.Amount,
+			assignment.Description,
+			assignment.CreatedAt,
+		).Scan(&assignment.ID)
+		if err != nil {
+			return fmt.Errorf("error creating budget assignment: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing budget assignments: %v", err)
+	}
+	return nil
+}
+
+// GetAssignments - распределения конверта, новые первыми
+func (r *BudgetRepository) GetAssignments(budgetID int64) ([]*models.BudgetAssignment, error) {
+	query := `
+	select id, account_id, budget_id, related_budget_id, amount, description, created_at
+	from budget_assignments
+	where budget_id = $1
+	order by created_at desc, id desc`
+	rows, err := r.db.Query(query, budgetID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting budget assignments: %v", err)
+	}
+	defer rows.Close()
+	assignments := make([]*models.BudgetAssignment, 0)
+	for rows.Next() {
+		assignment := &models.BudgetAssignment{}
+		err := rows.Scan(
+			&assignment.ID,
+			&assignment.AccountID,
+			&assignment.BudgetID,
+			&assignment.RelatedBudgetID,
+			&assignment.Amount,
+			&assignment.Description,
+			&assignment.CreatedAt,
+		)
+		if err != nil {
+			return assignments, fmt.Errorf("error scanning budget assignment: %v", err)
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments, nil
+}
+
+// GetAssignedByBudgets - сумма распределений по каждому конверту, конверты без распределений не возвращаются
+func (r *BudgetRepository) GetAssignedByBudgets(budgetIDs []int64) (map[int64]models.Money, error) {
+	query := `
+	select budget_id, sum(amount)
+	from budget_assignments
+	where budget_id = any($1)
+	group by budget_id`
+	rows, err := r.db.Query(query, pq.Array(budgetIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error getting assigned amounts: %v", err)
+	}
+	defer rows.Close()
+	assigned := make(map[int64]models.Money)
+	for rows.Next() {
+		var budgetID int64
+		var amount models.Money
+		if err := rows.Scan(&budgetID, &amount); err != nil {
+			return assigned, fmt.Errorf("error scanning assigned amount: %v", err)
+		}
+		assigned[budgetID] = amount
+	}
+	return assigned, nil
+}
+
+// GetEnvelopeTotals - день начала первого конверта аккаунта (nil, если конвертов нет) и сумма всех распределений
+// перемещения между конвертами в сумме дают ноль
+func (r *BudgetRepository) GetEnvelopeTotals(accountID int64) (*time.Time, models.Money, error) {
+	query := `
+	select
+	    (select min(start_date) from budgets where account_id = $1 and mode = 'envelope' and is_active = true),
+	    coalesce((select sum(amount) from budget_assignments where account_id = $1), 0)`
+	var firstDate *time.Time
+	var assigned models.Money
+	if err := r.db.QueryRow(query, accountID).Scan(&firstDate, &assigned); err != nil {
+		return nil, models.Money{}, fmt.Errorf("error getting envelope totals: %v", err)
+	}
+	return firstDate, assigned, nil
+}
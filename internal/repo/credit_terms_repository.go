@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"justTest/internal/models"
+	"time"
+)
+
+type CreditTermsRepository struct {
+	db *sql.DB
+}
+
+func NewCreditTermsRepository(db *sql.DB) *CreditTermsRepository {
+	return &CreditTermsRepository{
+		db: db,
+	}
+}
+
+// Save создает или заменяет условия кредитного счета, отметка о напоминании сохраняется
+func (r *CreditTermsRepository) Save(terms *models.CreditTerms) (*models.CreditTerms, error) {
+	query := `
+	insert into credit_terms (bank_account_id, credit_limit, statement_day, payment_due_day,
+	                          interest_rate, min_payment_percent, created_at, updated_at)
+	values ($1, $2, $3, $4, $5, $6, $7, $8)
+	on conflict (bank_account_id)
+	do update set credit_limit = excluded.credit_limit, statement_day = excluded.statement_day,
+	              payment_due_day = excluded.payment_due_day, interest_rate = excluded.interest_rate,
+	              min_payment_percent = excluded.min_payment_percent, updated_at = excluded.updated_at
+	returning created_at, last_reminder_date`
+	err := r.db.QueryRow(query,
+		terms.BankAccountID,
+		terms.CreditLimit,
+		terms.StatementDay,
+		terms.PaymentDueDay,
+		terms.InterestRate,
+		terms.MinPaymentPercent,
+		terms.CreatedAt,
+		terms.UpdatedAt,
+	).Scan(&terms.CreatedAt, &terms.LastReminderDate)
+	if err != nil {
+		return nil, fmt.Errorf("error saving credit terms: %v", err)
+	}
+	return terms, nil
+}
+
+// GetByBankAccountID возвращает sql.ErrNoRows, если условия счета не заданы
+func (r *CreditTermsRepository) GetByBankAccountID(bankAccountID int64) (*models.CreditTerms, error) {
+	query := `
+	select bank_account_id, credit_limit, statement_day, payment_due_day, interest_rate,
+	       min_payment_percent, last_reminder_date, created_at, updated_at
+	from credit_terms where bank_account_id = $1`
+	terms := &models.CreditTerms{}
+	err := r.db.QueryRow(query, bankAccountID).Scan(
+		&terms.BankAccountID,
+		&terms.CreditLimit,
+		&terms.StatementDay,
+		&terms.PaymentDueDay,
+		&terms.InterestRate,
+		&terms.MinPaymentPercent,
+		&terms.LastReminderDate,
+		&terms.CreatedAt,
+		&terms.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting credit terms: %v", err)
+	}
+	return terms, nil
+}
+
+// GetAll - условия всех активных кредитных счетов, для напоминаний о платежах
+func (r *CreditTermsRepository) GetAll() ([]*models.CreditTerms, error) {
+	query := `
+	select ct.bank_account_id, ct.credit_limit, ct.statement_day, ct.payment_due_day, ct.interest_rate,
+	       ct.min_payment_percent, ct.last_reminder_date, ct.created_at, ct.updated_at
+	from credit_terms ct
+	    join bank_accounts ba on ba.id = ct.bank_account_id
+	where ba.is_active = true and ba.account_type = 'credit'
+	order by ct.bank_account_id`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error getting credit terms: %v", err)
+	}
+	defer rows.Close()
+	result := make([]*models.CreditTerms, 0)
+	for rows.Next() {
+		terms := &models.CreditTerms{}
+		err := rows.Scan(
+			&terms.BankAccountID,
+			&terms.CreditLimit,
+			&terms.StatementDay,
+			&terms.PaymentDueDay,
+			&terms.InterestRate,
+			&terms.MinPaymentPercent,
+			&terms.LastReminderDate,
+			&terms.CreatedAt,
+			&terms.UpdatedAt,
+		)
+		if err != nil {
+			return result, fmt.Errorf("error scanning credit terms: %v", err)
+		}
+		result = append(result, terms)
+	}
+	return result, nil
+}
+
+// MarkReminderSent запоминает срок платежа, о котором уже отправлено напоминание
+func (r *CreditTermsRepository) MarkReminderSent(bankAccountID int64, dueDate time.Time) error {
+	query := `update credit_terms set last_reminder_date = $2::date where bank_account_id = $1`
+	_, err := r.db.Exec(query, bankAccountID, dueDate.Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("error marking payment reminder: %v", err)
+	}
+	return nil
+}
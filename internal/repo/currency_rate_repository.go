@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"justTest/internal/models"
+	"time"
+)
+
+type CurrencyRateRepository struct {
+	db *sql.DB
+}
+
+func NewCurrencyRateRepository(db *sql.DB) *CurrencyRateRepository {
+	return &CurrencyRateRepository{
+		db: db,
+	}
+}
+
+// SaveRates записывает курсы одной транзакцией, курс за тот же день перезаписывается
+func (r *CurrencyRateRepository) SaveRates(rates []*models.CurrencyRate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+	insert into currency_rates (from_currency, to_currency, rate, rate_date, source, updated_at)
+	values ($1, $2, $3, $4, $5, $6)
+	on conflict (from_currency, to_currency, rate_date)
+	do update set rate = excluded.rate, source = excluded.source, updated_at = excluded.updated_at`
+	now := time.Now()
+	for _, rate := range rates {
+		_, err := tx.Exec(query,
+			rate.FromCurrency,
+			rate.ToCurrency,
+			rate.Rate,
+			rate.RateDate,
+			rate.Source,
+			now,
+		)
+		if err != nil {
+			return fmt.Errorf("save currency rate %s/%s: %w", rate.FromCurrency, rate.ToCurrency, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit currency rates: %w", err)
+	}
+	return nil
+}
+
+// GetRateOnDate возвращает последний курс from -> to на дату date или раньше, sql.ErrNoRows если его нет
+func (r *CurrencyRateRepository) GetRateOnDate(fromCurrency, toCurrency string, date time.Time) (*models.CurrencyRate, error) {
+	query := `
+	select id, from_currency, to_currency, rate, rate_date, source, updated_at
+	from currency_rates
+	where from_currency = $1 and to_currency = $2 and rate_date <= $3
+	order by rate_date desc
+	limit 1`
+	rate := &models.CurrencyRate{}
+	err := r.db.QueryRow(query, fromCurrency, toCurrency, date.Format("2006-01-02")).Scan(
+		&rate.ID,
+		&rate.FromCurrency,
+		&rate.ToCurrency,
+		&rate.Rate,
+		&rate.RateDate,
+		&rate.Source,
+		&rate.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get currency rate: %w", err)
+	}
+	return rate, nil
+}
@@ -0,0 +1,419 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"justTest/internal/models"
+	"time"
+)
+
+// DebtRepository - люди (counterparties), долги и их возвраты
+// выдача/получение долга и возврат со счетом - транзакции типа debt, они создаются в той же транзакции БД
+type DebtRepository struct {
+	db *sql.DB
+}
+
+func NewDebtRepository(db *sql.DB) *DebtRepository {
+	return &DebtRepository{
+		db: db,
+	}
+}
+
+func (r *DebtRepository) CreateCounterparty(counterparty *models.Counterparty) (*models.Counterparty, error) {
+	query := `
+	insert into counterparties (account_id, name, note, created_at, updated_at)
+	values ($1, $2, $3, $4, $5)
+	returning id`
+	err := r.db.QueryRow(query,
+		counterparty.AccountID,
+		counterparty.Name,
+		counterparty.Note,
+		counterparty.CreatedAt,
+		counterparty.UpdatedAt,
+	).Scan(&counterparty.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating counterparty: %v", err)
+	}
+	return counterparty, nil
+}
+
+func (r *DebtRepository) GetCounterpartyByID(counterpartyID int64) (*models.Counterparty, error) {
+	query := `select id, account_id, name, note, created_at, updated_at from counterparties where id = $1`
+	counterparty := &models.Counterparty{}
+	err := r.db.QueryRow(query, counterpartyID).Scan(
+		&counterparty.ID,
+		&counterparty.AccountID,
+		&counterparty.Name,
+		&counterparty.Note,
+		&counterparty.CreatedAt,
+		&counterparty.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("counterparty with id %d not found", counterpartyID)
+		}
+		return nil, fmt.Errorf("error getting counterparty: %v", err)
+	}
+	return counterparty, nil
+}
+
+func (r *DebtRepository) GetCounterparties(accountID int64) ([]*models.Counterparty, error) {
+	query := `
+	select id, account_id, name, note, created_at, updated_at
+	from counterparties where account_id = $1
+	order by name`
+	rows, err := r.db.Query(query, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting counterparties: %v", err)
+	}
+	defer rows.Close()
+	counterparties := make([]*models.Counterparty, 0)
+	for rows.Next() {
+		counterparty := &models.Counterparty{}
+		err := rows.Scan(
+			&counterparty.ID,
+			&counterparty.AccountID,
+			&counterparty.Name,
+			&counterparty.Note,
+			&counterparty.CreatedAt,
+			&counterparty.UpdatedAt,
+		)
+		if err != nil {
+			return counterparties, fmt.Errorf("error scanning counterparty: %v", err)
+		}
+		counterparties = append(counterparties, counterparty)
+	}
+	return counterparties, nil
+}
+
+func (r *DebtRepository) DeleteCounterparty(counterpartyID int64) error {
+	result, err := r.db.Exec(`delete from counterparties where id = $1`, counterpartyID)
+	if err != nil {
+		return fmt.Errorf("error deleting counterparty: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting counterparty: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("counterparty with id %d not found", counterpartyID)
+	}
+	return nil
+}
+
+// Create сохраняет долг и, если она есть, его транзакцию по счету
+func (r *DebtRepository) Create(debt *models.Debt, transaction *models.Transaction) (*models.Debt, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error creating debt: %v", err)
+	}
+	defer tx.Rollback()
+
+	if transaction != nil {
+		if err = insertTransaction(tx, transaction); err != nil {
+			return nil, err
+		}
+		debt.TransactionID = &transaction.ID
+	}
+	var dueDate *string
+	if debt.DueDate != nil {
+		value := debt.DueDate.Format("2006-01-02")
+		dueDate = &value
+	}
+	query := `
+	insert into debts (account_id, counterparty_id, direction, amount, currency, description, date, due_date,
+	                   bank_account_id, transaction_id, created_at, updated_at)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	returning id`
+	err = tx.QueryRow(query,
+		debt.AccountID,
+		debt.CounterpartyID,
+		debt.Direction,
+		debt.Amount,
+		debt.Currency,
+		debt.Description,
+		debt.Date,
+		dueDate,
+		debt.BankAccountID,
+		debt.TransactionID,
+		debt.CreatedAt,
+		debt.UpdatedAt,
+	).Scan(&debt.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating debt: %v", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing debt: %v", err)
+	}
+	debt.Repaid = models.NewMoney(0, debt.Currency)
+	debt.Outstanding = debt.Amount
+	return debt, nil
+}
+
+// debtColumns - долг с именем человека и суммой возвратов
+const debtColumns = `
+	select d.id, d.account_id, d.counterparty_id, c.name, d.direction, d.amount, d.currency, d.description,
+	       d.date, d.due_date, d.bank_account_id, d.transaction_id, d.last_reminder_date, d.created_at, d.updated_at,
+	       coalesce(r.repaid, 0)
+	from debts d
+	    join counterparties c on c.id = d.counterparty_id
+	    left join (select debt_id, sum(amount) as repaid from debt_repayments group by debt_id) r on r.debt_id = d.id`
+
+func (r *DebtRepository) GetByID(debtID int64) (*models.Debt, error) {
+	debts, err := r.queryDebts(debtColumns+` where d.id = $1`, debtID)
+	if err != nil {
+		return nil, err
+	}
+	if len(debts) == 0 {
+		return nil, fmt.Errorf("debt with id %d not found", debtID)
+	}
+	return debts[0], nil
+}
+
+// GetByAccountID - долги аккаунта, новые первыми; counterpartyID - только с этим человеком
+func (r *DebtRepository) GetByAccountID(accountID int64, counterpartyID *int64, includeSettled bool) ([]*models.Debt, error) {
+	query := debtColumns + `
+	where d.account_id = $1
+	    and ($2::bigint is null or d.counterparty_id = $2)
+	    and ($3::boolean or d.amount > coalesce(r.repaid, 0))
+	order by d.date desc, d.id desc`
+	return r.queryDebts(query, accountID, counterpartyID, includeSettled)
+}
+
+// GetDueForReminder - непогашенные долги со сроком не позже before, о сроке которых еще не напоминали
+func (r *DebtRepository) GetDueForReminder(before time.Time) ([]*models.Debt, error) {
+	query := debtColumns + `
+	where d.due_date is not null
+	    and d.due_date <= $1::date
+	    and d.amount > coalesce(r.repaid, 0)
+	    and (d.last_reminder_date is null or d.last_reminder_date <> d.due_date)
+	order by d.id`
+	return r.queryDebts(query, before.Format("2006-01-02"))
+}
+
+func (r *DebtRepository) queryDebts(query string, args ...interface{}) ([]*models.Debt, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting debts: %v", err)
+	}
+	defer rows.Close()
+	debts := make([]*models.Debt, 0)
+	for rows.Next() {
+		debt := &models.Debt{}
+		err := rows.Scan(
+			&debt.ID,
+			&debt.AccountID,
+			&debt.CounterpartyID,
+			&debt.CounterpartyName,
+			&debt.Direction,
+			&debt.Amount,
+			&debt.Currency,
+			&debt.Description,
+			&debt.Date,
+			&debt.DueDate,
+			&debt.BankAccountID,
+			&debt.TransactionID,
+			&debt.LastReminderDate,
+			&debt.CreatedAt,
+			&debt.UpdatedAt,
+			&debt.Repaid,
+		)
+		if err != nil {
+			return debts, fmt.Errorf("error scanning debt: %v", err)
+		}
+		debt.Amount.Currency = debt.Currency
+		debt.Repaid.Currency = debt.Currency
+		debt.Outstanding = debt.Amount.Sub(debt.Repaid)
+		debt.IsSettled = !debt.Outstanding.IsPositive()
+		debts = append(debts, debt)
+	}
+	return debts, nil
+}
+
+// Delete удаляет долг, его возвраты и их транзакции по счетам, остатки счетов поправляются
+func (r *DebtRepository) Delete(debtID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error deleting debt: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+	delete from transactions
+	where id in (
+	    select transaction_id from debts where id = $1
+	    union
+	    select transaction_id from debt_repayments where debt_id = $1)
+	returning bank_account_id, amount, date, is_planned`, debtID)
+	if err != nil {
+		return fmt.Errorf("error deleting debt transactions: %v", err)
+	}
+	type deleted struct {
+		bankAccountID int64
+		amount        models.Money
+		date          time.Time
+		isPlanned     bool
+	}
+	transactions := make([]deleted, 0)
+	for rows.Next() {
+		var item deleted
+		if err := rows.Scan(&item.bankAccountID, &item.amount, &item.date, &item.isPlanned); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning deleted debt transaction: %v", err)
+		}
+		transactions = append(transactions, item)
+	}
+	rows.Close()
+	for _, item := range transactions {
+		if err = applyBalanceChange(tx, item.bankAccountID, item.amount.Neg(), item.date, item.isPlanned); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.Exec(`delete from debts where id = $1`, debtID)
+	if err != nil {
+		return fmt.Errorf("error deleting debt: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting debt: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("debt with id %d not found", debtID)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing debt: %v", err)
+	}
+	return nil
+}
+
+// AddRepayment сохраняет возврат и его транзакцию по счету
+// долг блокируется, поэтому два параллельных возврата не погасят больше остатка
+func (r *DebtRepository) AddRepayment(repayment *models.DebtRepayment, transaction *models.Transaction) (*models.DebtRepayment, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error adding repayment: %v", err)
+	}
+	defer tx.Rollback()
+
+	var amount, repaid models.Money
+	err = tx.QueryRow(`select amount from debts where id = $1 for update`, repayment.DebtID).Scan(&amount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("debt with id %d not found", repayment.DebtID)
+		}
+		return nil, fmt.Errorf("error getting debt: %v", err)
+	}
+	err = tx.QueryRow(`select coalesce(sum(amount), 0) from debt_repayments where debt_id = $1`, repayment.DebtID).Scan(&repaid)
+	if err != nil {
+		return nil, fmt.Errorf("error getting debt repayments: %v", err)
+	}
+	if outstanding := amount.Sub(repaid); repayment.Amount.Cmp(outstanding) > 0 {
+		return nil, fmt.Errorf("repayment exceeds the outstanding amount %s", outstanding.WithCurrency(repayment.Amount.Currency))
+	}
+
+	if transaction != nil {
+		if err = insertTransaction(tx, transaction); err != nil {
+			return nil, err
+		}
+		repayment.TransactionID = &transaction.ID
+	}
+	query := `
+	insert into debt_repayments (debt_id, amount, date, note, bank_account_id, transaction_id, created_at)
+	values ($1, $2, $3, $4, $5, $6, $7)
+	returning id`
+	err = tx.QueryRow(query,
+		repayment.DebtID,
+		repayment.Amount,
+		repayment.Date,
+		repayment.Note,
+		repayment.BankAccountID,
+		repayment.TransactionID,
+		repayment.CreatedAt,
+	).Scan(&repayment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error adding repayment: %v", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing repayment: %v", err)
+	}
+	return repayment, nil
+}
+
+func (r *DebtRepository) GetRepayments(debtID int64) ([]*models.DebtRepayment, error) {
+	query := `
+	select id, debt_id, amount, date, note, bank_account_id, transaction_id, created_at
+	from debt_repayments where debt_id = $1
+	order by date, id`
+	rows, err := r.db.Query(query, debtID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting repayments: %v", err)
+	}
+	defer rows.Close()
+	repayments := make([]*models.DebtRepayment, 0)
+	for rows.Next() {
+		repayment := &models.DebtRepayment{}
+		err := rows.Scan(
+			&repayment.ID,
+			&repayment.DebtID,
+			&repayment.Amount,
+			&repayment.Date,
+			&repayment.Note,
+			&repayment.BankAccountID,
+			&repayment.TransactionID,
+			&repayment.CreatedAt,
+		)
+		if err != nil {
+			return repayments, fmt.Errorf("error scanning repayment: %v", err)
+		}
+		repayments = append(repayments, repayment)
+	}
+	return repayments, nil
+}
+
+// GetBalances - остатки непогашенных долгов по людям и валютам
+func (r *DebtRepository) GetBalances(accountID int64) ([]*models.DebtBalance, error) {
+	query := `
+	select c.id, c.name, d.currency,
+	    coalesce(sum(case when d.direction = 'lent' then d.amount - coalesce(r.repaid, 0) else 0 end), 0),
+	    coalesce(sum(case when d.direction = 'borrowed' then d.amount - coalesce(r.repaid, 0) else 0 end), 0)
+	from debts d
+	    join counterparties c on c.id = d.counterparty_id
+	    left join (select debt_id, sum(amount) as repaid from debt_repayments group by debt_id) r on r.debt_id = d.id
+	where d.account_id = $1
+	    and d.amount > coalesce(r.repaid, 0)
+	group by c.id, c.name, d.currency
+	order by c.name, d.currency`
+	rows, err := r.db.Query(query, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting debt balances: %v", err)
+	}
+	defer rows.Close()
+	balances := make([]*models.DebtBalance, 0)
+	for rows.Next() {
+		balance := &models.DebtBalance{}
+		err := rows.Scan(
+			&balance.CounterpartyID,
+			&balance.CounterpartyName,
+			&balance.Currency,
+			&balance.OwedToMe,
+			&balance.IOwe,
+		)
+		if err != nil {
+			return balances, fmt.Errorf("error scanning debt balance: %v", err)
+		}
+		balance.OwedToMe.Currency = balance.Currency
+		balance.IOwe.Currency = balance.Currency
+		balance.Net = balance.OwedToMe.Sub(balance.IOwe)
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
+
+func (r *DebtRepository) MarkReminderSent(debtID int64, dueDate time.Time) error {
+	_, err := r.db.Exec(`update debts set last_reminder_date = $2 where id = $1`, debtID, dueDate.Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("error marking debt reminder: %v", err)
+	}
+	return nil
+}
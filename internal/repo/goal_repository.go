@@ -0,0 +1,203 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"justTest/internal/models"
+	"time"
+)
+
+type GoalRepository struct {
+	db *sql.DB
+}
+
+func NewGoalRepository(db *sql.DB) *GoalRepository {
+	return &GoalRepository{
+		db: db,
+	}
+}
+
+func (r *GoalRepository) Create(goal *models.Goal) (*models.Goal, error) {
+	query := `
+	insert into goals (account_id, bank_account_id, name, target_amount, currency, target_date,
+	                   last_milestone, created_at, updated_at)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	returning id`
+	err := r.db.QueryRow(query,
+		goal.AccountID,
+		goal.BankAccountID,
+		goal.Name,
+		goal.TargetAmount,
+		goal.Currency,
+		goal.TargetDate.Format("2006-01-02"),
+		goal.LastMilestone,
+		goal.CreatedAt,
+		goal.UpdatedAt,
+	).Scan(&goal.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating goal: %v", err)
+	}
+	return goal, nil
+}
+
+func (r *GoalRepository) GetByID(goalID int64) (*models.Goal, error) {
+	query := `
+	select id, account_id, bank_account_id, name, target_amount, currency, target_date,
+	       last_milestone, created_at, updated_at
+	from goals where id = $1`
+	goal := &models.Goal{}
+	err := r.db.QueryRow(query, goalID).Scan(
+		&goal.ID,
+		&goal.AccountID,
+		&goal.BankAccountID,
+		&goal.Name,
+		&goal.TargetAmount,
+		&goal.Currency,
+		&goal.TargetDate,
+		&goal.LastMilestone,
+		&goal.CreatedAt,
+		&goal.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("goal with id %d not found", goalID)
+		}
+		return nil, fmt.Errorf("error getting goal: %v", err)
+	}
+	goal.TargetAmount.Currency = goal.Currency
+	return goal, nil
+}
+
+func (r *GoalRepository) GetByAccountID(accountID int64) ([]*models.Goal, error) {
+	query := `
+	select id, account_id, bank_account_id, name, target_amount, currency, target_date,
+	       last_milestone, created_at, updated_at
+	from goals where account_id = $1
+	order by target_date, id`
+	return r.queryGoals(query, accountID)
+}
+
+// GetAll - все цели, которые еще не достигли 100%, для проверки рубежей по расписанию
+func (r *GoalRepository) GetAll() ([]*models.Goal, error) {
+	query := `
+	select id, account_id, bank_account_id, name, target_amount, currency, target_date,
+	       last_milestone, created_at, updated_at
+	from goals where last_milestone < 100
+	order by id`
+	return r.queryGoals(query)
+}
+
+func (r *GoalRepository) queryGoals(query string, args ...interface{}) ([]*models.Goal, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting goals: %v", err)
+	}
+	defer rows.Close()
+	goals := make([]*models.Goal, 0)
+	for rows.Next() {
+		goal := &models.Goal{}
+		err := rows.Scan(
+			&goal.ID,
+			&goal.AccountID,
+			&goal.BankAccountID,
+			&goal.Name,
+			&goal.TargetAmount,
+			&goal.Currency,
+			&goal.TargetDate,
+			&goal.LastMilestone,
+			&goal.CreatedAt,
+			&goal.UpdatedAt,
+		)
+		if err != nil {
+			return goals, fmt.Errorf("error scanning goal: %v", err)
+		}
+		goal.TargetAmount.Currency = goal.Currency
+		goals = append(goals, goal)
+	}
+	return goals, nil
+}
+
+func (r *GoalRepository) Delete(goalID int64) error {
+	query := `
+delete from goals where id = $1`
+	res, err := r.db.Exec(query, goalID)
+	if err != nil {
+		return fmt.Errorf("error deleting goal: %v", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting goal: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("goal with id %d not found", goalID)
+	}
+	return nil
+}
+
+func (r *GoalRepository) SetLastMilestone(goalID int64, milestone int) error {
+	query := `update goals set last_milestone = $2, updated_at = $3 where id = $1`
+	_, err := r.db.Exec(query, goalID, milestone, time.Now())
+	if err != nil {
+		return fmt.Errorf("error updating goal milestone: %v", err)
+	}
+	return nil
+}
+
+func (r *GoalRepository) AddContribution(contribution *models.GoalContribution) (*models.GoalContribution, error) {
+	query := `
+	insert into goal_contributions (goal_id, amount, date, note, created_at)
+	values ($1, $2, $3, $4, $5)
+	returning id`
+	err := r.db.QueryRow(query,
+		contribution.GoalID,
+		contribution.Amount,
+		contribution.Date,
+		contribution.Note,
+		contribution.CreatedAt,
+	).Scan(&contribution.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating goal contribution: %v", err)
+	}
+	return contribution, nil
+}
+
+func (r *GoalRepository) GetContributions(goalID int64) ([]*models.GoalContribution, error) {
+	query := `
+	select id, goal_id, amount, date, note, created_at
+	from goal_contributions where goal_id = $1
+	order by date desc, id desc`
+	rows, err := r.db.Query(query, goalID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting goal contributions: %v", err)
+	}
+	defer rows.Close()
+	contributions := make([]*models.GoalContribution, 0)
+	for rows.Next() {
+		contribution := &models.GoalContribution{}
+		err := rows.Scan(
+			&contribution.ID,
+			&contribution.GoalID,
+			&contribution.Amount,
+			&contribution.Date,
+			&contribution.Note,
+			&contribution.CreatedAt,
+		)
+		if err != nil {
+			return contributions, fmt.Errorf("error scanning goal contribution: %v", err)
+		}
+		contributions = append(contributions, contribution)
+	}
+	return contributions, nil
+}
+
+// GetContributionTotal - сумма взносов в цель до момента before (не включая)
+func (r *GoalRepository) GetContributionTotal(goalID int64, before time.Time) (models.Money, error) {
+	query := `
+	select COALESCE(SUM(amount), 0) from goal_contributions where goal_id = $1 and date < $2`
+	var total models.Money
+	err := r.db.QueryRow(query, goalID, before).Scan(&total)
+	if err != nil {
+		return total, fmt.Errorf("error getting goal contributions total: %v", err)
+	}
+	return total, nil
+}
@@ -0,0 +1,315 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"justTest/internal/models"
+	"time"
+)
+
+// InstallmentRepository - рассрочки и их платежи
+// платеж - транзакция с installment_plan_id: пока она запланированная, платеж еще не наступил
+type InstallmentRepository struct {
+	db *sql.DB
+}
+
+func NewInstallmentRepository(db *sql.DB) *InstallmentRepository {
+	return &InstallmentRepository{
+		db: db,
+	}
+}
+
+// Create сохраняет рассрочку и ее платежи (запланированные транзакции) в одной транзакции БД
+func (r *InstallmentRepository) Create(plan *models.InstallmentPlan, payments []*models.Transaction) (*models.InstallmentPlan, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error creating installment plan: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+	insert into installment_plans (account_id, bank_account_id, category_id, description, total_amount, fee_amount,
+	                               payments_count, paid_payments, first_payment_date, created_at, updated_at)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	returning id`
+	err = tx.QueryRow(query,
+		plan.AccountID,
+		plan.BankAccountID,
+		plan.CategoryID,
+		plan.Description,
+		plan.TotalAmount,
+		plan.Fee,
+		plan.PaymentsCount,
+		plan.PaidPayments,
+		plan.FirstPaymentDate.Format("2006-01-02"),
+		plan.CreatedAt,
+		plan.UpdatedAt,
+	).Scan(&plan.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating installment plan: %v", err)
+	}
+	for _, payment := range payments {
+		if err = insertTransaction(tx, payment); err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`update transactions set installment_plan_id = $1 where id = $2`, plan.ID, payment.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error linking installment payment: %v", err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing installment plan: %v", err)
+	}
+	return plan, nil
+}
+
+func (r *InstallmentRepository) GetByID(planID int64) (*models.InstallmentPlan, error) {
+	query := `
+	select p.id, p.account_id, p.bank_account_id, p.category_id, p.description, p.total_amount, p.fee_amount,
+	       p.payments_count, p.paid_payments, p.first_payment_date, p.created_at, p.updated_at, ba.currency
+	from installment_plans p
+	    join bank_accounts ba on ba.id = p.bank_account_id
+	where p.id = $1`
+	plan := &models.InstallmentPlan{}
+	var currency string
+	err := r.db.QueryRow(query, planID).Scan(
+		&plan.ID,
+		&plan.AccountID,
+		&plan.BankAccountID,
+		&plan.CategoryID,
+		&plan.Description,
+		&plan.TotalAmount,
+		&plan.Fee,
+		&plan.PaymentsCount,
+		&plan.PaidPayments,
+		&plan.FirstPaymentDate,
+		&plan.CreatedAt,
+		&plan.UpdatedAt,
+		&currency,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("installment plan with id %d not found", planID)
+		}
+		return nil, fmt.Errorf("error getting installment plan: %v", err)
+	}
+	plan.TotalAmount.Currency = currency
+	plan.Fee.Currency = currency
+	return plan, nil
+}
+
+func (r *InstallmentRepository) GetByAccountID(accountID int64) ([]*models.InstallmentPlan, error) {
+	query := `
+	select p.id, p.account_id, p.bank_account_id, p.category_id, p.description, p.total_amount, p.fee_amount,
+	       p.payments_count, p.paid_payments, p.first_payment_date, p.created_at, p.updated_at, ba.currency
+	from installment_plans p
+	    join bank_accounts ba on ba.id = p.bank_account_id
+	where p.account_id = $1
+	order by p.first_payment_date desc, p.id desc`
+	rows, err := r.db.Query(query, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting installment plans: %v", err)
+	}
+	defer rows.Close()
+	plans := make([]*models.InstallmentPlan, 0)
+	for rows.Next() {
+		plan := &models.InstallmentPlan{}
+		var currency string
+		err := rows.Scan(
+			&plan.ID,
+			&plan.AccountID,
+			&plan.BankAccountID,
+			&plan.CategoryID,
+			&plan.Description,
+			&plan.TotalAmount,
+			&plan.Fee,
+			&plan.PaymentsCount,
+			&plan.PaidPayments,
+			&plan.FirstPaymentDate,
+			&plan.CreatedAt,
+			&plan.UpdatedAt,
+			&currency,
+		)
+		if err != nil {
+			return plans, fmt.Errorf("error scanning installment plan: %v", err)
+		}
+		plan.TotalAmount.Currency = currency
+		plan.Fee.Currency = currency
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// Delete удаляет рассрочку и ее еще не наступившие платежи, прошедшие платежи остаются обычными транзакциями
+func (r *InstallmentRepository) Delete(planID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error deleting installment plan: %v", err)
+	}
+	defer tx.Rollback()
+
+	// запланированные транзакции на остатки не влияют, поправлять их не нужно
+	_, err = tx.Exec(`delete from transactions where installment_plan_id = $1 and is_planned = true`, planID)
+	if err != nil {
+		return fmt.Errorf("error deleting installment payments: %v", err)
+	}
+	result, err := tx.Exec(`delete from installment_plans where id = $1`, planID)
+	if err != nil {
+		return fmt.Errorf("error deleting installment plan: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting installment plan: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("installment plan with id %d not found", planID)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing installment plan: %v", err)
+	}
+	return nil
+}
+
+// GetPayments - все платежи рассрочки по дате, включая уже прошедшие
+func (r *InstallmentRepository) GetPayments(planID int64) ([]*models.InstallmentPayment, error) {
+	query := `
+	select t.installment_plan_id, t.id, t.bank_account_id, t.category_id, t.description, t.date, t.amount,
+	       ba.currency, not t.is_planned
+	from transactions t
+	    join bank_accounts ba on ba.id = t.bank_account_id
+	where t.installment_plan_id = $1
+	order by t.date, t.id`
+	return r.queryPayments(query, planID)
+}
+
+// GetOutstandingPayments - еще не наступившие платежи по всем рассрочкам аккаунта с датой в [startDate, endDate)
+func (r *InstallmentRepository) GetOutstandingPayments(accountID int64, startDate, endDate time.Time) ([]*models.InstallmentPayment, error) {
+	query := `
+	select t.installment_plan_id, t.id, t.bank_account_id, t.category_id, t.description, t.date, t.amount,
+	       ba.currency, not t.is_planned
+	from transactions t
+	    join bank_accounts ba on ba.id = t.bank_account_id
+	where ba.account_id = $1
+	    and t.installment_plan_id is not null
+	    and t.is_planned = true
+	    and t.date >= $2
+	    and t.date < $3
+	order by t.date, t.id`
+	return r.queryPayments(query, accountID, startDate, endDate)
+}
+
+// GetOutstandingByCategory - сумма еще не наступивших платежей по рассрочкам с датой в [startDate, endDate)
+// по категориям и валютам счетов, как траты в GetSpentAmountByAccountAndMonth
+func (r *InstallmentRepository) GetOutstandingByCategory(accountID int64, startDate, endDate time.Time) ([]*models.CategorySpending, error) {
+	query := `
+	select COALESCE(c.id, 0), COALESCE(c.name, 'Без категории'), ba.currency, SUM(ABS(t.amount))
+	from transactions t
+	    join bank_accounts ba on ba.id = t.bank_account_id
+	    left join categories c on c.id = t.category_id
+	where ba.account_id = $1
+	    and t.installment_plan_id is not null
+	    and t.is_planned = true
+	    and t.date >= $2
+	    and t.date < $3
+	group by ba.currency, c.id, c.name`
+	rows, err := r.db.Query(query, accountID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("error getting installment obligations: %v", err)
+	}
+	defer rows.Close()
+	spending := make([]*models.CategorySpending, 0)
+	for rows.Next() {
+		item := &models.CategorySpending{}
+		err := rows.Scan(
+			&item.CategoryID,
+			&item.CategoryName,
+			&item.Currency,
+			&item.Amount,
+		)
+		if err != nil {
+			return spending, fmt.Errorf("error scanning installment obligations: %v", err)
+		}
+		item.Amount.Currency = item.Currency
+		spending = append(spending, item)
+	}
+	return spending, nil
+}
+
+// RealizeDuePayments делает обычными все платежи по рассрочкам с датой не позже now и обновляет остатки счетов
+// платежи блокируются, поэтому параллельный запуск не проведет один платеж дважды
+func (r *InstallmentRepository) RealizeDuePayments(now time.Time) ([]*models.InstallmentPayment, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error realizing installment payments: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+	select t.installment_plan_id, t.id, t.bank_account_id, t.category_id, t.description, t.date, t.amount,
+	       ba.currency, not t.is_planned
+	from transactions t
+	    join bank_accounts ba on ba.id = t.bank_account_id
+	where t.installment_plan_id is not null
+	    and t.is_planned = true
+	    and t.date <= $1
+	order by t.date, t.id
+	for update of t`, now)
+	if err != nil {
+		return nil, fmt.Errorf("error getting due installment payments: %v", err)
+	}
+	payments, err := scanInstallmentPayments(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range payments {
+		_, err = tx.Exec(`update transactions set is_planned = false, updated_at = $2 where id = $1`,
+			payment.TransactionID, now)
+		if err != nil {
+			return nil, fmt.Errorf("error realizing installment payment %d: %v", payment.TransactionID, err)
+		}
+		if err = applyBalanceChange(tx, payment.BankAccountID, payment.Amount, payment.DueDate, false); err != nil {
+			return nil, err
+		}
+		payment.IsPaid = true
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing installment payments: %v", err)
+	}
+	return payments, nil
+}
+
+func (r *InstallmentRepository) queryPayments(query string, args ...interface{}) ([]*models.InstallmentPayment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting installment payments: %v", err)
+	}
+	return scanInstallmentPayments(rows)
+}
+
+func scanInstallmentPayments(rows *sql.Rows) ([]*models.InstallmentPayment, error) {
+	defer rows.Close()
+	payments := make([]*models.InstallmentPayment, 0)
+	for rows.Next() {
+		payment := &models.InstallmentPayment{}
+		err := rows.Scan(
+			&payment.PlanID,
+			&payment.TransactionID,
+			&payment.BankAccountID,
+			&payment.CategoryID,
+			&payment.Description,
+			&payment.DueDate,
+			&payment.Amount,
+			&payment.Currency,
+			&payment.IsPaid,
+		)
+		if err != nil {
+			return payments, fmt.Errorf("error scanning installment payment: %v", err)
+		}
+		payment.Amount.Currency = payment.Currency
+		payments = append(payments, payment)
+	}
+	if err := rows.Err(); err != nil {
+		return payments, fmt.Errorf("error reading installment payments: %v", err)
+	}
+	return payments, nil
+}
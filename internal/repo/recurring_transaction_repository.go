@@ -0,0 +1,247 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"justTest/internal/models"
+	"time"
+)
+
+type RecurringTransactionRepository struct {
+	db *sql.DB
+}
+
+func NewRecurringTransactionRepository(db *sql.DB) *RecurringTransactionRepository {
+	return &RecurringTransactionRepository{
+		db: db,
+	}
+}
+
+func (r *RecurringTransactionRepository) Create(recurring *models.RecurringTransaction) (*models.RecurringTransaction, error) {
+	query := `
+	insert into recurring_transactions (account_id, bank_account_id, category_id, amount, description, transaction_type,
+	                                    frequency, interval_count, day_of_month, start_date, end_date, next_run_date,
+	                                    last_run_date, is_active, created_at, updated_at)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	returning id;`
+	err := r.db.QueryRow(query,
+		recurring.AccountID,
+		recurring.BankAccountID,
+		recurring.CategoryID,
+		recurring.Amount,
+		recurring.Description,
+		recurring.TransactionType,
+		recurring.Frequency,
+		recurring.Interval,
+		recurring.DayOfMonth,
+		recurring.StartDate,
+		recurring.EndDate,
+		recurring.NextRunDate,
+		recurring.LastRunDate,
+		recurring.IsActive,
+		recurring.CreatedAt,
+		recurring.UpdatedAt,
+	).Scan(&recurring.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating recurring transaction: %v", err)
+	}
+	return recurring, nil
+}
+
+func (r *RecurringTransactionRepository) Update(recurring *models.RecurringTransaction) (*models.RecurringTransaction, error) {
+	query := `
+	update recurring_transactions
+	set bank_account_id = $1, category_id = $2, amount = $3, description = $4, transaction_type = $5,
+	    frequency = $6, interval_count = $7, day_of_month = $8, start_date = $9, end_date = $10,
+	    next_run_date = $11, is_active = $12, updated_at = $13
+	where id = $14`
+	res, err := r.db.Exec(query,
+		recurring.BankAccountID,
+		recurring.CategoryID,
+		recurring.Amount,
+		recurring.Description,
+		recurring.TransactionType,
+		recurring.Frequency,
+		recurring.Interval,
+		recurring.DayOfMonth,
+		recurring.StartDate,
+		recurring.EndDate,
+		recurring.NextRunDate,
+		recurring.IsActive,
+		recurring.UpdatedAt,
+		recurring.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating recurring transaction: %v", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error updating recurring transaction: %v", err)
+	}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("recurring transaction with id %d not found", recurring.ID)
+	}
+	return recurring, nil
+}
+
+func (r *RecurringTransactionRepository) Delete(recurringID int64) error {
+	query := `
+delete from recurring_transactions where id = $1`
+	res, err := r.db.Exec(query, recurringID)
+	if err != nil {
+		return fmt.Errorf("error deleting recurring transaction: %v", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting recurring transaction: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("recurring transaction with id %d not found", recurringID)
+	}
+	return nil
+}
+
+func (r *RecurringTransactionRepository) GetByID(recurringID int64) (*models.RecurringTransaction, error) {
+	query := `
+	select id, account_id, bank_account_id, category_id, amount, description, transaction_type,
+	       frequency, interval_count, day_of_month, start_date, end_date, next_run_date,
+	       last_run_date, is_active, created_at, updated_at
+	from recurring_transactions where id = $1`
+	recurring := &models.RecurringTransaction{}
+	err := r.db.QueryRow(query, recurringID).Scan(
+		&recurring.ID,
+		&recurring.AccountID,
+		&recurring.BankAccountID,
+		&recurring.CategoryID,
+		&recurring.Amount,
+		&recurring.Description,
+		&recurring.TransactionType,
+		&recurring.Frequency,
+		&recurring.Interval,
+		&recurring.DayOfMonth,
+		&recurring.StartDate,
+		&recurring.EndDate,
+		&recurring.NextRunDate,
+		&recurring.LastRunDate,
+		&recurring.IsActive,
+		&recurring.CreatedAt,
+		&recurring.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("recurring transaction with id %d not found", recurringID)
+		}
+		return nil, fmt.Errorf("error getting recurring transaction: %v", err)
+	}
+	return recurring, nil
+}
+
+func (r *RecurringTransactionRepository) GetByAccountID(accountID int64) ([]*models.RecurringTransaction, error) {
+	query := `
+	select id, account_id, bank_account_id, category_id, amount, description, transaction_type,
+	       frequency, interval_count, day_of_month, start_date, end_date, next_run_date,
+	       last_run_date, is_active, created_at, updated_at
+	from recurring_transactions where account_id = $1
+	order by next_run_date`
+	rows, err := r.db.Query(query, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting recurring transactions: %v", err)
+	}
+	defer rows.Close()
+	return scanRecurringTransactions(rows)
+}
+
+// GetDue - активные правила, у которых следующее срабатывание не позже date
+func (r *RecurringTransactionRepository) GetDue(date time.Time) ([]*models.RecurringTransaction, error) {
+	query := `
+	select id, account_id, bank_account_id, category_id, amount, description, transaction_type,
+	       frequency, interval_count, day_of_month, start_date, end_date, next_run_date,
+	       last_run_date, is_active, created_at, updated_at
+	from recurring_transactions
+	where is_active = true and next_run_date <= $1
+	order by next_run_date`
+	rows, err := r.db.Query(query, date)
+	if err != nil {
+		return nil, fmt.Errorf("error getting due recurring transactions: %v", err)
+	}
+	defer rows.Close()
+	return scanRecurringTransactions(rows)
+}
+
+func (r *RecurringTransactionRepository) UpdateSchedule(recurringID int64, nextRunDate time.Time, lastRunDate *time.Time, isActive bool) error {
+	query := `
+	update recurring_transactions
+	set next_run_date = $1, last_run_date = $2, is_active = $3, updated_at = now()
+	where id = $4`
+	_, err := r.db.Exec(query, nextRunDate, lastRunDate, isActive, recurringID)
+	if err != nil {
+		return fmt.Errorf("error updating recurring schedule: %v", err)
+	}
+	return nil
+}
+
+// CreateRun записывает срабатывание правила на дату и создает его транзакцию в одной транзакции БД
+// false - если срабатывание на эту дату уже было, транзакция тогда не создается
+func (r *RecurringTransactionRepository) CreateRun(recurringID int64, runDate time.Time, transaction *models.Transaction) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("error creating recurring run: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+	insert into recurring_transaction_runs (recurring_transaction_id, run_date)
+	values ($1, $2)
+	on conflict (recurring_transaction_id, run_date) do nothing
+	returning id`
+	var runID int64
+	err = tx.QueryRow(query, recurringID, runDate).Scan(&runID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error claiming recurring run: %v", err)
+	}
+	if err = insertTransaction(tx, transaction); err != nil {
+		return false, err
+	}
+	_, err = tx.Exec(`update recurring_transaction_runs set transaction_id = $1 where id = $2`, transaction.ID, runID)
+	if err != nil {
+		return false, fmt.Errorf("error saving recurring run transaction: %v", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing recurring run: %v", err)
+	}
+	return true, nil
+}
+
+func scanRecurringTransactions(rows *sql.Rows) ([]*models.RecurringTransaction, error) {
+	recurringTransactions := make([]*models.RecurringTransaction, 0)
+	for rows.Next() {
+		recurring := &models.RecurringTransaction{}
+		err := rows.Scan(
+			&recurring.ID,
+			&recurring.AccountID,
+			&recurring.BankAccountID,
+			&recurring.CategoryID,
+			&recurring.Amount,
+			&recurring.Description,
+			&recurring.TransactionType,
+			&recurring.Frequency,
+			&recurring.Interval,
+			&recurring.DayOfMonth,
+			&recurring.StartDate,
+			&recurring.EndDate,
+			&recurring.NextRunDate,
+			&recurring.LastRunDate,
+			&recurring.IsActive,
+			&recurring.CreatedAt,
+			&recurring.UpdatedAt,
+		)
+		if err != nil {
+			return recurringTransactions, fmt.Errorf("error scanning recurring transaction: %v", err)
+		}
+		recurringTransactions = append(recurringTransactions, recurring)
+	}
+	return recurringTransactions, nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"justTest/internal/models"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type TransactionRepository struct {
@@ -17,12 +19,47 @@ func NewTransactionRepository(db *sql.DB) *TransactionRepository {
 
 }
 func (r *TransactionRepository) Create(transaction *models.Transaction) (*models.Transaction, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return transaction, fmt.Errorf("error creating transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err = insertTransaction(tx, transaction); err != nil {
+		return transaction, err
+	}
+	if err = tx.Commit(); err != nil {
+		return transaction, fmt.Errorf("error committing transaction: %v", err)
+	}
+	return transaction, nil
+}
+
+// CreateBatch создает все транзакции в одной транзакции БД: либо все, либо ни одной
+func (r *TransactionRepository) CreateBatch(transactions []*models.Transaction) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error creating transactions: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, transaction := range transactions {
+		if err = insertTransaction(tx, transaction); err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transactions: %v", err)
+	}
+	return nil
+}
+
+func insertTransaction(tx *sql.Tx, transaction *models.Transaction) error {
 	query := `
 insert into transactions ( bank_account_id, category_id, amount, description, transaction_type, date, 
-                          created_at, updated_at, to_account_id, transfer_rate)
-	values ($1, $2, $3, $4, $5, $6, $7, $8, $9,$10)
+                          created_at, updated_at, to_account_id, transfer_rate, is_planned, external_id)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9,$10, $11, $12)
 	returning id;`
-	err := r.db.QueryRow(query,
+	err := tx.QueryRow(query,
 		transaction.BankAccountID,
 		transaction.CategoryID,
 		transaction.Amount,
@@ -33,18 +70,170 @@ insert into transactions ( bank_account_id, category_id, amount, description, tr
 		transaction.UpdatedAt,
 		transaction.ToAccountID,
 		transaction.TransferRate,
+		transaction.IsPlanned,
+		transaction.ExternalID,
 	).Scan(&transaction.ID)
 
 	if err != nil {
-		return transaction, fmt.Errorf("error creating transaction: %v", err)
+		return fmt.Errorf("error creating transaction: %v", err)
+	}
+	if err = insertSplits(tx, transaction); err != nil {
+		return err
+	}
+	return applyBalanceChange(tx, transaction.BankAccountID, transaction.Amount, transaction.Date, transaction.IsPlanned)
+}
+
+// Update сохраняет транзакцию и полностью заменяет ее разбивку на transaction.Splits
+func (r *TransactionRepository) Update(transaction *models.Transaction) (*models.Transaction, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error updating transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// прежние сумма и дата нужны, чтобы поправить остатки
+	var (
+		bankAccountID int64
+		oldAmount     models.Money
+		oldDate       time.Time
+		oldIsPlanned  bool
+	)
+	err = tx.QueryRow(`select bank_account_id, amount, date, is_planned from transactions where id = $1 for update`,
+		transaction.ID).Scan(&bankAccountID, &oldAmount, &oldDate, &oldIsPlanned)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("transaction with id %d not found", transaction.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error updating transaction: %v", err)
+	}
+
+	query := `
+update transactions 
+set category_id = $1, amount = $2, description = $3, transaction_type = $4, date = $5, updated_at = $6, is_planned = $7
+	where id = $8`
+	res, err := tx.Exec(query,
+		transaction.CategoryID,
+		transaction.Amount,
+		transaction.Description,
+		transaction.TransactionType,
+		transaction.Date,
+		transaction.UpdatedAt,
+		transaction.IsPlanned,
+		transaction.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating transaction: %v", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error updating transaction: %v", err)
+	}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("transaction with id %d not found", transaction.ID)
+	}
+	_, err = tx.Exec(`delete from transaction_splits where transaction_id = $1`, transaction.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting transaction splits: %v", err)
+	}
+	if err = insertSplits(tx, transaction); err != nil {
+		return nil, err
+	}
+	if err = applyBalanceChange(tx, bankAccountID, oldAmount.Neg(), oldDate, oldIsPlanned); err != nil {
+		return nil, err
+	}
+	if err = applyBalanceChange(tx, bankAccountID, transaction.Amount, transaction.Date, transaction.IsPlanned); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %v", err)
 	}
 	return transaction, nil
 }
 
+func insertSplits(tx *sql.Tx, transaction *models.Transaction) error {
+	query := `
+	insert into transaction_splits (transaction_id, category_id, amount, description)
+	values ($1, $2, $3, $4)
+	returning id`
+	for _, split := range transaction.Splits {
+		split.TransactionID = transaction.ID
+		err := tx.QueryRow(query,
+			split.TransactionID,
+			split.CategoryID,
+			split.Amount,
+			split.Description,
+		).Scan(&split.ID)
+		if err != nil {
+			return fmt.Errorf("error creating transaction split: %v", err)
+		}
+	}
+	return nil
+}
+
+func (r *TransactionRepository) GetSplitsByTransactionID(transactionID int64) ([]*models.TransactionSplit, error) {
+	query := `
+	select id, transaction_id, category_id, amount, description
+	from transaction_splits where transaction_id = $1
+	order by id`
+	rows, err := r.db.Query(query, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting transaction splits: %v", err)
+	}
+	defer rows.Close()
+	splits := make([]*models.TransactionSplit, 0)
+	for rows.Next() {
+		split := &models.TransactionSplit{}
+		err := rows.Scan(
+			&split.ID,
+			&split.TransactionID,
+			&split.CategoryID,
+			&split.Amount,
+			&split.Description,
+		)
+		if err != nil {
+			return splits, fmt.Errorf("error scanning transaction split: %v", err)
+		}
+		splits = append(splits, split)
+	}
+	return splits, nil
+}
+
+func (r *TransactionRepository) Delete(transactionID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error deleting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+delete from transactions where id = $1
+	returning bank_account_id, amount, date, is_planned`
+	var (
+		bankAccountID int64
+		amount        models.Money
+		date          time.Time
+		isPlanned     bool
+	)
+	err = tx.QueryRow(query, transactionID).Scan(&bankAccountID, &amount, &date, &isPlanned)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("transaction with id %d not found", transactionID)
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting transaction: %v", err)
+	}
+	if err = applyBalanceChange(tx, bankAccountID, amount.Neg(), date, isPlanned); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", err)
+	}
+	return nil
+}
+
 func (r *TransactionRepository) GetByBankAccountID(BankAccountID int64, limit, offset int) ([]*models.Transaction, error) {
 	query := ` 
 	select id, bank_account_id, category_id, amount, description, transaction_type, 
-               date, created_at, updated_at, to_account_id, transfer_rate from transactions where bank_account_id = $1
+               date, created_at, updated_at, to_account_id, transfer_rate, is_planned, external_id, installment_plan_id from transactions where bank_account_id = $1
 	order by created_at desc limit $2 offset $3
 `
 	rows, err := r.db.Query(query, BankAccountID, limit, offset)
@@ -67,6 +256,9 @@ func (r *TransactionRepository) GetByBankAccountID(BankAccountID int64, limit, o
 			&transaction.UpdatedAt,
 			&transaction.ToAccountID,
 			&transaction.TransferRate,
+			&transaction.IsPlanned,
+			&transaction.ExternalID,
+			&transaction.InstallmentPlanID,
 		)
 		if err != nil {
 			return transactions, fmt.Errorf("error getting transaction: %v", err)
@@ -78,12 +270,77 @@ func (r *TransactionRepository) GetByBankAccountID(BankAccountID int64, limit, o
 
 }
 
+// GetByBankAccountAndDateRange - все транзакции счета за период [startDate, endDate), для сверки импорта
+func (r *TransactionRepository) GetByBankAccountAndDateRange(bankAccountID int64, startDate, endDate time.Time) ([]*models.Transaction, error) {
+	query := `
+	select id, bank_account_id, category_id, amount, description, transaction_type,
+	       date, created_at, updated_at, to_account_id, transfer_rate, is_planned, external_id, installment_plan_id
+	from transactions
+	where bank_account_id = $1 and date >= $2 and date < $3
+	order by date`
+	rows, err := r.db.Query(query, bankAccountID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("error getting transactions: %v", err)
+	}
+	defer rows.Close()
+	transactions := make([]*models.Transaction, 0)
+	for rows.Next() {
+		transaction := &models.Transaction{}
+		err := rows.Scan(
+			&transaction.ID,
+			&transaction.BankAccountID,
+			&transaction.CategoryID,
+			&transaction.Amount,
+			&transaction.Description,
+			&transaction.TransactionType,
+			&transaction.Date,
+			&transaction.CreatedAt,
+			&transaction.UpdatedAt,
+			&transaction.ToAccountID,
+			&transaction.TransferRate,
+			&transaction.IsPlanned,
+			&transaction.ExternalID,
+			&transaction.InstallmentPlanID,
+		)
+		if err != nil {
+			return transactions, fmt.Errorf("error getting transaction: %v", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, nil
+}
+
+// GetExistingExternalIDs возвращает те externalIDs, которые уже есть у транзакций счета
+func (r *TransactionRepository) GetExistingExternalIDs(bankAccountID int64, externalIDs []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(externalIDs) == 0 {
+		return existing, nil
+	}
+	query := `
+	select external_id from transactions
+	where bank_account_id = $1 and external_id = any($2)`
+	rows, err := r.db.Query(query, bankAccountID, pq.Array(externalIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error getting external ids: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var externalID string
+		if err := rows.Scan(&externalID); err != nil {
+			return existing, fmt.Errorf("error scanning external id: %v", err)
+		}
+		existing[externalID] = true
+	}
+	return existing, nil
+}
+
 // TODO надо везде дату добавить а то есть лимиты но нет даты я хз на каком уровне его добавить но надо
 func (r *TransactionRepository) GetByCategoryID(CategoryID int64, limit, offset int) ([]*models.Transaction, error) {
 	query := ` 
 	select id, bank_account_id, category_id, amount, description, transaction_type, 
-               date, created_at, updated_at, to_account_id, transfer_rate 
-	from transactions where category_id = $1
+               date, created_at, updated_at, to_account_id, transfer_rate, is_planned, external_id, installment_plan_id 
+	from transactions where (category_id = $1 
+	    or exists (select 1 from transaction_splits s where s.transaction_id = transactions.id and s.category_id = $1))
 		order by created_at desc limit $2 offset $3
 
 `
@@ -107,6 +364,9 @@ func (r *TransactionRepository) GetByCategoryID(CategoryID int64, limit, offset
 			&transaction.UpdatedAt,
 			&transaction.ToAccountID,
 			&transaction.TransferRate,
+			&transaction.IsPlanned,
+			&transaction.ExternalID,
+			&transaction.InstallmentPlanID,
 		)
 		transactions = append(transactions, transaction)
 		if err != nil {
@@ -119,7 +379,7 @@ func (r *TransactionRepository) GetByCategoryID(CategoryID int64, limit, offset
 func (r *TransactionRepository) GetByTransactionID(TransactionID int64) (*models.Transaction, error) {
 	query := ` 
 	select id, bank_account_id, category_id, amount, description, transaction_type, 
-               date, created_at, updated_at, to_account_id, transfer_rate 
+               date, created_at, updated_at, to_account_id, transfer_rate, is_planned, external_id, installment_plan_id 
 	from transactions where id = $1
 `
 	transaction := &models.Transaction{}
@@ -135,13 +395,20 @@ func (r *TransactionRepository) GetByTransactionID(TransactionID int64) (*models
 		&transaction.UpdatedAt,
 		&transaction.ToAccountID,
 		&transaction.TransferRate,
+		&transaction.IsPlanned,
+		&transaction.ExternalID,
+		&transaction.InstallmentPlanID,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("transaction with id %d not found", TransactionID)
+			return nil, fmt.Errorf("transaction with id %d not found: %w", TransactionID, err)
 		}
 		return nil, fmt.Errorf("error getting transaction: %v", err)
 	}
+	transaction.Splits, err = r.GetSplitsByTransactionID(TransactionID)
+	if err != nil {
+		return nil, err
+	}
 	return transaction, nil
 
 }
@@ -149,7 +416,7 @@ func (r *TransactionRepository) GetByTransactionID(TransactionID int64) (*models
 func (r *TransactionRepository) GetByAccountID(AccountID int64, limit, offset int) ([]*models.Transaction, error) {
 	query := ` 
 	select t.id , t.bank_account_id, t.category_id, t.amount, t.description, t.transaction_type,
-	t.date, t.created_at, t.updated_at, to_account_id, t.transfer_rate 
+	t.date, t.created_at, t.updated_at, to_account_id, t.transfer_rate, t.is_planned, t.external_id, t.installment_plan_id 
 from transactions t 
 	join bank_accounts ba on t.bank_account_id = ba.id
 	where ba.account_id = $1
@@ -178,6 +445,9 @@ from transactions t
 			&transaction.UpdatedAt,
 			&transaction.ToAccountID,
 			&transaction.TransferRate,
+			&transaction.IsPlanned,
+			&transaction.ExternalID,
+			&transaction.InstallmentPlanID,
 		)
 		if err != nil {
 			return transactions, fmt.Errorf("error getting transaction: %v", err)
@@ -190,7 +460,7 @@ from transactions t
 func (r *TransactionRepository) GetTransfersByAccountID(AccountID int64) ([]*models.Transaction, error) {
 	query := `
 	select t.id, t.bank_account_id, t.category_id, t.amount, t.description, t.transaction_type,
-	t.date, t.created_at, t.updated_at, to_account_id, t.transfer_rate
+	t.date, t.created_at, t.updated_at, to_account_id, t.transfer_rate, t.is_planned, t.external_id, t.installment_plan_id
 from transactions t
 	join bank_accounts ba on t.bank_account_id = ba.id
 	where ba.account_id = $1 and t.transaction_type = 'transfer'
@@ -216,7 +486,10 @@ from transactions t
 			&transaction.CreatedAt,
 			&transaction.UpdatedAt,
 			&transaction.ToAccountID,
-			&transaction.TransferRate)
+			&transaction.TransferRate,
+			&transaction.IsPlanned,
+			&transaction.ExternalID,
+			&transaction.InstallmentPlanID)
 		if err != nil {
 			return transactions, fmt.Errorf("error getting transaction: %v", err)
 		}
@@ -227,7 +500,7 @@ from transactions t
 
 }
 
-func (r *TransactionRepository) CreateTransaction(AccountID, FromBankAccountID, categoryID *int64, toAccountID int64, amount float64, description string, transferRate *float64) error {
+func (r *TransactionRepository) CreateTransaction(AccountID, FromBankAccountID, categoryID *int64, toAccountID int64, amount, receivedAmount models.Money, description string, transferRate *float64) error {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return err
@@ -244,7 +517,7 @@ func (r *TransactionRepository) CreateTransaction(AccountID, FromBankAccountID,
 	_, err = tx.Exec(outgoingQuery,
 		FromBankAccountID,
 		categoryID,
-		-amount,
+		amount.Neg(),
 		description,
 		"transfer",
 		now,
@@ -255,11 +528,6 @@ func (r *TransactionRepository) CreateTransaction(AccountID, FromBankAccountID,
 	if err != nil {
 		return fmt.Errorf("error creating transaction: %v", err)
 	}
-	incomingAmount := amount
-	if transferRate != nil {
-		incomingAmount = amount * (*transferRate)
-	}
-
 	incomingQuery := ` 
 	insert into transactions (bank_account_id, category_id, amount, description, 
 	                          transaction_type, date , created_at , updated_at, to_account_id, transfer_rate )
@@ -268,7 +536,7 @@ func (r *TransactionRepository) CreateTransaction(AccountID, FromBankAccountID,
 	_, err = tx.Exec(incomingQuery,
 		toAccountID,
 		categoryID,
-		incomingAmount,
+		receivedAmount,
 		description,
 		"transfer",
 		now,
@@ -279,6 +547,12 @@ func (r *TransactionRepository) CreateTransaction(AccountID, FromBankAccountID,
 	if err != nil {
 		return fmt.Errorf("error creating transaction: %v", err)
 	}
+	if err = applyBalanceChange(tx, *FromBankAccountID, amount.Neg(), now, false); err != nil {
+		return err
+	}
+	if err = applyBalanceChange(tx, toAccountID, receivedAmount, now, false); err != nil {
+		return err
+	}
 
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("error committing transaction: %v", err)
@@ -286,13 +560,14 @@ func (r *TransactionRepository) CreateTransaction(AccountID, FromBankAccountID,
 	return nil
 }
 
-func (r *TransactionRepository) GetTotalAmountByBankAccountID(BankAccountID int64) (float64, error) {
+// GetTotalAmountByBankAccountID - текущий остаток счета из bank_account_balances, 0 если транзакций еще не было
+func (r *TransactionRepository) GetTotalAmountByBankAccountID(BankAccountID int64) (models.Money, error) {
 
 	query := `
-	select sum(amount) from transactions where bank_account_id = $1
+	select COALESCE((select balance from bank_account_balances where bank_account_id = $1), 0)
 `
 	row := r.db.QueryRow(query, BankAccountID)
-	var amount float64
+	var amount models.Money
 	err := row.Scan(&amount)
 	if err != nil {
 		return amount, fmt.Errorf("error getting total amount by bank account id: %v", err)
@@ -301,37 +576,155 @@ func (r *TransactionRepository) GetTotalAmountByBankAccountID(BankAccountID int6
 
 }
 
-func (r *TransactionRepository) GetSpentAmountByCategoryAndMonth(categoryID int64, year, month int) (float64, error) {
-	query := ` 
-	select COALESCE(SUM(ABS(amount)), 0) 
--- 	    as total // можно тотал убрать и после amount умножить все в тг 
-from transactions where category_id =$1 
-	and transaction_type ='expense' 
-	and extract(year from date) =$2 
-	and extract(month from date) =$3
--- 	group by currency; // хз вот убрать или нет 
+// GetInflowByBankAccountAndDateRange - сумма пополнений счета (положительных транзакций) за [startDate, endDate)
+func (r *TransactionRepository) GetInflowByBankAccountAndDateRange(bankAccountID int64, startDate, endDate time.Time) (models.Money, error) {
+	query := `
+	select COALESCE(SUM(amount), 0) from transactions
+	where bank_account_id = $1
+	    and amount > 0
+	    and is_planned = false
+	    and date >= $2
+	    and date < $3
 `
-	row := r.db.QueryRow(query, categoryID, year, month)
-	var amount float64
-	err := row.Scan(&amount)
+	var amount models.Money
+	err := r.db.QueryRow(query, bankAccountID, startDate, endDate).Scan(&amount)
 	if err != nil {
-		return amount, fmt.Errorf("error getting total amount by bank account id: %v", err)
+		return amount, fmt.Errorf("error getting inflow by bank account: %v", err)
 	}
 	return amount, nil
 }
 
-func (r *TransactionRepository) GetTransactionsByCategoryAndMonth(categoryID int64, year, month int, limit, offset int) ([]*models.Transaction, error) {
+// GetBalancesByAccountAsOf - остатки банковских счетов аккаунта по транзакциям до момента before (не включая)
+func (r *TransactionRepository) GetBalancesByAccountAsOf(accountID int64, before time.Time) (map[int64]models.Money, error) {
+	query := `
+	select t.bank_account_id, COALESCE(SUM(t.amount), 0)
+	from transactions t
+	    join bank_accounts ba on t.bank_account_id = ba.id
+	where ba.account_id = $1
+	    and t.is_planned = false
+	    and t.date < $2
+	group by t.bank_account_id
+`
+	rows, err := r.db.Query(query, accountID, before)
+	if err != nil {
+		return nil, fmt.Errorf("error getting balances: %v", err)
+	}
+	defer rows.Close()
+	balances := make(map[int64]models.Money)
+	for rows.Next() {
+		var bankAccountID int64
+		var balance models.Money
+		if err := rows.Scan(&bankAccountID, &balance); err != nil {
+			return balances, fmt.Errorf("error scanning balance: %v", err)
+		}
+		balances[bankAccountID] = balance
+	}
+	return balances, nil
+}
+
+// GetSpentAmountByAccountAndPeriod - траты всех категорий аккаунта за период [periodStart, periodEnd) по категориям и валютам счетов
+// границы периода передаются в таймзоне аккаунта (utils.DateRange по датам бюджета), а не считаются в таймзоне сессии БД
+func (r *TransactionRepository) GetSpentAmountByAccountAndPeriod(accountID int64, periodStart, periodEnd time.Time) ([]*models.CategorySpending, error) {
+	query := `
+	select t.category_id, ba.currency, COALESCE(SUM(ABS(t.amount)), 0)
+	from transaction_lines t
+	    join bank_accounts ba on t.bank_account_id = ba.id
+	where ba.account_id = $1
+	    and t.category_id is not null
+	    and t.transaction_type = 'expense'
+	    and t.is_planned = false
+	    and t.date >= $2
+	    and t.date < $3
+	group by t.category_id, ba.currency
+`
+	rows, err := r.db.Query(query, accountID, periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("error getting spent amount by account: %v", err)
+	}
+	defer rows.Close()
+	spending := make([]*models.CategorySpending, 0)
+	for rows.Next() {
+		item := &models.CategorySpending{}
+		if err := rows.Scan(&item.CategoryID, &item.Currency, &item.Amount); err != nil {
+			return spending, fmt.Errorf("error scanning spent amount: %v", err)
+		}
+		item.Amount.Currency = item.Currency
+		spending = append(spending, item)
+	}
+	return spending, nil
+}
+
+// GetBalancesByAccountID - текущие остатки всех банковских счетов аккаунта одним запросом (из bank_account_balances)
+func (r *TransactionRepository) GetBalancesByAccountID(accountID int64) (map[int64]models.Money, error) {
+	query := `
+	select b.bank_account_id, b.balance
+	from bank_account_balances b
+	    join bank_accounts ba on b.bank_account_id = ba.id
+	where ba.account_id = $1
+`
+	rows, err := r.db.Query(query, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting balances: %v", err)
+	}
+	defer rows.Close()
+	balances := make(map[int64]models.Money)
+	for rows.Next() {
+		var bankAccountID int64
+		var balance models.Money
+		if err := rows.Scan(&bankAccountID, &balance); err != nil {
+			return balances, fmt.Errorf("error scanning balance: %v", err)
+		}
+		balances[bankAccountID] = balance
+	}
+	return balances, nil
+}
+
+// GetSpentAmountByCategoryAndPeriod - траты по категории за период [periodStart, periodEnd) отдельно по валютам счетов
+// границы периода - в таймзоне аккаунта, в валюту бюджета пересчитывает сервис
+func (r *TransactionRepository) GetSpentAmountByCategoryAndPeriod(categoryID int64, periodStart, periodEnd time.Time) ([]*models.CurrencyBalance, error) {
+	query := ` 
+	select ba.currency, COALESCE(SUM(ABS(t.amount)), 0) 
+from transaction_lines t
+	join bank_accounts ba on t.bank_account_id = ba.id
+where t.category_id =$1 
+	and t.transaction_type ='expense' 
+	and t.is_planned = false
+	and t.date >= $2
+	and t.date < $3
+group by ba.currency
+order by ba.currency
+`
+	rows, err := r.db.Query(query, categoryID, periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("error getting spent amount by category: %v", err)
+	}
+	defer rows.Close()
+	spent := make([]*models.CurrencyBalance, 0)
+	for rows.Next() {
+		item := &models.CurrencyBalance{}
+		if err := rows.Scan(&item.Currency, &item.Amount); err != nil {
+			return spent, fmt.Errorf("error scanning spent amount: %v", err)
+		}
+		item.Amount.Currency = item.Currency
+		spent = append(spent, item)
+	}
+	return spent, nil
+}
+
+// GetTransactionsByCategoryAndMonth - транзакции категории за месяц [monthStart, monthEnd) в таймзоне аккаунта
+func (r *TransactionRepository) GetTransactionsByCategoryAndMonth(categoryID int64, monthStart, monthEnd time.Time, limit, offset int) ([]*models.Transaction, error) {
 	query := `
         SELECT id, bank_account_id, category_id, amount, description, transaction_type, 
-               date, created_at, updated_at, to_account_id, transfer_rate 
+               date, created_at, updated_at, to_account_id, transfer_rate, is_planned, external_id, installment_plan_id 
         FROM transactions 
-        WHERE category_id = $1 
-        AND EXTRACT(YEAR FROM date) = $2 
-        AND EXTRACT(MONTH FROM date) = $3
+        WHERE (category_id = $1 
+            OR EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = transactions.id AND s.category_id = $1))
+        AND date >= $2
+        AND date < $3
         ORDER BY date DESC 
         LIMIT $4 OFFSET $5
     `
-	rows, err := r.db.Query(query, categoryID, year, month, limit, offset)
+	rows, err := r.db.Query(query, categoryID, monthStart, monthEnd, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -351,6 +744,9 @@ func (r *TransactionRepository) GetTransactionsByCategoryAndMonth(categoryID int
 			&transaction.UpdatedAt,
 			&transaction.ToAccountID,
 			&transaction.TransferRate,
+			&transaction.IsPlanned,
+			&transaction.ExternalID,
+			&transaction.InstallmentPlanID,
 		)
 		if err != nil {
 			return transactions, fmt.Errorf("error getting transaction: %v", err)
@@ -365,9 +761,10 @@ func (r *TransactionRepository) GetTransactionsByCategoryAndMonth(categoryID int
 func (r *TransactionRepository) GetTransactionsByDateRangeWithCategory(categoryID int64, startDate, endDate time.Time, limit, offset int) ([]*models.Transaction, error) {
 	query := `
      SELECT id, bank_account_id, category_id, amount, description, transaction_type, 
-               date, created_at, updated_at, to_account_id, transfer_rate 
+               date, created_at, updated_at, to_account_id, transfer_rate, is_planned, external_id, installment_plan_id 
         FROM transactions
-        where category_id = $1
+        where (category_id = $1
+            or exists (select 1 from transaction_splits s where s.transaction_id = transactions.id and s.category_id = $1))
     AND date >= $2 
         AND date <= $3
         ORDER BY date DESC 
@@ -393,6 +790,9 @@ func (r *TransactionRepository) GetTransactionsByDateRangeWithCategory(categoryI
 			&transaction.UpdatedAt,
 			&transaction.ToAccountID,
 			&transaction.TransferRate,
+			&transaction.IsPlanned,
+			&transaction.ExternalID,
+			&transaction.InstallmentPlanID,
 		)
 		if err != nil {
 			return transactions, fmt.Errorf("error scanning transaction: %v", err)
@@ -405,7 +805,7 @@ func (r *TransactionRepository) GetTransactionsByDateRangeWithCategory(categoryI
 func (r *TransactionRepository) GetTransactionsByDateRange(startDate, endDate time.Time, limit, offset int) ([]*models.Transaction, error) {
 	query := `
      SELECT id, bank_account_id, category_id, amount, description, transaction_type, 
-               date, created_at, updated_at, to_account_id, transfer_rate 
+               date, created_at, updated_at, to_account_id, transfer_rate, is_planned, external_id, installment_plan_id 
         FROM transactions
         where date >= $1
         AND date <= $2
@@ -432,6 +832,113 @@ func (r *TransactionRepository) GetTransactionsByDateRange(startDate, endDate ti
 			&transaction.UpdatedAt,
 			&transaction.ToAccountID,
 			&transaction.TransferRate,
+			&transaction.IsPlanned,
+			&transaction.ExternalID,
+			&transaction.InstallmentPlanID,
+		)
+		if err != nil {
+			return transactions, fmt.Errorf("error scanning transaction: %v", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, nil
+}
+
+// аналитика
+
+func (r *TransactionRepository) GetIncomeExpenseByAccountAndDateRange(accountID int64, startDate, endDate time.Time) (models.Money, models.Money, error) {
+	query := `
+	select 
+	    COALESCE(SUM(case when t.transaction_type = 'income' then t.amount else 0 end), 0),
+	    COALESCE(SUM(case when t.transaction_type = 'expense' then ABS(t.amount) else 0 end), 0)
+	from transactions t
+	    join bank_accounts ba on t.bank_account_id = ba.id
+	where ba.account_id = $1
+	    and t.transaction_type in ('income', 'expense')
+	    and t.is_planned = false
+	    and t.date >= $2
+	    and t.date < $3
+`
+	var income, expense models.Money
+	err := r.db.QueryRow(query, accountID, startDate, endDate).Scan(&income, &expense)
+	if err != nil {
+		return income, expense, fmt.Errorf("error getting income and expense: %v", err)
+	}
+	return income, expense, nil
+}
+
+func (r *TransactionRepository) GetCategorySpendingByAccountAndDateRange(accountID int64, startDate, endDate time.Time) ([]*models.CategorySpending, error) {
+	query := `
+	select COALESCE(c.id, 0), COALESCE(c.name, 'Без категории'), SUM(ABS(t.amount)) as total
+	from transaction_lines t
+	    join bank_accounts ba on t.bank_account_id = ba.id
+	    left join categories c on t.category_id = c.id
+	where ba.account_id = $1
+	    and t.transaction_type = 'expense'
+	    and t.is_planned = false
+	    and t.date >= $2
+	    and t.date < $3
+	group by c.id, c.name
+	order by total desc
+`
+	rows, err := r.db.Query(query, accountID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("error getting category spending: %v", err)
+	}
+	defer rows.Close()
+	spending := make([]*models.CategorySpending, 0)
+	for rows.Next() {
+		item := &models.CategorySpending{}
+		err := rows.Scan(
+			&item.CategoryID,
+			&item.CategoryName,
+			&item.Amount,
+		)
+		if err != nil {
+			return spending, fmt.Errorf("error scanning category spending: %v", err)
+		}
+		spending = append(spending, item)
+	}
+	return spending, nil
+}
+
+func (r *TransactionRepository) GetTopExpensesByAccountAndDateRange(accountID int64, startDate, endDate time.Time, limit int) ([]*models.Transaction, error) {
+	query := `
+	select t.id, t.bank_account_id, t.category_id, t.amount, t.description, t.transaction_type,
+	t.date, t.created_at, t.updated_at, t.to_account_id, t.transfer_rate, t.is_planned, t.external_id, t.installment_plan_id
+from transactions t
+	join bank_accounts ba on t.bank_account_id = ba.id
+	where ba.account_id = $1
+	and t.transaction_type = 'expense'
+	and t.is_planned = false
+	and t.date >= $2
+	and t.date < $3
+	order by t.amount asc
+	limit $4
+`
+	rows, err := r.db.Query(query, accountID, startDate, endDate, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting top expenses: %v", err)
+	}
+	defer rows.Close()
+	transactions := make([]*models.Transaction, 0)
+	for rows.Next() {
+		transaction := &models.Transaction{}
+		err := rows.Scan(
+			&transaction.ID,
+			&transaction.BankAccountID,
+			&transaction.CategoryID,
+			&transaction.Amount,
+			&transaction.Description,
+			&transaction.TransactionType,
+			&transaction.Date,
+			&transaction.CreatedAt,
+			&transaction.UpdatedAt,
+			&transaction.ToAccountID,
+			&transaction.TransferRate,
+			&transaction.IsPlanned,
+			&transaction.ExternalID,
+			&transaction.InstallmentPlanID,
 		)
 		if err != nil {
 			return transactions, fmt.Errorf("error scanning transaction: %v", err)
@@ -440,3 +947,170 @@ func (r *TransactionRepository) GetTransactionsByDateRange(startDate, endDate ti
 	}
 	return transactions, nil
 }
+
+func (r *TransactionRepository) GetIncomeExpenseByCurrency(accountID int64, startDate, endDate time.Time) ([]*models.IncomeExpenseReport, error) {
+	query := `
+	select ba.currency,
+	    COALESCE(SUM(case when t.transaction_type = 'income' then t.amount else 0 end), 0),
+	    COALESCE(SUM(case when t.transaction_type = 'expense' then ABS(t.amount) else 0 end), 0)
+	from transactions t
+	    join bank_accounts ba on t.bank_account_id = ba.id
+	where ba.account_id = $1
+	    and t.transaction_type in ('income', 'expense')
+	    and t.is_planned = false
+	    and t.date >= $2
+	    and t.date < $3
+	group by ba.currency
+	order by ba.currency
+`
+	rows, err := r.db.Query(query, accountID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("error getting income and expense by currency: %v", err)
+	}
+	defer rows.Close()
+	reports := make([]*models.IncomeExpenseReport, 0)
+	for rows.Next() {
+		report := &models.IncomeExpenseReport{}
+		err := rows.Scan(
+			&report.Currency,
+			&report.TotalIncome,
+			&report.TotalExpense,
+		)
+		if err != nil {
+			return reports, fmt.Errorf("error scanning income and expense: %v", err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// GetIncomeExpenseSeries - доходы и расходы по периодам, groupBy: day, week, month
+// периоды режутся в таймзоне аккаунта, недели начинаются с weekStart, как у недельных бюджетов
+func (r *TransactionRepository) GetIncomeExpenseSeries(accountID int64, startDate, endDate time.Time, groupBy, timezone string, weekStart time.Weekday) ([]*models.IncomeExpensePoint, error) {
+	// date_trunc('week') всегда дает понедельник: дата сдвигается так, чтобы weekStart стал понедельником,
+	// и начало недели сдвигается обратно
+	weekShift := (8 - int(weekStart)) % 7
+	query := `
+	select case when $4 = 'week'
+	        then date_trunc('week', (t.date at time zone $5) + make_interval(days => $6::int)) - make_interval(days => $6::int)
+	        else date_trunc($4, t.date at time zone $5)
+	    end as period, ba.currency,
+	    COALESCE(SUM(case when t.transaction_type = 'income' then t.amount else 0 end), 0),
+	    COALESCE(SUM(case when t.transaction_type = 'expense' then ABS(t.amount) else 0 end), 0)
+	from transactions t
+	    join bank_accounts ba on t.bank_account_id = ba.id
+	where ba.account_id = $1
+	    and t.transaction_type in ('income', 'expense')
+	    and t.is_planned = false
+	    and t.date >= $2
+	    and t.date < $3
+	group by period, ba.currency
+	order by period, ba.currency
+`
+	rows, err := r.db.Query(query, accountID, startDate, endDate, groupBy, timezone, weekShift)
+	if err != nil {
+		return nil, fmt.Errorf("error getting income and expense series: %v", err)
+	}
+	defer rows.Close()
+	loc := startDate.Location()
+	points := make([]*models.IncomeExpensePoint, 0)
+	for rows.Next() {
+		point := &models.IncomeExpensePoint{}
+		var period time.Time
+		err := rows.Scan(
+			&period,
+			&point.Currency,
+			&point.TotalIncome,
+			&point.TotalExpense,
+		)
+		if err != nil {
+			return points, fmt.Errorf("error scanning income and expense series: %v", err)
+		}
+		// date_trunc возвращает timestamp без таймзоны - это локальное время аккаунта
+		point.PeriodStart = time.Date(period.Year(), period.Month(), period.Day(), 0, 0, 0, 0, loc)
+		point.NetIncome = point.TotalIncome.Sub(point.TotalExpense)
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+func (r *TransactionRepository) GetCategorySpendingByCurrency(accountID int64, startDate, endDate time.Time) ([]*models.CategorySpending, error) {
+	query := `
+	select COALESCE(c.id, 0), COALESCE(c.name, 'Без категории'), ba.currency, SUM(ABS(t.amount)) as total
+	from transaction_lines t
+	    join bank_accounts ba on t.bank_account_id = ba.id
+	    left join categories c on t.category_id = c.id
+	where ba.account_id = $1
+	    and t.transaction_type = 'expense'
+	    and t.is_planned = false
+	    and t.date >= $2
+	    and t.date < $3
+	group by ba.currency, c.id, c.name
+	order by ba.currency, total desc
+`
+	rows, err := r.db.Query(query, accountID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("error getting category spending: %v", err)
+	}
+	defer rows.Close()
+	spending := make([]*models.CategorySpending, 0)
+	for rows.Next() {
+		item := &models.CategorySpending{}
+		err := rows.Scan(
+			&item.CategoryID,
+			&item.CategoryName,
+			&item.Currency,
+			&item.Amount,
+		)
+		if err != nil {
+			return spending, fmt.Errorf("error scanning category spending: %v", err)
+		}
+		spending = append(spending, item)
+	}
+	return spending, nil
+}
+
+// GetPlannedFlowSeries - запланированные доходы и расходы по месяцам (в таймзоне timezone) и валютам счетов
+// платежи по рассрочкам входят в расходы и отдельно - в installments
+func (r *TransactionRepository) GetPlannedFlowSeries(accountID int64, startDate, endDate time.Time, timezone string) ([]*models.PlannedFlowPoint, error) {
+	query := `
+	select date_trunc('month', t.date at time zone $4) as period, ba.currency,
+	    COALESCE(SUM(case when t.transaction_type = 'income' then t.amount else 0 end), 0),
+	    COALESCE(SUM(case when t.transaction_type = 'expense' then ABS(t.amount) else 0 end), 0),
+	    COALESCE(SUM(case when t.installment_plan_id is not null then ABS(t.amount) else 0 end), 0)
+	from transactions t
+	    join bank_accounts ba on t.bank_account_id = ba.id
+	where ba.account_id = $1
+	    and t.transaction_type in ('income', 'expense')
+	    and t.is_planned = true
+	    and t.date >= $2
+	    and t.date < $3
+	group by period, ba.currency
+	order by period, ba.currency
+`
+	rows, err := r.db.Query(query, accountID, startDate, endDate, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("error getting planned flow series: %v", err)
+	}
+	defer rows.Close()
+	loc := startDate.Location()
+	points := make([]*models.PlannedFlowPoint, 0)
+	for rows.Next() {
+		point := &models.PlannedFlowPoint{}
+		var period time.Time
+		err := rows.Scan(
+			&period,
+			&point.Currency,
+			&point.Income,
+			&point.Expense,
+			&point.Installments,
+		)
+		if err != nil {
+			return points, fmt.Errorf("error scanning planned flow series: %v", err)
+		}
+		// date_trunc возвращает timestamp без таймзоны - это локальное время аккаунта
+		point.PeriodStart = time.Date(period.Year(), period.Month(), period.Day(), 0, 0, 0, 0, loc)
+		points = append(points, point)
+	}
+	return points, nil
+}
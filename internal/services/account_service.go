@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"justTest/internal/interfaces"
 	"justTest/internal/models"
+	"justTest/internal/utils"
+	"sort"
 	"time"
 )
 
@@ -11,6 +13,8 @@ type AccountService struct {
 	accountRepo     interfaces.AccountRepository
 	BankAccountRepo interfaces.BankAccountRepository
 	transactionRepo interfaces.TransactionRepository
+	rateService     *RateService
+	budgetService   *BudgetService
 	authService     models.AuthService
 }
 
@@ -18,12 +22,16 @@ func NewAccountService(
 	accountRepo interfaces.AccountRepository,
 	bankAccountRepo interfaces.BankAccountRepository,
 	transactionRepo interfaces.TransactionRepository,
+	rateService *RateService,
+	budgetService *BudgetService,
 	authService models.AuthService,
 ) *AccountService {
 	return &AccountService{
 		accountRepo:     accountRepo,
 		BankAccountRepo: bankAccountRepo,
 		transactionRepo: transactionRepo,
+		rateService:     rateService,
+		budgetService:   budgetService,
 		authService:     authService,
 	}
 }
@@ -59,11 +67,13 @@ func (s *AccountService) CreateAccount(userID string, displayName string) (*mode
 	//	return nil, fmt.Errorf("get user by id failed, err:%v", err)
 	//}
 	newAccount := &models.Account{
-		UserID:      userID,
-		DisplayName: displayName,
-		Name:        displayName,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		UserID:       userID,
+		DisplayName:  displayName,
+		Name:         displayName,
+		BaseCurrency: defaultBaseCurrency,
+		WeekStartDay: int(time.Monday),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
 		// потом добавлю еще чтот по сути
 	}
 	createdAccount, err := s.accountRepo.Create(newAccount)
@@ -74,4 +84,177 @@ func (s *AccountService) CreateAccount(userID string, displayName string) (*mode
 
 }
 
-// summary надо написать потом как нибудь
+// UpdateAccount меняет имя, таймзону, первый день недели и базовую валюту аккаунта
+// в базовой валюте считаются чистые активы, отчеты и бюджеты
+// таймзона - имя IANA ("Asia/Almaty"), от нее зависят границы дней, недель и месяцев в бюджетах и отчетах,
+// поэтому при ее смене остатки на конец дня пересчитываются в той же транзакции БД
+func (s *AccountService) UpdateAccount(userID string, req *models.UpdateAccountRequest) (*models.Account, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id: empty")
+	}
+	if req.Timezone == "" || req.Timezone == "Local" {
+		return nil, fmt.Errorf("invalid timezone %q", req.Timezone)
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", req.Timezone)
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	timezoneChanged := account.Timezone != req.Timezone
+	account.DisplayName = req.DisplayName
+	account.Timezone = req.Timezone
+	if req.WeekStartDay != nil {
+		account.WeekStartDay = *req.WeekStartDay
+	}
+	if req.BaseCurrency != nil {
+		account.BaseCurrency = *req.BaseCurrency
+	}
+	account.UpdatedAt = time.Now()
+	if timezoneChanged {
+		return s.accountRepo.UpdateAndRebuildBalances(account)
+	}
+	return s.accountRepo.Update(account)
+}
+
+// GetNetWorth - остатки активных счетов на конец дня date (в таймзоне аккаунта) в базовой валюте
+// остатки в других валютах пересчитываются по курсам на date, нулевая date - сегодня
+func (s *AccountService) GetNetWorth(userID string, date time.Time) (*models.NetWorth, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id: empty")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := utils.LoadLocation(account.Timezone)
+	if date.IsZero() {
+		date = time.Now().In(loc)
+	}
+	start, end := utils.DateRange(date, date, loc)
+
+	bankAccounts, err := s.BankAccountRepo.GetActiveBankAccounts(account.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get bank accounts: %w", err)
+	}
+	balances, err := s.transactionRepo.GetBalancesByAccountAsOf(account.ID, end)
+	if err != nil {
+		return nil, fmt.Errorf("get balances: %w", err)
+	}
+
+	currency := baseCurrency(account)
+	netWorth := &models.NetWorth{
+		Date:         start,
+		BaseCurrency: currency,
+		Total:        models.NewMoney(0, currency),
+		Currencies:   make([]*models.NetWorthCurrency, 0),
+	}
+	byCurrency := make(map[string]*models.NetWorthCurrency)
+	for _, bankAccount := range bankAccounts {
+		subtotal, ok := byCurrency[bankAccount.Currency]
+		if !ok {
+			subtotal = &models.NetWorthCurrency{
+				Currency:     bankAccount.Currency,
+				Amount:       models.NewMoney(0, bankAccount.Currency),
+				BankAccounts: make([]*models.BankAccountSummary, 0),
+			}
+			byCurrency[bankAccount.Currency] = subtotal
+			netWorth.Currencies = append(netWorth.Currencies, subtotal)
+		}
+		balance := balances[bankAccount.ID].WithCurrency(bankAccount.Currency)
+		subtotal.Amount = subtotal.Amount.Add(balance)
+		subtotal.BankAccounts = append(subtotal.BankAccounts, &models.BankAccountSummary{
+			BankAccount: bankAccount,
+			Balance:     balance,
+		})
+	}
+	sort.Slice(netWorth.Currencies, func(i, j int) bool {
+		return netWorth.Currencies[i].Currency < netWorth.Currencies[j].Currency
+	})
+	for _, subtotal := range netWorth.Currencies {
+		converted, quote, err := s.rateService.Convert(subtotal.Amount, subtotal.Currency, currency, start)
+		if err != nil {
+			return nil, fmt.Errorf("convert %s balance: %w", subtotal.Currency, err)
+		}
+		subtotal.Converted = converted
+		subtotal.Rate = quote
+		netWorth.Total = netWorth.Total.Add(converted)
+	}
+	return netWorth, nil
+}
+
+// GetAccountSummary - сводка по аккаунту: остатки активных счетов и по валютам,
+// доходы и расходы за текущий месяц (в таймзоне аккаунта) и статусы бюджетов этого месяца
+// все считается агрегирующими запросами, без запроса на каждый счет или бюджет
+func (s *AccountService) GetAccountSummary(userID string) (*models.AccountSummary, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id: empty")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := utils.LoadLocation(account.Timezone)
+	now := time.Now().In(loc)
+	monthStart, monthEnd := utils.MonthRange(now.Year(), int(now.Month()), loc)
+
+	bankAccounts, err := s.BankAccountRepo.GetActiveBankAccounts(account.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get bank accounts: %w", err)
+	}
+	balances, err := s.transactionRepo.GetBalancesByAccountID(account.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get balances: %w", err)
+	}
+	summary := &models.AccountSummary{
+		Account:         account,
+		Balances:        make([]*models.CurrencyBalance, 0),
+		BankAccounts:    make([]*models.BankAccountSummary, 0, len(bankAccounts)),
+		MonthlyIncome:   make([]*models.CurrencyBalance, 0),
+		MonthlyExpenses: make([]*models.CurrencyBalance, 0),
+		Budgets:         make([]*models.BudgetStatus, 0),
+	}
+	byCurrency := make(map[string]*models.CurrencyBalance)
+	for _, bankAccount := range bankAccounts {
+		balance := balances[bankAccount.ID].WithCurrency(bankAccount.Currency)
+		summary.BankAccounts = append(summary.BankAccounts, &models.BankAccountSummary{
+			BankAccount: bankAccount,
+			Balance:     balance,
+		})
+		total, ok := byCurrency[bankAccount.Currency]
+		if !ok {
+			total = &models.CurrencyBalance{Currency: bankAccount.Currency, Amount: models.NewMoney(0, bankAccount.Currency)}
+			byCurrency[bankAccount.Currency] = total
+			summary.Balances = append(summary.Balances, total)
+		}
+		total.Amount = total.Amount.Add(balance)
+	}
+	sort.Slice(summary.Balances, func(i, j int) bool {
+		return summary.Balances[i].Currency < summary.Balances[j].Currency
+	})
+
+	reports, err := s.transactionRepo.GetIncomeExpenseByCurrency(account.ID, monthStart, monthEnd)
+	if err != nil {
+		return nil, fmt.Errorf("get income and expense: %w", err)
+	}
+	for _, report := range reports {
+		summary.MonthlyIncome = append(summary.MonthlyIncome, &models.CurrencyBalance{
+			Currency: report.Currency,
+			Amount:   report.TotalIncome,
+		})
+		summary.MonthlyExpenses = append(summary.MonthlyExpenses, &models.CurrencyBalance{
+			Currency: report.Currency,
+			Amount:   report.TotalExpense,
+		})
+	}
+
+	budgets, err := s.budgetService.GetBudgets(userID, "", now)
+	if err != nil {
+		return nil, fmt.Errorf("get budgets: %w", err)
+	}
+	for _, budget := range budgets {
+		summary.Budgets = append(summary.Budgets, budget.Status)
+	}
+	return summary, nil
+}
@@ -1,37 +1,304 @@
 package services
 
 import (
+	"fmt"
+	"justTest/internal/interfaces"
 	"justTest/internal/models"
-	"justTest/internal/repo"
+	"justTest/internal/utils"
+	"sort"
 	"time"
 )
 
+const defaultTopExpensesLimit = 5
+
+// на сколько месяцев вперед строится прогноз остатков
+const (
+	defaultForecastMonths = 6
+	maxForecastMonths     = 24
+)
+
 type AnalyticsService struct {
-	transactionRepo repo.TransactionRepository
-	accountRepo     repo.AccountRepository
+	transactionRepo interfaces.TransactionRepository
+	accountRepo     interfaces.AccountRepository
+	bankAccountRepo interfaces.BankAccountRepository
+	rateService     *RateService
 }
 
-func NewAnalyticsService(transactionRepo repo.TransactionRepository, accountRepo repo.AccountRepository) *AnalyticsService {
+func NewAnalyticsService(
+	transactionRepo interfaces.TransactionRepository,
+	accountRepo interfaces.AccountRepository,
+	bankAccountRepo interfaces.BankAccountRepository,
+	rateService *RateService,
+) *AnalyticsService {
 	return &AnalyticsService{
 		transactionRepo: transactionRepo,
 		accountRepo:     accountRepo,
+		bankAccountRepo: bankAccountRepo,
+		rateService:     rateService,
 	}
 }
 
 // GetMonthlyReport - отчет за месяц
-func (s *AnalyticsService) GetMonthlyReport(userID string, year int, month int) (*models.MonthlyReport, error) {
-	// TODO: Реализовать получение отчета за месяц
-	return nil, nil
+// границы месяца считаются в таймзоне аккаунта, переводы не считаются доходом или расходом
+// суммы со счетов в разных валютах сводятся в базовую валюту по курсу на конец месяца
+func (s *AnalyticsService) GetMonthlyReport(userID string, year int, month int, topLimit int) (*models.MonthlyReport, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if month < 1 || month > 12 {
+		return nil, fmt.Errorf("invalid month")
+	}
+	if topLimit <= 0 {
+		topLimit = defaultTopExpensesLimit
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	startDate, endDate := utils.MonthRange(year, month, utils.LoadLocation(account.Timezone))
+
+	currency := baseCurrency(account)
+	rateDate := conversionDate(endDate)
+
+	byCurrency, err := s.transactionRepo.GetIncomeExpenseByCurrency(account.ID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("get income and expense: %w", err)
+	}
+	income := models.NewMoney(0, currency)
+	expense := models.NewMoney(0, currency)
+	for _, item := range byCurrency {
+		convertedIncome, _, err := s.rateService.Convert(item.TotalIncome, item.Currency, currency, rateDate)
+		if err != nil {
+			return nil, fmt.Errorf("convert income: %w", err)
+		}
+		convertedExpense, _, err := s.rateService.Convert(item.TotalExpense, item.Currency, currency, rateDate)
+		if err != nil {
+			return nil, fmt.Errorf("convert expense: %w", err)
+		}
+		income = income.Add(convertedIncome)
+		expense = expense.Add(convertedExpense)
+	}
+	spending, err := s.transactionRepo.GetCategorySpendingByCurrency(account.ID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("get category spending: %w", err)
+	}
+	categories, err := s.mergeCategorySpending(spending, currency, rateDate)
+	if err != nil {
+		return nil, err
+	}
+	fillCategoryPercentages(categories, expense)
+
+	topExpenses, err := s.transactionRepo.GetTopExpensesByAccountAndDateRange(account.ID, startDate, endDate, topLimit)
+	if err != nil {
+		return nil, fmt.Errorf("get top expenses: %w", err)
+	}
+
+	report := &models.MonthlyReport{
+		Month:        month,
+		Year:         year,
+		Currency:     currency,
+		TotalIncome:  income,
+		TotalExpense: expense,
+		NetIncome:    income.Sub(expense),
+		Categories:   categories,
+		TopExpenses:  topExpenses,
+	}
+	return report, nil
 }
 
-// GetCategorySpending - траты по категориям
+// GetCategorySpending - траты по категориям за период, отдельно по каждой валюте
 func (s *AnalyticsService) GetCategorySpending(userID string, startDate, endDate time.Time) ([]*models.CategorySpending, error) {
-	// TODO: Реализовать анализ трат по категориям
-	return nil, nil
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("end date is before start date")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	start, end := utils.DateRange(startDate, endDate, utils.LoadLocation(account.Timezone))
+
+	spending, err := s.transactionRepo.GetCategorySpendingByCurrency(account.ID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("get category spending: %w", err)
+	}
+	totals := make(map[string]models.Money)
+	for _, item := range spending {
+		totals[item.Currency] = totals[item.Currency].Add(item.Amount)
+	}
+	for _, item := range spending {
+		item.Percentage = item.Amount.Percent(totals[item.Currency])
+	}
+	return spending, nil
 }
 
-// GetIncomeVsExpenses - доходы vs расходы
-func (s *AnalyticsService) GetIncomeVsExpenses(userID string, startDate, endDate time.Time) (*models.IncomeExpenseReport, error) {
-	// TODO: Реализовать сравнение доходов и расходов
-	return nil, nil
+// GetIncomeVsExpenses - доходы vs расходы за период, один отчет на каждую валюту
+// groupBy (day, week, month) добавляет в отчет временной ряд, пустой groupBy - только итоги
+func (s *AnalyticsService) GetIncomeVsExpenses(userID string, startDate, endDate time.Time, groupBy string) ([]*models.IncomeExpenseReport, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("end date is before start date")
+	}
+	if groupBy != "" && groupBy != "day" && groupBy != "week" && groupBy != "month" {
+		return nil, fmt.Errorf("invalid group by: %s", groupBy)
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	loc := utils.LoadLocation(account.Timezone)
+	start, end := utils.DateRange(startDate, endDate, loc)
+
+	reports, err := s.transactionRepo.GetIncomeExpenseByCurrency(account.ID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("get income and expense: %w", err)
+	}
+	for _, report := range reports {
+		report.NetIncome = report.TotalIncome.Sub(report.TotalExpense)
+		if report.TotalIncome.IsPositive() {
+			report.SavingsRate = report.NetIncome.Percent(report.TotalIncome)
+		}
+	}
+	if groupBy == "" {
+		return reports, nil
+	}
+
+	points, err := s.transactionRepo.GetIncomeExpenseSeries(account.ID, start, end, groupBy, loc.String(), time.Weekday(account.WeekStartDay))
+	if err != nil {
+		return nil, fmt.Errorf("get income and expense series: %w", err)
+	}
+	byCurrency := make(map[string]*models.IncomeExpenseReport, len(reports))
+	for _, report := range reports {
+		byCurrency[report.Currency] = report
+	}
+	for _, point := range points {
+		if report, ok := byCurrency[point.Currency]; ok {
+			report.Periods = append(report.Periods, point)
+		}
+	}
+	return reports, nil
+}
+
+// GetCashFlowForecast - прогноз остатков на months месяцев, начиная с текущего:
+// к текущим остаткам активных счетов прибавляются запланированные транзакции с датой с этого момента,
+// в том числе платежи по рассрочкам; суммы в других валютах пересчитываются по сегодняшнему курсу
+func (s *AnalyticsService) GetCashFlowForecast(userID string, months int) (*models.CashFlowForecast, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if months <= 0 {
+		months = defaultForecastMonths
+	}
+	if months > maxForecastMonths {
+		return nil, fmt.Errorf("months must be between 1 and %d", maxForecastMonths)
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	loc := utils.LoadLocation(account.Timezone)
+	now := time.Now().In(loc)
+	monthStart, _ := utils.MonthRange(now.Year(), int(now.Month()), loc)
+	end := monthStart.AddDate(0, months, 0)
+	currency := baseCurrency(account)
+
+	bankAccounts, err := s.bankAccountRepo.GetActiveBankAccounts(account.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get bank accounts: %w", err)
+	}
+	balances, err := s.transactionRepo.GetBalancesByAccountID(account.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get balances: %w", err)
+	}
+	forecast := &models.CashFlowForecast{
+		Currency:     currency,
+		StartBalance: models.NewMoney(0, currency),
+		Months:       make([]*models.CashFlowMonth, 0, months),
+	}
+	for _, bankAccount := range bankAccounts {
+		balance, _, err := s.rateService.Convert(balances[bankAccount.ID].WithCurrency(bankAccount.Currency), bankAccount.Currency, currency, now)
+		if err != nil {
+			return nil, fmt.Errorf("convert %s balance: %w", bankAccount.Currency, err)
+		}
+		forecast.StartBalance = forecast.StartBalance.Add(balance)
+	}
+
+	points, err := s.transactionRepo.GetPlannedFlowSeries(account.ID, now, end, loc.String())
+	if err != nil {
+		return nil, fmt.Errorf("get planned transactions: %w", err)
+	}
+	balance := forecast.StartBalance
+	for i := 0; i < months; i++ {
+		month := &models.CashFlowMonth{
+			PeriodStart:    monthStart.AddDate(0, i, 0),
+			PlannedIncome:  models.NewMoney(0, currency),
+			PlannedExpense: models.NewMoney(0, currency),
+			Installments:   models.NewMoney(0, currency),
+		}
+		for _, point := range points {
+			if !point.PeriodStart.Equal(month.PeriodStart) {
+				continue
+			}
+			income, _, err := s.rateService.Convert(point.Income, point.Currency, currency, now)
+			if err != nil {
+				return nil, fmt.Errorf("convert planned income: %w", err)
+			}
+			expense, _, err := s.rateService.Convert(point.Expense, point.Currency, currency, now)
+			if err != nil {
+				return nil, fmt.Errorf("convert planned expense: %w", err)
+			}
+			installments, _, err := s.rateService.Convert(point.Installments, point.Currency, currency, now)
+			if err != nil {
+				return nil, fmt.Errorf("convert installments: %w", err)
+			}
+			month.PlannedIncome = month.PlannedIncome.Add(income)
+			month.PlannedExpense = month.PlannedExpense.Add(expense)
+			month.Installments = month.Installments.Add(installments)
+		}
+		month.NetFlow = month.PlannedIncome.Sub(month.PlannedExpense)
+		balance = balance.Add(month.NetFlow)
+		month.EndBalance = balance
+		forecast.Months = append(forecast.Months, month)
+	}
+	return forecast, nil
+}
+
+// mergeCategorySpending сводит траты категорий по валютам в одну сумму на категорию в валюте currency
+func (s *AnalyticsService) mergeCategorySpending(spending []*models.CategorySpending, currency string, rateDate time.Time) ([]*models.CategorySpending, error) {
+	categories := make([]*models.CategorySpending, 0, len(spending))
+	byID := make(map[int64]*models.CategorySpending, len(spending))
+	for _, item := range spending {
+		converted, _, err := s.rateService.Convert(item.Amount, item.Currency, currency, rateDate)
+		if err != nil {
+			return nil, fmt.Errorf("convert category spending: %w", err)
+		}
+		category, ok := byID[item.CategoryID]
+		if !ok {
+			category = &models.CategorySpending{
+				CategoryID:   item.CategoryID,
+				CategoryName: item.CategoryName,
+				Amount:       models.NewMoney(0, currency),
+			}
+			byID[item.CategoryID] = category
+			categories = append(categories, category)
+		}
+		category.Amount = category.Amount.Add(converted)
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].Amount.Cmp(categories[j].Amount) > 0
+	})
+	return categories, nil
+}
+
+func fillCategoryPercentages(categories []*models.CategorySpending, totalExpense models.Money) {
+	if !totalExpense.IsPositive() {
+		return
+	}
+	for _, category := range categories {
+		category.Percentage = category.Amount.Percent(totalExpense)
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"justTest/internal/interfaces"
 	"justTest/internal/models"
+	"justTest/internal/utils"
 	"time"
 )
 
@@ -21,7 +22,11 @@ func NewBankAccService(
 		accountRepo:           accountRepo,
 	}
 }
-func (s *BankAccService) CreateBankAccount(userID string, name, currency, accountType, bankName string) (*models.BankAccount, error) {
+
+// iban необязателен, нужен для импорта выписок camt.053/052 без выбора счета
+// openingBalance - остаток уже существующего счета на openingDate (дата в таймзоне аккаунта, пусто - сейчас),
+// сохраняется транзакцией opening_balance, которая не попадает в доходы, расходы и бюджеты
+func (s *BankAccService) CreateBankAccount(userID string, name, currency, accountType, bankName, iban string, openingBalance *models.Money, openingDate string) (*models.BankAccount, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("invalid user id")
 	}
@@ -37,6 +42,10 @@ func (s *BankAccService) CreateBankAccount(userID string, name, currency, accoun
 	if bankName == "" {
 		return nil, fmt.Errorf("empty bankName")
 	}
+	iban = utils.NormalizeIBAN(iban)
+	if len(iban) > 34 {
+		return nil, fmt.Errorf("invalid iban")
+	}
 	account, err := s.accountRepo.GetByUserID(userID)
 	if err != nil {
 		return nil, err
@@ -48,22 +57,61 @@ func (s *BankAccService) CreateBankAccount(userID string, name, currency, accoun
 	if exists {
 		return nil, fmt.Errorf("duplicate bank account")
 	}
+	opening, err := s.openingBalanceTransaction(account, currency, openingBalance, openingDate)
+	if err != nil {
+		return nil, err
+	}
 	newBankAccount := &models.BankAccount{
 		AccountID:   account.ID,
 		Name:        name,
 		Currency:    currency,
 		AccountType: accountType,
 		BankName:    bankName,
+		IBAN:        iban,
 		IsActive:    true,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
-	createdBankAccount, err := s.BankAccountRepository.Create(newBankAccount)
+	createdBankAccount, err := s.BankAccountRepository.CreateWithOpeningBalance(newBankAccount, opening)
 	if err != nil {
 		return nil, err
 	}
 	return createdBankAccount, nil
 }
+
+// openingBalanceTransaction - транзакция начального остатка, nil если остаток не указан или нулевой
+func (s *BankAccService) openingBalanceTransaction(account *models.Account, currency string, openingBalance *models.Money, openingDate string) (*models.Transaction, error) {
+	if openingBalance == nil {
+		if openingDate != "" {
+			return nil, fmt.Errorf("opening date without opening balance")
+		}
+		return nil, nil
+	}
+	amount := openingBalance.WithCurrency(currency)
+	if amount.IsZero() {
+		return nil, nil
+	}
+	now := time.Now()
+	date := now
+	if openingDate != "" {
+		var err error
+		date, err = utils.ParseLocalDate(openingDate, utils.LoadLocation(account.Timezone))
+		if err != nil {
+			return nil, fmt.Errorf("invalid opening date: %s", openingDate)
+		}
+		if date.After(now) {
+			return nil, fmt.Errorf("opening date is in the future")
+		}
+	}
+	return &models.Transaction{
+		Amount:          amount,
+		Description:     "Opening balance",
+		TransactionType: "opening_balance",
+		Date:            date,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}, nil
+}
 func (s *BankAccService) GetBankAccount(userID string, bankAccountID int64) (*models.BankAccount, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("invalid user id")
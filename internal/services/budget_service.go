@@ -5,15 +5,24 @@ import (
 	"justTest/internal/interfaces"
 	"justTest/internal/models"
 	"justTest/internal/models/events"
+	"justTest/internal/utils"
 	"log"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// budgetWarningPercent - с какого процента использования бюджета отправляется предупреждение
+const budgetWarningPercent = 80
+
 type BudgetService struct {
 	budgetRepo      interfaces.BudgetRepository
 	transactionRepo interfaces.TransactionRepository
 	accountRepo     interfaces.AccountRepository
 	categoryRepo    interfaces.CategoryRepository
+	installmentRepo interfaces.InstallmentRepository
+	rateService     *RateService
 	publisher       interface{}
 }
 
@@ -22,6 +31,8 @@ func NewBudgetService(
 	transactionRepo interfaces.TransactionRepository,
 	accountRepo interfaces.AccountRepository,
 	categoryRepo interfaces.CategoryRepository,
+	installmentRepo interfaces.InstallmentRepository,
+	rateService *RateService,
 	publisher interface{},
 ) *BudgetService {
 	return &BudgetService{
@@ -29,45 +40,84 @@ func NewBudgetService(
 		transactionRepo: transactionRepo,
 		accountRepo:     accountRepo,
 		categoryRepo:    categoryRepo,
+		installmentRepo: installmentRepo,
+		rateService:     rateService,
 		publisher:       publisher,
 	}
 }
 func (s *BudgetService) CheckBudgetAfterTransaction(event events.TransactionCreatedEvent) error {
-	log.Printf("[BudgetService] Checking budget for transaction: ID=%d, CategoryID=%d, Amount=%.2f",
+	log.Printf("[BudgetService] Checking budget for transaction: ID=%d, CategoryID=%d, Amount=%s",
 		event.TransactionID, event.CategoryID, event.Amount)
-	if event.Amount >= 0 {
-		log.Printf("[BudgetService] Skipping: not an expense (amount=%.2f)", event.Amount)
+	if !event.Amount.IsNegative() {
+		log.Printf("[BudgetService] Skipping: not an expense (amount=%s)", event.Amount)
 		return nil
 	}
 	//category, err := s.categoryRepo.GetByID(event.CategoryID)
 	//if err != nil {
 	//	return fmt.Errorf("get category: %w", err)
 	//}
-	now := time.Now()
-	year := now.Year()
-	month := int(now.Month())
-	budget, err := s.budgetRepo.GetBudgetByCategoryAndMonth(event.CategoryID, year, month)
-	if err != nil {
-		log.Printf("[BudgetService] No budget found for category %d in %d-%02d", event.CategoryID, year, month)
-		return nil
-	}
 	account, err := s.accountRepo.GetByUserID(event.UserID)
 	if err != nil {
 		return fmt.Errorf("get account: %w", err)
 	}
-	if budget.AccountID != account.ID {
-		log.Printf("[BudgetService] Budget does not belong to user")
+	// бюджеты проверяются за период самой транзакции (она может быть задним числом)
+	transactionDate := event.Date
+	if transactionDate.IsZero() {
+		transactionDate = time.Now()
+	}
+	transactionDate = transactionDate.In(utils.LoadLocation(account.Timezone))
+	// бюджеты всех категорий транзакции без повторов: общий бюджет и бюджет нескольких категорий
+	// проверяются один раз, даже если в разбивке несколько их категорий
+	categoryIDs := event.CategoryIDs
+	if len(categoryIDs) == 0 {
+		categoryIDs = []int64{event.CategoryID}
+	}
+	var budgets []*models.Budget
+	seen := make(map[int64]bool)
+	for _, categoryID := range categoryIDs {
+		categoryBudgets, err := s.budgetRepo.GetBudgetsByCategoryAndDate(categoryID, transactionDate)
+		if err != nil {
+			return fmt.Errorf("get budgets: %w", err)
+		}
+		for _, budget := range categoryBudgets {
+			if !seen[budget.ID] {
+				seen[budget.ID] = true
+				budgets = append(budgets, budget)
+			}
+		}
+	}
+	if len(budgets) == 0 {
+		log.Printf("[BudgetService] No budget found for categories %v on %s", categoryIDs, transactionDate.Format("2006-01-02"))
 		return nil
 	}
-	spentAmount, err := s.transactionRepo.GetSpentAmountByCategoryAndMonth(event.CategoryID, year, month)
+	for _, budget := range budgets {
+		if budget.AccountID != account.ID {
+			log.Printf("[BudgetService] Budget does not belong to user")
+			continue
+		}
+		if err := s.checkBudgetLimit(event, budget, account); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[BudgetService]  Budget check completed")
+	return nil
+}
+
+// checkBudgetLimit публикует превышение или предупреждение по одному бюджету за его период
+// лимит - доступная сумма с учетом переноса из прошлых периодов и распределенного в конверт
+func (s *BudgetService) checkBudgetLimit(event events.TransactionCreatedEvent, budget *models.Budget, account *models.Account) error {
+	status, err := s.getBudgetStatus(budget, account)
 	if err != nil {
-		return fmt.Errorf("get spent amount: %w", err)
+		return fmt.Errorf("get budget status: %w", err)
 	}
-	log.Printf("[BudgetService] Budget check: Spent=%.2f / Limit=%.2f (%.0f%%)",
-		spentAmount, budget.Amount, (spentAmount/budget.Amount)*100)
-	if spentAmount > budget.Amount {
-		excessAmount := spentAmount - budget.Amount
-		log.Printf("[BudgetService] ⚠ Budget EXCEEDED by %.2f", excessAmount)
+	spentAmount := status.Spent
+	limit := status.Available
+	log.Printf("[BudgetService] Budget check (%s): Spent=%s / Limit=%s (%.0f%%)",
+		budget.Period, spentAmount, limit, spentAmount.Percent(limit))
+	if spentAmount.Cmp(limit) > 0 {
+		excessAmount := spentAmount.Sub(limit)
+		log.Printf("[BudgetService] ⚠ Budget EXCEEDED by %s", excessAmount)
 
 		// Публикуем событие превышения бюджета
 		if s.publisher != nil {
@@ -78,7 +128,7 @@ func (s *BudgetService) CheckBudgetAfterTransaction(event events.TransactionCrea
 					UserID:       event.UserID,
 					BudgetID:     budget.ID,
 					BudgetName:   budget.BudgetLimitName,
-					BudgetAmount: budget.Amount,
+					BudgetAmount: limit,
 					SpentAmount:  spentAmount,
 					ExcessAmount: excessAmount,
 					CategoryID:   event.CategoryID,
@@ -93,9 +143,8 @@ func (s *BudgetService) CheckBudgetAfterTransaction(event events.TransactionCrea
 		}
 		return nil
 	}
-	warningThreshold := budget.Amount * 0.80
-	if spentAmount >= warningThreshold {
-		percentUsed := (spentAmount / budget.Amount) * 100
+	percentUsed := spentAmount.Percent(limit)
+	if percentUsed >= budgetWarningPercent {
 		log.Printf("[BudgetService] Budget WARNING: %.0f%% used", percentUsed)
 
 		// Публикуем предупреждение
@@ -107,7 +156,7 @@ func (s *BudgetService) CheckBudgetAfterTransaction(event events.TransactionCrea
 					UserID:         event.UserID,
 					BudgetID:       budget.ID,
 					BudgetName:     budget.BudgetLimitName,
-					BudgetAmount:   budget.Amount,
+					BudgetAmount:   limit,
 					SpentAmount:    spentAmount,
 					WarningPercent: percentUsed,
 					CategoryID:     event.CategoryID,
@@ -121,39 +170,66 @@ func (s *BudgetService) CheckBudgetAfterTransaction(event events.TransactionCrea
 			}
 		}
 	}
-
-	log.Printf("[BudgetService]  Budget check completed")
 	return nil
 }
 
 func (s *BudgetService) CreateBudget(userID string, req *models.CreateBudgetRequest) (*models.Budget, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = "limit"
+	}
+	// у конверта amount - ориентир, доступно столько, сколько в него распределено
+	if mode == "envelope" && req.Amount.IsNegative() {
+		return nil, fmt.Errorf("envelope amount must not be negative")
+	}
+	if mode == "limit" && !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("budget amount must be positive")
+	}
 	account, err := s.accountRepo.GetByUserID(userID)
 	if err != nil {
 		return nil, fmt.Errorf("get account: %w", err)
 	}
 
-	category, err := s.categoryRepo.GetByID(req.CategoryID)
+	categoryID, categoryIDs, err := s.budgetCategories(account, req)
 	if err != nil {
-		return nil, fmt.Errorf("get category: %w", err)
+		return nil, err
 	}
-	if category.AccountID != account.ID {
-		return nil, fmt.Errorf("category does not belong to user")
-	}
-
-	startDate := time.Date(req.Year, time.Month(req.Month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, -1) // последний день месяца
 
-	existingBudget, err := s.budgetRepo.GetBudgetByCategoryAndMonth(req.CategoryID, req.Year, req.Month)
-	if err == nil && existingBudget != nil {
-		return nil, fmt.Errorf("budget already exists for category %d in %d-%02d", req.CategoryID, req.Year, req.Month)
+	period := req.Period
+	if period == "" {
+		period = "monthly"
+	}
+	referenceDate, err := budgetReferenceDate(period, req)
+	if err != nil {
+		return nil, err
+	}
+	// период бюджета - календарная неделя, месяц или год в таймзоне аккаунта
+	startDate, periodEnd, err := budgetPeriodRange(period, referenceDate, account)
+	if err != nil {
+		return nil, err
+	}
+	endDate := periodEnd.AddDate(0, 0, -1) // последний день периода
+
+	// бюджет одной категории и бюджет по всем категориям - не больше одного на период,
+	// бюджеты нескольких категорий могут пересекаться
+	if categoryID != nil || req.AllCategories {
+		existingBudget, err := s.budgetRepo.GetOverlappingBudget(account.ID, categoryID, period, startDate, endDate)
+		if err == nil && existingBudget != nil {
+			return nil, fmt.Errorf("%s budget %q already exists from %s to %s", period, existingBudget.BudgetLimitName,
+				existingBudget.StartDate.Format("2006-01-02"), existingBudget.EndDate.Format("2006-01-02"))
+		}
 	}
 
 	budget := &models.Budget{
 		AccountID:       account.ID,
 		BudgetLimitName: req.BudgetName,
-		CategoryID:      req.CategoryID,
+		CategoryID:      categoryID,
+		CategoryIDs:     categoryIDs,
+		AllCategories:   req.AllCategories,
 		Amount:          req.Amount,
-		Period:          "monthly",
+		Period:          period,
+		Mode:            mode,
+		Rollover:        req.Rollover,
 		StartDate:       startDate,
 		EndDate:         endDate,
 		IsActive:        true,
@@ -169,24 +245,306 @@ func (s *BudgetService) CreateBudget(userID string, req *models.CreateBudgetRequ
 	return createdBudget, nil
 }
 
-func (s *BudgetService) GetBudgets(userID string, year, month int) ([]*models.BudgetWithStatus, error) {
+// budgetCategories проверяет категории нового бюджета: category_id, category_ids или all_categories
+// возвращает категорию бюджета одной категории (nil для остальных) и категории по возрастанию без повторов
+func (s *BudgetService) budgetCategories(account *models.Account, req *models.CreateBudgetRequest) (*int64, []int64, error) {
+	requested := append([]int64{}, req.CategoryIDs...)
+	if req.CategoryID != nil {
+		requested = append(requested, *req.CategoryID)
+	}
+	if req.AllCategories {
+		if len(requested) > 0 {
+			return nil, nil, fmt.Errorf("all_categories cannot be combined with category_id or category_ids")
+		}
+		return nil, make([]int64, 0), nil
+	}
+	if len(requested) == 0 {
+		return nil, nil, fmt.Errorf("category_id, category_ids or all_categories is required")
+	}
+	sort.Slice(requested, func(i, j int) bool { return requested[i] < requested[j] })
+	categoryIDs := make([]int64, 0, len(requested))
+	for _, categoryID := range requested {
+		if len(categoryIDs) > 0 && categoryIDs[len(categoryIDs)-1] == categoryID {
+			continue
+		}
+		category, err := s.categoryRepo.GetByID(categoryID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("get category: %w", err)
+		}
+		if category.AccountID != account.ID {
+			return nil, nil, fmt.Errorf("category %d does not belong to user", categoryID)
+		}
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	if len(categoryIDs) == 1 {
+		categoryID := categoryIDs[0]
+		return &categoryID, categoryIDs, nil
+	}
+	return nil, categoryIDs, nil
+}
+
+// budgetScope - набор категорий бюджета строкой: "all" или ID категорий через запятую
+// бюджеты с одинаковым набором и периодичностью образуют цепочку переноса остатка
+func budgetScope(budget *models.Budget) string {
+	if budget.AllCategories {
+		return "all"
+	}
+	ids := make([]string, 0, len(budget.CategoryIDs))
+	for _, categoryID := range budget.CategoryIDs {
+		ids = append(ids, strconv.FormatInt(categoryID, 10))
+	}
+	return strings.Join(ids, ",")
+}
+
+// categoriesAmount - сумма byCategory по категориям бюджета; у бюджета по всем категориям - по всем, кроме "без категории" (0)
+func categoriesAmount(budget *models.Budget, byCategory map[int64]models.Money) models.Money {
+	var total models.Money
+	if budget.AllCategories {
+		for categoryID, amount := range byCategory {
+			if categoryID != 0 {
+				total = total.Add(amount)
+			}
+		}
+		return total
+	}
+	for _, categoryID := range budget.CategoryIDs {
+		total = total.Add(byCategory[categoryID])
+	}
+	return total
+}
+
+// budgetReferenceDate - календарный день, по которому выбирается период нового бюджета:
+// date из запроса, иначе первый день month/year (для месячного) или year (для годового)
+func budgetReferenceDate(period string, req *models.CreateBudgetRequest) (time.Time, error) {
+	if req.Date != "" {
+		date, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", req.Date)
+		}
+		return date, nil
+	}
+	switch period {
+	case "monthly":
+		if req.Year == 0 || req.Month == 0 {
+			return time.Time{}, fmt.Errorf("year and month or date are required for a monthly budget")
+		}
+		return time.Date(req.Year, time.Month(req.Month), 1, 0, 0, 0, 0, time.UTC), nil
+	case "yearly":
+		if req.Year == 0 {
+			return time.Time{}, fmt.Errorf("year or date is required for a yearly budget")
+		}
+		return time.Date(req.Year, time.January, 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, fmt.Errorf("date is required for a %s budget", period)
+}
+
+// budgetPeriodRange - границы периода [start, end), в который попадает календарная дата date, в таймзоне аккаунта
+// неделя начинается с первого дня недели аккаунта
+func budgetPeriodRange(period string, date time.Time, account *models.Account) (time.Time, time.Time, error) {
+	loc := utils.LoadLocation(account.Timezone)
+	switch period {
+	case "weekly":
+		start, end := utils.WeekRange(date, time.Weekday(account.WeekStartDay), loc)
+		return start, end, nil
+	case "monthly":
+		start, end := utils.MonthRange(date.Year(), int(date.Month()), loc)
+		return start, end, nil
+	case "yearly":
+		start, end := utils.YearRange(date.Year(), loc)
+		return start, end, nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid budget period %q", period)
+}
+
+// budgetRange - границы периода бюджета [start, end) по его датам в таймзоне аккаунта
+func budgetRange(budget *models.Budget, account *models.Account) (time.Time, time.Time) {
+	return utils.DateRange(budget.StartDate, budget.EndDate, utils.LoadLocation(account.Timezone))
+}
+
+// budgetDate - календарный день в таймзоне аккаунта, за который смотрятся бюджеты, нулевая date - сегодня
+func budgetDate(date time.Time, account *models.Account) time.Time {
+	if date.IsZero() {
+		return time.Now().In(utils.LoadLocation(account.Timezone))
+	}
+	return date
+}
 
+// UpdateBudget меняет название, сумму и перенос остатка бюджета
+// период и режим не меняются: для другого периода бюджет создается заново
+func (s *BudgetService) UpdateBudget(userID string, budgetID int64, req *models.UpdateBudgetRequest) (*models.Budget, error) {
 	account, err := s.accountRepo.GetByUserID(userID)
 	if err != nil {
 		return nil, fmt.Errorf("get account: %w", err)
 	}
+	budget, err := s.getOwnedBudget(account, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if budget.Mode == "envelope" && req.Amount.IsNegative() {
+		return nil, fmt.Errorf("envelope amount must not be negative")
+	}
+	if budget.Mode != "envelope" && !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("budget amount must be positive")
+	}
+	budget.BudgetLimitName = req.BudgetName
+	budget.Amount = req.Amount
+	if req.Rollover != nil {
+		budget.Rollover = *req.Rollover
+	}
+	budget.UpdatedAt = time.Now()
+	updatedBudget, err := s.budgetRepo.UpdateBudget(budget)
+	if err != nil {
+		return nil, fmt.Errorf("update budget: %w", err)
+	}
+	return updatedBudget, nil
+}
+
+// DeleteBudget удаляет бюджет; распределенное в конверт возвращается в "к распределению"
+func (s *BudgetService) DeleteBudget(userID string, budgetID int64) error {
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("get account: %w", err)
+	}
+	if _, err := s.getOwnedBudget(account, budgetID); err != nil {
+		return err
+	}
+	return s.budgetRepo.DeleteBudget(budgetID)
+}
+
+// CopyBudgets копирует месячные бюджеты месяца from в месяц to одной транзакцией БД,
+// суммы меняются на AdjustPercent и округляются до точности валюты;
+// бюджеты, для набора категорий которых в целевом месяце уже есть месячный бюджет, пропускаются
+// распределения конвертов не копируются
+func (s *BudgetService) CopyBudgets(userID string, req *models.CopyBudgetsRequest) (*models.CopyBudgetsResult, error) {
+	if req.FromYear == req.ToYear && req.FromMonth == req.ToMonth {
+		return nil, fmt.Errorf("source and target months must differ")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	currency := baseCurrency(account)
+	fromDate := time.Date(req.FromYear, time.Month(req.FromMonth), 1, 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(req.ToYear, time.Month(req.ToMonth), 1, 0, 0, 0, 0, time.UTC)
+	startDate, monthEnd, err := budgetPeriodRange("monthly", toDate, account)
+	if err != nil {
+		return nil, err
+	}
+	endDate := monthEnd.AddDate(0, 0, -1)
 
-	budgets, err := s.budgetRepo.GetBudgetsByAccountAndMonth(account.ID, year, month)
+	sources, err := s.budgetRepo.GetBudgetsByAccountAndDate(account.ID, fromDate)
 	if err != nil {
 		return nil, fmt.Errorf("get budgets: %w", err)
 	}
+	targets, err := s.budgetRepo.GetBudgetsByAccountAndDate(account.ID, toDate)
+	if err != nil {
+		return nil, fmt.Errorf("get budgets: %w", err)
+	}
+	existingScopes := make(map[string]bool)
+	for _, target := range targets {
+		if target.Period == "monthly" {
+			existingScopes[budgetScope(target)] = true
+		}
+	}
+	result := &models.CopyBudgetsResult{
+		Created: make([]*models.Budget, 0, len(sources)),
+		Skipped: make([]*models.Budget, 0),
+	}
+	now := time.Now()
+	for _, source := range sources {
+		if source.Period != "monthly" {
+			continue
+		}
+		if existingScopes[budgetScope(source)] {
+			result.Skipped = append(result.Skipped, source)
+			continue
+		}
+		amount, err := source.Amount.Convert(1+req.AdjustPercent/100, currency)
+		if err != nil {
+			return nil, fmt.Errorf("adjust amount: %w", err)
+		}
+		if source.Mode != "envelope" && !amount.IsPositive() {
+			return nil, fmt.Errorf("adjusted amount of budget %d must be positive", source.ID)
+		}
+		result.Created = append(result.Created, &models.Budget{
+			AccountID:       account.ID,
+			BudgetLimitName: source.BudgetLimitName,
+			CategoryID:      source.CategoryID,
+			CategoryIDs:     source.CategoryIDs,
+			AllCategories:   source.AllCategories,
+			Amount:          amount,
+			Period:          "monthly",
+			Mode:            source.Mode,
+			Rollover:        source.Rollover,
+			StartDate:       startDate,
+			EndDate:         endDate,
+			IsActive:        true,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		})
+	}
+	if len(result.Created) == 0 {
+		return result, nil
+	}
+	if err := s.budgetRepo.CreateBudgets(result.Created); err != nil {
+		return nil, fmt.Errorf("copy budgets: %w", err)
+	}
+	return result, nil
+}
 
-	var budgetsWithStatus []*models.BudgetWithStatus
+// GetBudgets - бюджеты, в период которых попадает date (нулевая - сегодня), period фильтрует по периоду, пустой - все
+func (s *BudgetService) GetBudgets(userID string, period string, date time.Time) ([]*models.BudgetWithStatus, error) {
+
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+
+	budgets, err := s.budgetRepo.GetBudgetsByAccountAndDate(account.ID, budgetDate(date, account))
+	if err != nil {
+		return nil, fmt.Errorf("get budgets: %w", err)
+	}
+
+	filtered := make([]*models.Budget, 0, len(budgets))
 	for _, budget := range budgets {
-		status, err := s.getBudgetStatus(budget, year, month)
-		if err != nil {
-			return nil, fmt.Errorf("get budget status: %w", err)
+		if period == "" || budget.Period == period {
+			filtered = append(filtered, budget)
 		}
+	}
+	assigned, err := s.assignedAmounts(filtered, account)
+	if err != nil {
+		return nil, fmt.Errorf("get assigned amounts: %w", err)
+	}
+	carriedIn, err := s.carriedIn(filtered, account)
+	if err != nil {
+		return nil, fmt.Errorf("get carried amounts: %w", err)
+	}
+	// траты всех бюджетов за их периоды - одним запросом, а не по запросу на бюджет
+	spentByBudget, err := s.spentByBudgets(filtered, account)
+	if err != nil {
+		return nil, fmt.Errorf("get spent amount: %w", err)
+	}
+	// платежи по рассрочкам - одним запросом на каждый период (неделя, месяц, год)
+	installmentsByPeriod := make(map[string]map[int64]models.Money)
+
+	var budgetsWithStatus []*models.BudgetWithStatus
+	for _, budget := range filtered {
+		key := budget.StartDate.Format("2006-01-02") + "/" + budget.EndDate.Format("2006-01-02")
+		installmentsByCategory, ok := installmentsByPeriod[key]
+		if !ok {
+			start, end := budgetRange(budget, account)
+			if installmentsByCategory, err = s.installmentsByCategory(account, start, end); err != nil {
+				return nil, fmt.Errorf("get installment payments: %w", err)
+			}
+			installmentsByPeriod[key] = installmentsByCategory
+		}
+
+		spent, ok := spentByBudget[budget.ID]
+		if !ok {
+			spent = models.NewMoney(0, baseCurrency(account))
+		}
+		installments := categoriesAmount(budget, installmentsByCategory).WithCurrency(spent.Currency)
+		status := newBudgetStatus(budget, assigned[budget.ID], carriedIn[budget.ID], spent, installments)
 
 		budgetWithStatus := &models.BudgetWithStatus{
 			Budget: budget,
@@ -198,22 +556,34 @@ func (s *BudgetService) GetBudgets(userID string, year, month int) ([]*models.Bu
 	return budgetsWithStatus, nil
 }
 
-func (s *BudgetService) GetBudgetStatus(userID string, categoryID int64, year, month int) (*models.BudgetStatus, error) {
+// GetBudgetStatus - статус бюджета категории на date (нулевая - сегодня)
+// если у категории несколько бюджетов, а period не задан, берется бюджет с самым коротким периодом
+func (s *BudgetService) GetBudgetStatus(userID string, categoryID int64, period string, date time.Time) (*models.BudgetStatus, error) {
 	account, err := s.accountRepo.GetByUserID(userID)
 	if err != nil {
 		return nil, fmt.Errorf("get account: %w", err)
 	}
 
-	budget, err := s.budgetRepo.GetBudgetByCategoryAndMonth(categoryID, year, month)
+	budgets, err := s.budgetRepo.GetBudgetsByCategoryAndDate(categoryID, budgetDate(date, account))
 	if err != nil {
 		return nil, fmt.Errorf("get budget: %w", err)
 	}
+	var budget *models.Budget
+	for _, candidate := range budgets {
+		if period == "" || candidate.Period == period {
+			budget = candidate
+			break
+		}
+	}
+	if budget == nil {
+		return nil, fmt.Errorf("get budget: no budget found for category %d", categoryID)
+	}
 
 	if budget.AccountID != account.ID {
 		return nil, fmt.Errorf("budget does not belong to user")
 	}
 
-	status, err := s.getBudgetStatus(budget, year, month)
+	status, err := s.getBudgetStatus(budget, account)
 	if err != nil {
 		return nil, fmt.Errorf("get budget status: %w", err)
 	}
@@ -221,55 +591,419 @@ func (s *BudgetService) GetBudgetStatus(userID string, categoryID int64, year, m
 	return status, nil
 }
 
-func (s *BudgetService) getBudgetStatus(budget *models.Budget, year, month int) (*models.BudgetStatus, error) {
-	spentAmount, err := s.transactionRepo.GetSpentAmountByCategoryAndMonth(budget.CategoryID, year, month)
+// spentAmount - траты по категориям бюджета за его период в валюте бюджета, как в spentByBudgets
+func (s *BudgetService) spentAmount(account *models.Account, budget *models.Budget) (models.Money, error) {
+	spentByBudget, err := s.spentByBudgets([]*models.Budget{budget}, account)
+	if err != nil {
+		return models.Money{}, err
+	}
+	spent, ok := spentByBudget[budget.ID]
+	if !ok {
+		spent = models.NewMoney(0, baseCurrency(account))
+	}
+	return spent, nil
+}
+
+// installmentsByCategory - еще не наступившие платежи по рассрочкам за период [periodStart, periodEnd)
+// по категориям в базовой валюте, по курсу как в spentAmount
+func (s *BudgetService) installmentsByCategory(account *models.Account, periodStart, periodEnd time.Time) (map[int64]models.Money, error) {
+	obligations, err := s.installmentRepo.GetOutstandingByCategory(account.ID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+	currency := baseCurrency(account)
+	rateDate := conversionDate(periodEnd)
+	installments := make(map[int64]models.Money)
+	for _, item := range obligations {
+		converted, _, err := s.rateService.Convert(item.Amount, item.Currency, currency, rateDate)
+		if err != nil {
+			return nil, err
+		}
+		installments[item.CategoryID] = installments[item.CategoryID].Add(converted)
+	}
+	return installments, nil
+}
+
+func (s *BudgetService) getBudgetStatus(budget *models.Budget, account *models.Account) (*models.BudgetStatus, error) {
+	spentAmount, err := s.spentAmount(account, budget)
 	if err != nil {
 		return nil, fmt.Errorf("get spent amount: %w", err)
 	}
+	periodStart, periodEnd := budgetRange(budget, account)
+	installmentsByCategory, err := s.installmentsByCategory(account, periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("get installment payments: %w", err)
+	}
+	installments := categoriesAmount(budget, installmentsByCategory).WithCurrency(spentAmount.Currency)
+	budgets := []*models.Budget{budget}
+	assigned, err := s.assignedAmounts(budgets, account)
+	if err != nil {
+		return nil, fmt.Errorf("get assigned amounts: %w", err)
+	}
+	carriedIn, err := s.carriedIn(budgets, account)
+	if err != nil {
+		return nil, fmt.Errorf("get carried amounts: %w", err)
+	}
+	return newBudgetStatus(budget, assigned[budget.ID], carriedIn[budget.ID], spentAmount, installments), nil
+}
+
+// assignedAmounts - деньги бюджетов на их период в базовой валюте: лимит amount или сумма распределений в конверт
+func (s *BudgetService) assignedAmounts(budgets []*models.Budget, account *models.Account) (map[int64]models.Money, error) {
+	currency := baseCurrency(account)
+	assigned := make(map[int64]models.Money, len(budgets))
+	var envelopeIDs []int64
+	for _, budget := range budgets {
+		if budget.Mode == "envelope" {
+			envelopeIDs = append(envelopeIDs, budget.ID)
+			assigned[budget.ID] = models.NewMoney(0, currency)
+			continue
+		}
+		assigned[budget.ID] = budget.Amount.WithCurrency(currency)
+	}
+	if len(envelopeIDs) == 0 {
+		return assigned, nil
+	}
+	amounts, err := s.budgetRepo.GetAssignedByBudgets(envelopeIDs)
+	if err != nil {
+		return nil, err
+	}
+	for budgetID, amount := range amounts {
+		assigned[budgetID] = amount.WithCurrency(currency)
+	}
+	return assigned, nil
+}
+
+// carriedIn - остаток (или перерасход), перенесенный в каждый бюджет из непрерывной цепочки предыдущих периодов
+// с тем же набором категорий и периодичностью; цепочка обрывается на бюджете без rollover или на пропущенном периоде
+// все цепочки считаются тремя запросами: предыдущие бюджеты, их распределения и траты
+func (s *BudgetService) carriedIn(budgets []*models.Budget, account *models.Account) (map[int64]models.Money, error) {
+	currency := baseCurrency(account)
+	carried := make(map[int64]models.Money, len(budgets))
+	if len(budgets) == 0 {
+		return carried, nil
+	}
+	before := budgets[0].StartDate
+	for _, budget := range budgets {
+		carried[budget.ID] = models.NewMoney(0, currency)
+		if budget.StartDate.After(before) {
+			before = budget.StartDate
+		}
+	}
+	previous, err := s.budgetRepo.GetPreviousBudgets(account.ID, before)
+	if err != nil {
+		return nil, err
+	}
+
+	chains := make(map[int64][]*models.Budget)
+	var chained []*models.Budget
+	for _, budget := range budgets {
+		next := budget.StartDate.Format("2006-01-02")
+		// previous - от последнего к первому, бюджеты одной категории и периодичности не пересекаются
+		for _, candidate := range previous {
+			if budgetScope(candidate) != budgetScope(budget) || candidate.Period != budget.Period ||
+				candidate.EndDate.Format("2006-01-02") >= next {
+				continue
+			}
+			if !candidate.Rollover || candidate.EndDate.AddDate(0, 0, 1).Format("2006-01-02") != next {
+				break
+			}
+			chains[budget.ID] = append(chains[budget.ID], candidate)
+			chained = append(chained, candidate)
+			next = candidate.StartDate.Format("2006-01-02")
+		}
+	}
+	if len(chained) == 0 {
+		return carried, nil
+	}
+
+	assigned, err := s.assignedAmounts(chained, account)
+	if err != nil {
+		return nil, err
+	}
+	spent, err := s.spentByBudgets(chained, account)
+	if err != nil {
+		return nil, err
+	}
+	for _, budget := range budgets {
+		chain := chains[budget.ID]
+		amount := models.NewMoney(0, currency)
+		for i := len(chain) - 1; i >= 0; i-- {
+			amount = assigned[chain[i].ID].Add(amount).Sub(spent[chain[i].ID])
+		}
+		carried[budget.ID] = amount
+	}
+	return carried, nil
+}
+
+// spentByBudgets - траты бюджетов за их собственные периоды в базовой валюте, по курсу на конец каждого периода
+// (на сегодня для текущего), бюджеты без трат не возвращаются
+func (s *BudgetService) spentByBudgets(budgets []*models.Budget, account *models.Account) (map[int64]models.Money, error) {
+	byID := make(map[int64]*models.Budget, len(budgets))
+	budgetIDs := make([]int64, 0, len(budgets))
+	for _, budget := range budgets {
+		byID[budget.ID] = budget
+		budgetIDs = append(budgetIDs, budget.ID)
+	}
+	spending, err := s.budgetRepo.GetSpentByBudgets(budgetIDs)
+	if err != nil {
+		return nil, err
+	}
+	currency := baseCurrency(account)
+	spent := make(map[int64]models.Money)
+	for _, item := range spending {
+		_, periodEnd := budgetRange(byID[item.BudgetID], account)
+		converted, _, err := s.rateService.Convert(item.Amount, item.Currency, currency, conversionDate(periodEnd))
+		if err != nil {
+			return nil, err
+		}
+		spent[item.BudgetID] = spent[item.BudgetID].Add(converted)
+	}
+	return spent, nil
+}
 
-	remainingAmount := budget.Amount - spentAmount
+// newBudgetStatus считает остаток и прогресс бюджета по сумме трат от доступной суммы
+// (лимит или распределенное в конверт плюс перенос из прошлых периодов),
+// прогноз - с учетом еще не наступивших платежей по рассрочкам
+func newBudgetStatus(budget *models.Budget, assigned, carriedIn, spentAmount, installments models.Money) *models.BudgetStatus {
+	available := assigned.Add(carriedIn)
+	remainingAmount := available.Sub(spentAmount)
 
 	var progress float64
-	if budget.Amount > 0 {
-		progress = (spentAmount / budget.Amount) * 100
+	if available.IsPositive() {
+		progress = spentAmount.Percent(available)
 	}
 
-	isExceeded := spentAmount > budget.Amount
+	isExceeded := spentAmount.Cmp(available) > 0
+
+	projected := spentAmount.Add(installments)
 
 	status := &models.BudgetStatus{
-		Budget:     budget,
-		Spent:      spentAmount,
-		Remaining:  remainingAmount,
-		Progress:   progress,
-		IsExceeded: isExceeded,
+		Budget:              budget,
+		Spent:               spentAmount,
+		Remaining:           remainingAmount,
+		Progress:            progress,
+		IsExceeded:          isExceeded,
+		Installments:        installments,
+		Projected:           projected,
+		IsProjectedExceeded: projected.Cmp(available) > 0,
+		CarriedIn:           carriedIn,
+		Assigned:            assigned,
+		Available:           available,
 	}
 
-	return status, nil
+	return status
 }
 
-func (s *BudgetService) GetBudgetSummary(userID string, year, month int) (*models.BudgetSummary, error) {
-	budgets, err := s.GetBudgets(userID, year, month)
+// GetBudgetSummary - итог по бюджетам на date, как в GetBudgets; суммы разных периодов складываются как есть,
+// поэтому для сравнения плана и трат стоит передавать period
+func (s *BudgetService) GetBudgetSummary(userID string, period string, date time.Time) (*models.BudgetSummary, error) {
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	budgets, err := s.GetBudgets(userID, period, date)
 	if err != nil {
-		return nil, fmt.Errorf("get monthly budgets: %w", err)
+		return nil, fmt.Errorf("get budgets: %w", err)
 	}
 
-	var totalPlanned float64
-	var totalSpent float64
-	var totalRemaining float64
+	var totalPlanned models.Money
+	var totalSpent models.Money
+	var totalRemaining models.Money
+	var totalInstallments models.Money
+	var totalCarriedIn models.Money
+	var totalAvailable models.Money
 
 	for _, budgetWithStatus := range budgets {
-		totalPlanned += budgetWithStatus.Budget.Amount
-		totalSpent += budgetWithStatus.Status.Spent
-		totalRemaining += budgetWithStatus.Status.Remaining
+		totalPlanned = totalPlanned.Add(budgetWithStatus.Budget.Amount)
+		totalSpent = totalSpent.Add(budgetWithStatus.Status.Spent)
+		totalRemaining = totalRemaining.Add(budgetWithStatus.Status.Remaining)
+		totalInstallments = totalInstallments.Add(budgetWithStatus.Status.Installments)
+		totalCarriedIn = totalCarriedIn.Add(budgetWithStatus.Status.CarriedIn)
+		totalAvailable = totalAvailable.Add(budgetWithStatus.Status.Available)
+	}
+
+	toBeAssigned, err := s.toBeAssigned(account, budgetDate(date, account))
+	if err != nil {
+		return nil, fmt.Errorf("get amount to be assigned: %w", err)
 	}
 
 	summary := &models.BudgetSummary{
-		TotalPlanned:   totalPlanned,
-		TotalSpent:     totalSpent,
-		TotalRemaining: totalRemaining,
-		IsOverBudget:   totalSpent > totalPlanned,
-		Budgets:        budgets,
+		TotalPlanned:      totalPlanned,
+		TotalSpent:        totalSpent,
+		TotalRemaining:    totalRemaining,
+		TotalInstallments: totalInstallments,
+		TotalCarriedIn:    totalCarriedIn,
+		TotalAvailable:    totalAvailable,
+		ToBeAssigned:      toBeAssigned,
+		IsOverBudget:      totalSpent.Cmp(totalAvailable) > 0,
+		Budgets:           budgets,
 	}
 
 	return summary, nil
 }
+
+// toBeAssigned - доход с начала первого конверта по день date включительно минус все распределения по конвертам
+// доход в других валютах пересчитывается по курсу на date; без конвертов - ноль
+func (s *BudgetService) toBeAssigned(account *models.Account, date time.Time) (models.Money, error) {
+	currency := baseCurrency(account)
+	firstDate, assigned, err := s.budgetRepo.GetEnvelopeTotals(account.ID)
+	if err != nil {
+		return models.Money{}, err
+	}
+	if firstDate == nil {
+		return models.NewMoney(0, currency), nil
+	}
+	loc := utils.LoadLocation(account.Timezone)
+	start, _ := utils.DateRange(*firstDate, *firstDate, loc)
+	_, end := utils.DateRange(date, date, loc)
+	reports, err := s.transactionRepo.GetIncomeExpenseByCurrency(account.ID, start, end)
+	if err != nil {
+		return models.Money{}, err
+	}
+	rateDate := conversionDate(end)
+	income := models.NewMoney(0, currency)
+	for _, report := range reports {
+		converted, _, err := s.rateService.Convert(report.TotalIncome, report.Currency, currency, rateDate)
+		if err != nil {
+			return models.Money{}, err
+		}
+		income = income.Add(converted)
+	}
+	return income.Sub(assigned.WithCurrency(currency)), nil
+}
+
+// getOwnedBudget - бюджет аккаунта
+func (s *BudgetService) getOwnedBudget(account *models.Account, budgetID int64) (*models.Budget, error) {
+	if budgetID <= 0 {
+		return nil, fmt.Errorf("invalid budget id")
+	}
+	budget, err := s.budgetRepo.GetBudget(budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if budget.AccountID != account.ID {
+		return nil, fmt.Errorf("no budget found with id %d", budgetID)
+	}
+	return budget, nil
+}
+
+// getOwnedEnvelope - конверт (envelope-бюджет) аккаунта
+func (s *BudgetService) getOwnedEnvelope(account *models.Account, budgetID int64) (*models.Budget, error) {
+	budget, err := s.getOwnedBudget(account, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if !budget.IsActive {
+		return nil, fmt.Errorf("no budget found with id %d", budgetID)
+	}
+	if budget.Mode != "envelope" {
+		return nil, fmt.Errorf("budget %d is not an envelope", budgetID)
+	}
+	return budget, nil
+}
+
+// AssignBudget распределяет деньги в конверт; отрицательная сумма возвращает их в "к распределению",
+// но не больше доступного в конверте
+func (s *BudgetService) AssignBudget(userID string, req *models.AssignBudgetRequest) (*models.BudgetStatus, error) {
+	if req.Amount.IsZero() {
+		return nil, fmt.Errorf("amount must not be zero")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	budget, err := s.getOwnedEnvelope(account, req.BudgetID)
+	if err != nil {
+		return nil, err
+	}
+	amount := req.Amount.WithCurrency(baseCurrency(account))
+	if amount.IsNegative() {
+		status, err := s.getBudgetStatus(budget, account)
+		if err != nil {
+			return nil, fmt.Errorf("get budget status: %w", err)
+		}
+		if status.Remaining.Add(amount).IsNegative() {
+			return nil, fmt.Errorf("cannot take %s from envelope, only %s is left", amount.Neg(), status.Remaining)
+		}
+	}
+
+	assignment := &models.BudgetAssignment{
+		AccountID: account.ID,
+		BudgetID:  budget.ID,
+		Amount:    amount,
+		CreatedAt: time.Now(),
+	}
+	if req.Description != "" {
+		assignment.Description = &req.Description
+	}
+	if err := s.budgetRepo.CreateAssignments([]*models.BudgetAssignment{assignment}); err != nil {
+		return nil, fmt.Errorf("assign budget: %w", err)
+	}
+	return s.getBudgetStatus(budget, account)
+}
+
+// MoveBudget перемещает деньги между конвертами, из конверта нельзя взять больше, чем в нем осталось
+func (s *BudgetService) MoveBudget(userID string, req *models.MoveBudgetRequest) ([]*models.BudgetStatus, error) {
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if req.FromBudgetID == req.ToBudgetID {
+		return nil, fmt.Errorf("cannot move money to the same envelope")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	from, err := s.getOwnedEnvelope(account, req.FromBudgetID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.getOwnedEnvelope(account, req.ToBudgetID)
+	if err != nil {
+		return nil, err
+	}
+	amount := req.Amount.WithCurrency(baseCurrency(account))
+	fromStatus, err := s.getBudgetStatus(from, account)
+	if err != nil {
+		return nil, fmt.Errorf("get budget status: %w", err)
+	}
+	if fromStatus.Remaining.Cmp(amount) < 0 {
+		return nil, fmt.Errorf("cannot move %s, only %s is left in envelope %d", amount, fromStatus.Remaining, from.ID)
+	}
+
+	var description *string
+	if req.Description != "" {
+		description = &req.Description
+	}
+	now := time.Now()
+	assignments := []*models.BudgetAssignment{
+		{AccountID: account.ID, BudgetID: from.ID, RelatedBudgetID: &to.ID, Amount: amount.Neg(), Description: description, CreatedAt: now},
+		{AccountID: account.ID, BudgetID: to.ID, RelatedBudgetID: &from.ID, Amount: amount, Description: description, CreatedAt: now},
+	}
+	if err := s.budgetRepo.CreateAssignments(assignments); err != nil {
+		return nil, fmt.Errorf("move budget: %w", err)
+	}
+
+	statuses := make([]*models.BudgetStatus, 0, 2)
+	for _, budget := range []*models.Budget{from, to} {
+		status, err := s.getBudgetStatus(budget, account)
+		if err != nil {
+			return nil, fmt.Errorf("get budget status: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// GetAssignments - история распределений конверта
+func (s *BudgetService) GetAssignments(userID string, budgetID int64) ([]*models.BudgetAssignment, error) {
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	budget, err := s.getOwnedEnvelope(account, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	return s.budgetRepo.GetAssignments(budget.ID)
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"justTest/internal/interfaces"
+	"justTest/internal/models"
+	"justTest/internal/models/events"
+	"justTest/internal/utils"
+	"log"
+	"time"
+)
+
+// creditReminderDays - за сколько дней до срока платежа приходит напоминание
+const creditReminderDays = 3
+
+// defaultMinPaymentPercent - минимальный платеж по умолчанию, % от задолженности по выписке
+const defaultMinPaymentPercent = 5
+
+type CreditService struct {
+	creditRepo          interfaces.CreditTermsRepository
+	bankAccountRepo     interfaces.BankAccountRepository
+	accountRepo         interfaces.AccountRepository
+	transactionRepo     interfaces.TransactionRepository
+	balanceRepo         interfaces.BalanceRepository
+	notificationService *NotificationService
+}
+
+func NewCreditService(
+	creditRepo interfaces.CreditTermsRepository,
+	bankAccountRepo interfaces.BankAccountRepository,
+	accountRepo interfaces.AccountRepository,
+	transactionRepo interfaces.TransactionRepository,
+	balanceRepo interfaces.BalanceRepository,
+	notificationService *NotificationService,
+) *CreditService {
+	return &CreditService{
+		creditRepo:          creditRepo,
+		bankAccountRepo:     bankAccountRepo,
+		accountRepo:         accountRepo,
+		transactionRepo:     transactionRepo,
+		balanceRepo:         balanceRepo,
+		notificationService: notificationService,
+	}
+}
+
+func (s *CreditService) getOwnedBankAccount(userID string, bankAccountID int64) (*models.BankAccount, *models.Account, error) {
+	bankAccount, err := s.bankAccountRepo.GetByBankAccountID(bankAccountID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bank account not found: %w", err)
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("user not found: %w", err)
+	}
+	if bankAccount.AccountID != account.ID {
+		return nil, nil, fmt.Errorf("user is not owned by the bank account")
+	}
+	return bankAccount, account, nil
+}
+
+// SetCreditTerms задает или меняет условия кредитного счета
+func (s *CreditService) SetCreditTerms(userID string, bankAccountID int64, req *models.CreditTermsRequest) (*models.CreditTerms, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if bankAccountID <= 0 {
+		return nil, fmt.Errorf("invalid bank account id")
+	}
+	if req.StatementDay < 1 || req.StatementDay > 31 || req.PaymentDueDay < 1 || req.PaymentDueDay > 31 {
+		return nil, fmt.Errorf("statement and payment due days must be between 1 and 31")
+	}
+	if req.InterestRate < 0 {
+		return nil, fmt.Errorf("invalid interest rate")
+	}
+	bankAccount, _, err := s.getOwnedBankAccount(userID, bankAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if bankAccount.AccountType != "credit" {
+		return nil, fmt.Errorf("bank account is not a credit account")
+	}
+	creditLimit := req.CreditLimit.WithCurrency(bankAccount.Currency)
+	if !creditLimit.IsPositive() {
+		return nil, fmt.Errorf("credit limit must be positive")
+	}
+	minPaymentPercent := float64(defaultMinPaymentPercent)
+	if req.MinPaymentPercent != nil {
+		if *req.MinPaymentPercent < 0 || *req.MinPaymentPercent > 100 {
+			return nil, fmt.Errorf("invalid minimum payment percent")
+		}
+		minPaymentPercent = *req.MinPaymentPercent
+	}
+	now := time.Now()
+	return s.creditRepo.Save(&models.CreditTerms{
+		BankAccountID:     bankAccountID,
+		CreditLimit:       creditLimit,
+		StatementDay:      req.StatementDay,
+		PaymentDueDay:     req.PaymentDueDay,
+		InterestRate:      req.InterestRate,
+		MinPaymentPercent: minPaymentPercent,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	})
+}
+
+// GetCreditStatus - доступный лимит, последняя закрытая выписка, минимальный платеж и срок платежа
+func (s *CreditService) GetCreditStatus(userID string, bankAccountID int64) (*models.CreditStatus, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if bankAccountID <= 0 {
+		return nil, fmt.Errorf("invalid bank account id")
+	}
+	bankAccount, account, err := s.getOwnedBankAccount(userID, bankAccountID)
+	if err != nil {
+		return nil, err
+	}
+	terms, err := s.creditRepo.GetByBankAccountID(bankAccountID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("credit terms are not set for bank account %d", bankAccountID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.creditStatus(bankAccount, account, terms, time.Now())
+}
+
+// AvailableBalance - сумма для проверки низкого баланса: для кредитного счета с условиями - доступный лимит,
+// для остальных - остаток счета
+func (s *CreditService) AvailableBalance(userID string, bankAccountID int64) (*models.BankAccount, models.Money, error) {
+	bankAccount, _, err := s.getOwnedBankAccount(userID, bankAccountID)
+	if err != nil {
+		return nil, models.Money{}, err
+	}
+	balance, err := s.transactionRepo.GetTotalAmountByBankAccountID(bankAccountID)
+	if err != nil {
+		return nil, models.Money{}, err
+	}
+	balance = balance.WithCurrency(bankAccount.Currency)
+	if bankAccount.AccountType != "credit" {
+		return bankAccount, balance, nil
+	}
+	terms, err := s.creditRepo.GetByBankAccountID(bankAccountID)
+	if err == sql.ErrNoRows {
+		return bankAccount, balance, nil
+	}
+	if err != nil {
+		return nil, models.Money{}, err
+	}
+	return bankAccount, terms.CreditLimit.WithCurrency(bankAccount.Currency).Add(balance), nil
+}
+
+// SendDueReminders напоминает через NotificationService о платеже по выписке не раньше чем за
+// creditReminderDays дней до срока, по одному разу на каждый срок; ошибки по отдельным счетам только логируются
+func (s *CreditService) SendDueReminders(now time.Time) error {
+	allTerms, err := s.creditRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, terms := range allTerms {
+		if err := s.sendDueReminder(terms, now); err != nil {
+			log.Printf("[CreditService] Error sending payment reminder for bank account %d: %v", terms.BankAccountID, err)
+		}
+	}
+	return nil
+}
+
+func (s *CreditService) sendDueReminder(terms *models.CreditTerms, now time.Time) error {
+	bankAccount, err := s.bankAccountRepo.GetByBankAccountID(terms.BankAccountID)
+	if err != nil {
+		return err
+	}
+	account, err := s.accountRepo.GetByID(bankAccount.AccountID)
+	if err != nil {
+		return err
+	}
+	status, err := s.creditStatus(bankAccount, account, terms, now)
+	if err != nil {
+		return err
+	}
+	if !status.RemainingStatement.IsPositive() {
+		return nil
+	}
+	if terms.LastReminderDate != nil && terms.LastReminderDate.Format("2006-01-02") == status.DueDate.Format("2006-01-02") {
+		return nil
+	}
+	local := now.In(status.DueDate.Location())
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	if today.AddDate(0, 0, creditReminderDays).Before(status.DueDate) {
+		return nil
+	}
+	err = s.notificationService.HandlePaymentDue(events.PaymentDueEvent{
+		UserID:           account.UserID,
+		BankAccountID:    bankAccount.ID,
+		AccountName:      bankAccount.Name,
+		StatementBalance: status.RemainingStatement,
+		MinimumPayment:   status.MinimumPayment,
+		DueDate:          status.DueDate,
+		Timestamp:        now,
+	})
+	if err != nil {
+		return err
+	}
+	return s.creditRepo.MarkReminderSent(bankAccount.ID, status.DueDate)
+}
+
+// creditStatus считает состояние счета на момент now, даты - в таймзоне аккаунта
+// выписка закрывается в конце дня StatementDay, задолженность по ней - долг на конец этого дня
+func (s *CreditService) creditStatus(bankAccount *models.BankAccount, account *models.Account, terms *models.CreditTerms, now time.Time) (*models.CreditStatus, error) {
+	currency := bankAccount.Currency
+	loc := utils.LoadLocation(account.Timezone)
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	balance, err := s.transactionRepo.GetTotalAmountByBankAccountID(bankAccount.ID)
+	if err != nil {
+		return nil, err
+	}
+	balance = balance.WithCurrency(currency)
+	status := &models.CreditStatus{
+		BankAccountID:   bankAccount.ID,
+		Currency:        currency,
+		Terms:           terms,
+		Balance:         balance,
+		AvailableCredit: terms.CreditLimit.WithCurrency(currency).Add(balance),
+		StatementEnd:    lastStatementClosing(today, terms.StatementDay),
+	}
+	previous := status.StatementEnd.AddDate(0, 0, -status.StatementEnd.Day()) // последний день прошлого месяца
+	status.StatementStart = monthDay(previous.Year(), previous.Month(), terms.StatementDay, loc).AddDate(0, 0, 1)
+	status.DueDate = monthDay(status.StatementEnd.Year(), status.StatementEnd.Month(), terms.PaymentDueDay, loc)
+	if !status.DueDate.After(status.StatementEnd) {
+		next := status.StatementEnd.AddDate(0, 0, 32-status.StatementEnd.Day()) // день следующего месяца
+		status.DueDate = monthDay(next.Year(), next.Month(), terms.PaymentDueDay, loc)
+	}
+
+	closing, err := s.balanceRepo.GetDailyBalances(bankAccount.ID, status.StatementEnd, status.StatementEnd)
+	if err != nil {
+		return nil, err
+	}
+	status.StatementBalance = models.NewMoney(0, currency)
+	if len(closing) > 0 {
+		status.StatementBalance = debtOf(closing[len(closing)-1].Balance.WithCurrency(currency))
+	}
+	paid, err := s.transactionRepo.GetInflowByBankAccountAndDateRange(bankAccount.ID, status.StatementEnd.AddDate(0, 0, 1), today.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	status.PaidSinceStatement = paid.WithCurrency(currency)
+	status.RemainingStatement = debtOf(status.PaidSinceStatement.Sub(status.StatementBalance))
+
+	minimum, err := status.StatementBalance.Convert(terms.MinPaymentPercent/100, currency)
+	if err != nil {
+		return nil, err
+	}
+	status.MinimumPayment = debtOf(status.PaidSinceStatement.Sub(minimum))
+	status.EstimatedInterest, err = status.RemainingStatement.Convert(terms.InterestRate/100/12, currency)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// debtOf - задолженность по остатку: -balance для отрицательного, 0 для остальных
+func debtOf(balance models.Money) models.Money {
+	if balance.IsNegative() {
+		return balance.Neg()
+	}
+	return models.NewMoney(0, balance.Currency)
+}
+
+// monthDay - день day месяца, но не позже последнего дня месяца (31 -> 30 апреля)
+func monthDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+	if last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day(); day > last {
+		day = last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// lastStatementClosing - день закрытия последней закрытой выписки: в день закрытия выписка еще открыта
+func lastStatementClosing(today time.Time, statementDay int) time.Time {
+	closing := monthDay(today.Year(), today.Month(), statementDay, today.Location())
+	if today.After(closing) {
+		return closing
+	}
+	previous := today.AddDate(0, 0, -today.Day())
+	return monthDay(previous.Year(), previous.Month(), statementDay, today.Location())
+}
@@ -0,0 +1,408 @@
+package services
+
+import (
+	"fmt"
+	"justTest/internal/interfaces"
+	"justTest/internal/models"
+	"justTest/internal/models/events"
+	"justTest/internal/utils"
+	"log"
+	"strings"
+	"time"
+)
+
+// debtReminderDays - за сколько дней до срока возврата приходит напоминание
+const debtReminderDays = 3
+
+// DebtService - долги между людьми: выдача/получение и возвраты со счетом создают транзакции типа debt,
+// которые меняют остаток счета, но не входят в доходы, расходы и бюджеты
+type DebtService struct {
+	debtRepo            interfaces.DebtRepository
+	bankAccountRepo     interfaces.BankAccountRepository
+	accountRepo         interfaces.AccountRepository
+	notificationService *NotificationService
+}
+
+func NewDebtService(
+	debtRepo interfaces.DebtRepository,
+	bankAccountRepo interfaces.BankAccountRepository,
+	accountRepo interfaces.AccountRepository,
+	notificationService *NotificationService,
+) *DebtService {
+	return &DebtService{
+		debtRepo:            debtRepo,
+		bankAccountRepo:     bankAccountRepo,
+		accountRepo:         accountRepo,
+		notificationService: notificationService,
+	}
+}
+
+func (s *DebtService) CreateCounterparty(userID string, req *models.CreateCounterpartyRequest) (*models.Counterparty, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("invalid name")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	existing, err := s.debtRepo.GetCounterparties(account.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, counterparty := range existing {
+		if strings.EqualFold(counterparty.Name, name) {
+			return nil, fmt.Errorf("counterparty %s already exists", counterparty.Name)
+		}
+	}
+	now := time.Now()
+	return s.debtRepo.CreateCounterparty(&models.Counterparty{
+		AccountID: account.ID,
+		Name:      name,
+		Note:      req.Note,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+}
+
+func (s *DebtService) GetCounterparties(userID string) ([]*models.Counterparty, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	return s.debtRepo.GetCounterparties(account.ID)
+}
+
+// DeleteCounterparty удаляет человека, если с ним нет ни одного долга (включая погашенные)
+func (s *DebtService) DeleteCounterparty(userID string, counterpartyID int64) error {
+	counterparty, account, err := s.getOwnedCounterparty(userID, counterpartyID)
+	if err != nil {
+		return err
+	}
+	debts, err := s.debtRepo.GetByAccountID(account.ID, &counterparty.ID, true)
+	if err != nil {
+		return err
+	}
+	if len(debts) > 0 {
+		return fmt.Errorf("counterparty has debts, delete them first")
+	}
+	return s.debtRepo.DeleteCounterparty(counterparty.ID)
+}
+
+// CreateDebt записывает долг; со счетом деньги списываются с него (lent) или зачисляются на него (borrowed)
+func (s *DebtService) CreateDebt(userID string, req *models.CreateDebtRequest) (*models.Debt, error) {
+	counterparty, account, err := s.getOwnedCounterparty(userID, req.CounterpartyID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Direction != "lent" && req.Direction != "borrowed" {
+		return nil, fmt.Errorf("invalid direction: %s", req.Direction)
+	}
+	loc := utils.LoadLocation(account.Timezone)
+	date, err := debtDate(req.Date, loc)
+	if err != nil {
+		return nil, err
+	}
+	var dueDate *time.Time
+	if req.DueDate != nil && *req.DueDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", *req.DueDate, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due date, expected YYYY-MM-DD")
+		}
+		if parsed.Before(date) {
+			return nil, fmt.Errorf("due date is before the debt date")
+		}
+		dueDate = &parsed
+	}
+
+	currency := req.Currency
+	var bankAccount *models.BankAccount
+	if req.BankAccountID != nil {
+		bankAccount, err = s.getOwnedBankAccount(account, *req.BankAccountID)
+		if err != nil {
+			return nil, err
+		}
+		if currency != "" && currency != bankAccount.Currency {
+			return nil, fmt.Errorf("debt currency must match the bank account currency %s", bankAccount.Currency)
+		}
+		currency = bankAccount.Currency
+	}
+	if currency == "" {
+		return nil, fmt.Errorf("currency is required for a debt without a bank account")
+	}
+	amount := req.Amount.WithCurrency(currency)
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	description := req.Description
+	if description == "" {
+		description = "Lent to " + counterparty.Name
+		if req.Direction == "borrowed" {
+			description = "Borrowed from " + counterparty.Name
+		}
+	}
+
+	now := time.Now()
+	debt := &models.Debt{
+		AccountID:        account.ID,
+		CounterpartyID:   counterparty.ID,
+		CounterpartyName: counterparty.Name,
+		Direction:        req.Direction,
+		Amount:           amount,
+		Currency:         currency,
+		Description:      description,
+		Date:             date,
+		DueDate:          dueDate,
+		BankAccountID:    req.BankAccountID,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	var transaction *models.Transaction
+	if bankAccount != nil {
+		// дали в долг - деньги ушли со счета, взяли - пришли
+		movement := amount.Neg()
+		if req.Direction == "borrowed" {
+			movement = amount
+		}
+		transaction = debtTransaction(bankAccount.ID, movement, description, date, now)
+	}
+	return s.debtRepo.Create(debt, transaction)
+}
+
+// GetDebts - долги пользователя; counterpartyID - только с этим человеком, includeSettled - вместе с погашенными
+func (s *DebtService) GetDebts(userID string, counterpartyID *int64, includeSettled bool) ([]*models.Debt, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	return s.debtRepo.GetByAccountID(account.ID, counterpartyID, includeSettled)
+}
+
+// GetDebt - долг вместе с его возвратами
+func (s *DebtService) GetDebt(userID string, debtID int64) (*models.Debt, error) {
+	debt, _, err := s.getOwnedDebt(userID, debtID)
+	if err != nil {
+		return nil, err
+	}
+	repayments, err := s.debtRepo.GetRepayments(debt.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, repayment := range repayments {
+		repayment.Amount.Currency = debt.Currency
+	}
+	debt.Repayments = repayments
+	return debt, nil
+}
+
+// DeleteDebt удаляет долг, его возвраты и их транзакции по счетам
+func (s *DebtService) DeleteDebt(userID string, debtID int64) error {
+	if _, _, err := s.getOwnedDebt(userID, debtID); err != nil {
+		return err
+	}
+	return s.debtRepo.Delete(debtID)
+}
+
+// AddRepayment записывает частичный или полный возврат; со счетом деньги зачисляются на него (lent)
+// или списываются с него (borrowed)
+func (s *DebtService) AddRepayment(userID string, debtID int64, req *models.DebtRepaymentRequest) (*models.DebtRepayment, error) {
+	debt, account, err := s.getOwnedDebt(userID, debtID)
+	if err != nil {
+		return nil, err
+	}
+	if debt.IsSettled {
+		return nil, fmt.Errorf("debt is already settled")
+	}
+	amount := req.Amount.WithCurrency(debt.Currency)
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if amount.Cmp(debt.Outstanding) > 0 {
+		return nil, fmt.Errorf("repayment exceeds the outstanding amount %s", debt.Outstanding)
+	}
+	date, err := debtDate(req.Date, utils.LoadLocation(account.Timezone))
+	if err != nil {
+		return nil, err
+	}
+	if date.Before(debt.Date) {
+		return nil, fmt.Errorf("repayment date is before the debt date")
+	}
+
+	now := time.Now()
+	repayment := &models.DebtRepayment{
+		DebtID:        debt.ID,
+		Amount:        amount,
+		Date:          date,
+		Note:          req.Note,
+		BankAccountID: req.BankAccountID,
+		CreatedAt:     now,
+	}
+	var transaction *models.Transaction
+	if req.BankAccountID != nil {
+		bankAccount, err := s.getOwnedBankAccount(account, *req.BankAccountID)
+		if err != nil {
+			return nil, err
+		}
+		if bankAccount.Currency != debt.Currency {
+			return nil, fmt.Errorf("bank account currency %s differs from the debt currency %s", bankAccount.Currency, debt.Currency)
+		}
+		// долг нам возвращают - деньги приходят, мы возвращаем - уходят
+		movement := amount
+		description := "Repayment from " + debt.CounterpartyName
+		if debt.Direction == "borrowed" {
+			movement = amount.Neg()
+			description = "Repayment to " + debt.CounterpartyName
+		}
+		transaction = debtTransaction(bankAccount.ID, movement, description, date, now)
+	}
+	return s.debtRepo.AddRepayment(repayment, transaction)
+}
+
+// GetBalances - непогашенные долги по людям и валютам
+func (s *DebtService) GetBalances(userID string) ([]*models.DebtBalance, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	return s.debtRepo.GetBalances(account.ID)
+}
+
+// SendDueReminders напоминает о непогашенных долгах за debtReminderDays дней до срока возврата
+// (и о просроченных, о которых еще не напоминали); напоминание по одному сроку отправляется один раз
+func (s *DebtService) SendDueReminders(now time.Time) error {
+	// с запасом в сутки: "сегодня" у аккаунтов в восточных таймзонах наступает раньше UTC
+	debts, err := s.debtRepo.GetDueForReminder(now.UTC().AddDate(0, 0, debtReminderDays+1))
+	if err != nil {
+		return err
+	}
+	for _, debt := range debts {
+		if err := s.sendDueReminder(debt, now); err != nil {
+			log.Printf("[DebtService] Error sending reminder for debt %d: %v", debt.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *DebtService) sendDueReminder(debt *models.Debt, now time.Time) error {
+	account, err := s.accountRepo.GetByID(debt.AccountID)
+	if err != nil {
+		return err
+	}
+	loc := utils.LoadLocation(account.Timezone)
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	dueDate := time.Date(debt.DueDate.Year(), debt.DueDate.Month(), debt.DueDate.Day(), 0, 0, 0, 0, loc)
+	if today.AddDate(0, 0, debtReminderDays).Before(dueDate) {
+		return nil
+	}
+	err = s.notificationService.HandleDebtDue(events.DebtDueEvent{
+		UserID:           account.UserID,
+		DebtID:           debt.ID,
+		CounterpartyName: debt.CounterpartyName,
+		Direction:        debt.Direction,
+		Outstanding:      debt.Outstanding,
+		DueDate:          dueDate,
+		Timestamp:        now,
+	})
+	if err != nil {
+		return err
+	}
+	return s.debtRepo.MarkReminderSent(debt.ID, dueDate)
+}
+
+func (s *DebtService) getOwnedCounterparty(userID string, counterpartyID int64) (*models.Counterparty, *models.Account, error) {
+	if userID == "" {
+		return nil, nil, fmt.Errorf("invalid user id")
+	}
+	if counterpartyID <= 0 {
+		return nil, nil, fmt.Errorf("invalid counterparty id")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get account: %w", err)
+	}
+	counterparty, err := s.debtRepo.GetCounterpartyByID(counterpartyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if counterparty.AccountID != account.ID {
+		return nil, nil, fmt.Errorf("counterparty does not belong to user")
+	}
+	return counterparty, account, nil
+}
+
+func (s *DebtService) getOwnedDebt(userID string, debtID int64) (*models.Debt, *models.Account, error) {
+	if userID == "" {
+		return nil, nil, fmt.Errorf("invalid user id")
+	}
+	if debtID <= 0 {
+		return nil, nil, fmt.Errorf("invalid debt id")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get account: %w", err)
+	}
+	debt, err := s.debtRepo.GetByID(debtID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if debt.AccountID != account.ID {
+		return nil, nil, fmt.Errorf("debt does not belong to user")
+	}
+	return debt, account, nil
+}
+
+func (s *DebtService) getOwnedBankAccount(account *models.Account, bankAccountID int64) (*models.BankAccount, error) {
+	bankAccount, err := s.bankAccountRepo.GetByBankAccountID(bankAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("bank account not found: %w", err)
+	}
+	if bankAccount.AccountID != account.ID {
+		return nil, fmt.Errorf("bank account does not belong to user")
+	}
+	if !bankAccount.IsActive {
+		return nil, fmt.Errorf("bank account is not active")
+	}
+	return bankAccount, nil
+}
+
+// debtDate - дата выдачи или возврата из запроса в таймзоне аккаунта, по умолчанию - сейчас, не в будущем
+func debtDate(value *string, loc *time.Location) (time.Time, error) {
+	now := time.Now()
+	if value == nil || *value == "" {
+		return now, nil
+	}
+	date, err := utils.ParseLocalDate(*value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date: %s", *value)
+	}
+	if date.After(now) {
+		return time.Time{}, fmt.Errorf("date is in the future")
+	}
+	return date, nil
+}
+
+// debtTransaction - транзакция типа debt: меняет остаток счета, но не входит в доходы, расходы и бюджеты
+func debtTransaction(bankAccountID int64, amount models.Money, description string, date, now time.Time) *models.Transaction {
+	return &models.Transaction{
+		BankAccountID:   bankAccountID,
+		Amount:          amount,
+		Description:     description,
+		TransactionType: "debt",
+		Date:            date,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+)
+
+// виды ошибок сервисов, по которым обработчики выбирают HTTP статус (400, 403, 404)
+// текст ошибки остается как есть, вид проверяется через errors.Is
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	ErrForbidden    = errors.New("access denied")
+	ErrNotFound     = errors.New("not found")
+)
+
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+func invalidInput(format string, args ...interface{}) error {
+	return &kindError{kind: ErrInvalidInput, err: fmt.Errorf(format, args...)}
+}
+
+func forbidden(format string, args ...interface{}) error {
+	return &kindError{kind: ErrForbidden, err: fmt.Errorf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return &kindError{kind: ErrNotFound, err: fmt.Errorf(format, args...)}
+}
@@ -0,0 +1,367 @@
+package services
+
+import (
+	"fmt"
+	"justTest/internal/interfaces"
+	"justTest/internal/models"
+	"justTest/internal/models/events"
+	"justTest/internal/utils"
+	"log"
+	"math"
+	"time"
+)
+
+// goalMilestones - рубежи прогресса цели в процентах, о каждом приходит одно уведомление
+var goalMilestones = []int{25, 50, 75, 100}
+
+// за сколько последних месяцев считается средний взнос для прогноза
+const (
+	defaultGoalProjectionMonths = 3
+	maxGoalProjectionMonths     = 24
+)
+
+type GoalService struct {
+	goalRepo            interfaces.GoalRepository
+	bankAccountRepo     interfaces.BankAccountRepository
+	accountRepo         interfaces.AccountRepository
+	transactionRepo     interfaces.TransactionRepository
+	balanceRepo         interfaces.BalanceRepository
+	notificationService *NotificationService
+}
+
+func NewGoalService(
+	goalRepo interfaces.GoalRepository,
+	bankAccountRepo interfaces.BankAccountRepository,
+	accountRepo interfaces.AccountRepository,
+	transactionRepo interfaces.TransactionRepository,
+	balanceRepo interfaces.BalanceRepository,
+	notificationService *NotificationService,
+) *GoalService {
+	return &GoalService{
+		goalRepo:            goalRepo,
+		bankAccountRepo:     bankAccountRepo,
+		accountRepo:         accountRepo,
+		transactionRepo:     transactionRepo,
+		balanceRepo:         balanceRepo,
+		notificationService: notificationService,
+	}
+}
+
+// CreateGoal создает цель: с bank_account_id прогресс - остаток сберегательного счета, иначе - взносы вручную
+func (s *GoalService) CreateGoal(userID string, req *models.CreateGoalRequest) (*models.Goal, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if req.Name == "" {
+		return nil, fmt.Errorf("invalid name")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := utils.LoadLocation(account.Timezone)
+	targetDate, err := time.ParseInLocation("2006-01-02", req.TargetDate, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target date, expected YYYY-MM-DD")
+	}
+	if !targetDate.After(time.Now()) {
+		return nil, fmt.Errorf("target date must be in the future")
+	}
+
+	currency := req.Currency
+	if req.BankAccountID != nil {
+		bankAccount, err := s.bankAccountRepo.GetByBankAccountID(*req.BankAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("bank account not found: %w", err)
+		}
+		if bankAccount.AccountID != account.ID {
+			return nil, fmt.Errorf("user is not owned by the bank account")
+		}
+		if bankAccount.AccountType != "savings" {
+			return nil, fmt.Errorf("goal can be linked only to a savings bank account")
+		}
+		if currency != "" && currency != bankAccount.Currency {
+			return nil, fmt.Errorf("goal currency must match the bank account currency %s", bankAccount.Currency)
+		}
+		currency = bankAccount.Currency
+	}
+	if currency == "" {
+		return nil, fmt.Errorf("currency is required for a goal without a bank account")
+	}
+	targetAmount := req.TargetAmount.WithCurrency(currency)
+	if !targetAmount.IsPositive() {
+		return nil, fmt.Errorf("target amount must be positive")
+	}
+
+	now := time.Now()
+	return s.goalRepo.Create(&models.Goal{
+		AccountID:     account.ID,
+		BankAccountID: req.BankAccountID,
+		Name:          req.Name,
+		TargetAmount:  targetAmount,
+		Currency:      currency,
+		TargetDate:    targetDate,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+}
+
+func (s *GoalService) getOwnedGoal(userID string, goalID int64) (*models.Goal, *models.Account, error) {
+	if userID == "" {
+		return nil, nil, fmt.Errorf("invalid user id")
+	}
+	if goalID <= 0 {
+		return nil, nil, fmt.Errorf("invalid goal id")
+	}
+	goal, err := s.goalRepo.GetByID(goalID)
+	if err != nil {
+		return nil, nil, err
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if goal.AccountID != account.ID {
+		return nil, nil, fmt.Errorf("goal with id %d not found", goalID)
+	}
+	return goal, account, nil
+}
+
+// GetGoals - прогресс всех целей пользователя, months - период среднего взноса (0 - по умолчанию)
+func (s *GoalService) GetGoals(userID string, months int) ([]*models.GoalProgress, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	goals, err := s.goalRepo.GetByAccountID(account.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.GoalProgress, 0, len(goals))
+	for _, goal := range goals {
+		progress, err := s.goalProgress(goal, account, months, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("goal %d: %w", goal.ID, err)
+		}
+		result = append(result, progress)
+	}
+	return result, nil
+}
+
+func (s *GoalService) GetGoal(userID string, goalID int64, months int) (*models.GoalProgress, error) {
+	goal, account, err := s.getOwnedGoal(userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+	return s.goalProgress(goal, account, months, time.Now())
+}
+
+func (s *GoalService) DeleteGoal(userID string, goalID int64) error {
+	if _, _, err := s.getOwnedGoal(userID, goalID); err != nil {
+		return err
+	}
+	return s.goalRepo.Delete(goalID)
+}
+
+// AddContribution записывает взнос в цель без привязанного счета и проверяет рубежи
+func (s *GoalService) AddContribution(userID string, goalID int64, req *models.GoalContributionRequest) (*models.GoalContribution, error) {
+	goal, account, err := s.getOwnedGoal(userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+	if goal.BankAccountID != nil {
+		return nil, fmt.Errorf("goal is linked to a bank account, its progress is the account balance")
+	}
+	amount := req.Amount.WithCurrency(goal.Currency)
+	if amount.IsZero() {
+		return nil, fmt.Errorf("invalid amount")
+	}
+	now := time.Now()
+	date := now
+	if req.Date != nil && *req.Date != "" {
+		date, err = utils.ParseLocalDate(*req.Date, utils.LoadLocation(account.Timezone))
+		if err != nil {
+			return nil, fmt.Errorf("invalid date: %s", *req.Date)
+		}
+		if date.After(now) {
+			return nil, fmt.Errorf("date is in the future")
+		}
+	}
+	contribution, err := s.goalRepo.AddContribution(&models.GoalContribution{
+		GoalID:    goal.ID,
+		Amount:    amount,
+		Date:      date,
+		Note:      req.Note,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMilestone(goal, account); err != nil {
+		log.Printf("[GoalService] Error checking milestone of goal %d: %v", goal.ID, err)
+	}
+	return contribution, nil
+}
+
+func (s *GoalService) GetContributions(userID string, goalID int64) ([]*models.GoalContribution, error) {
+	goal, _, err := s.getOwnedGoal(userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+	contributions, err := s.goalRepo.GetContributions(goal.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, contribution := range contributions {
+		contribution.Amount.Currency = goal.Currency
+	}
+	return contributions, nil
+}
+
+// CheckMilestones проверяет рубежи всех незавершенных целей, для целей со счетом прогресс меняется вместе с его остатком
+// ошибки по отдельным целям только логируются
+func (s *GoalService) CheckMilestones() error {
+	goals, err := s.goalRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, goal := range goals {
+		account, err := s.accountRepo.GetByID(goal.AccountID)
+		if err != nil {
+			log.Printf("[GoalService] Error getting account of goal %d: %v", goal.ID, err)
+			continue
+		}
+		if err := s.checkMilestone(goal, account); err != nil {
+			log.Printf("[GoalService] Error checking milestone of goal %d: %v", goal.ID, err)
+		}
+	}
+	return nil
+}
+
+// checkMilestone отправляет уведомление о самом высоком новом достигнутом рубеже
+// рубеж запоминается и при падении прогресса повторно не приходит
+func (s *GoalService) checkMilestone(goal *models.Goal, account *models.Account) error {
+	saved, err := s.savedAmount(goal, time.Now())
+	if err != nil {
+		return err
+	}
+	percent := saved.Percent(goal.TargetAmount)
+	reached := 0
+	for _, milestone := range goalMilestones {
+		if percent >= float64(milestone) {
+			reached = milestone
+		}
+	}
+	if reached <= goal.LastMilestone {
+		return nil
+	}
+	err = s.notificationService.HandleGoalMilestone(events.GoalMilestoneEvent{
+		UserID:       account.UserID,
+		GoalID:       goal.ID,
+		GoalName:     goal.Name,
+		Milestone:    reached,
+		SavedAmount:  saved,
+		TargetAmount: goal.TargetAmount,
+		Timestamp:    time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	goal.LastMilestone = reached
+	return s.goalRepo.SetLastMilestone(goal.ID, reached)
+}
+
+// savedAmount - накоплено к моменту before: остаток счета на конец предыдущего дня или сумма взносов
+// нулевой before - текущее значение
+func (s *GoalService) savedAmount(goal *models.Goal, before time.Time) (models.Money, error) {
+	if goal.BankAccountID == nil {
+		total, err := s.goalRepo.GetContributionTotal(goal.ID, before)
+		return total.WithCurrency(goal.Currency), err
+	}
+	if !before.After(time.Now()) {
+		day := before.AddDate(0, 0, -1)
+		snapshots, err := s.balanceRepo.GetDailyBalances(*goal.BankAccountID, day, day)
+		if err != nil {
+			return models.Money{}, err
+		}
+		if len(snapshots) == 0 {
+			return models.NewMoney(0, goal.Currency), nil
+		}
+		return snapshots[len(snapshots)-1].Balance.WithCurrency(goal.Currency), nil
+	}
+	balance, err := s.transactionRepo.GetTotalAmountByBankAccountID(*goal.BankAccountID)
+	return balance.WithCurrency(goal.Currency), err
+}
+
+// goalProgress считает прогресс на момент now, даты - в таймзоне аккаунта
+// средний взнос - прирост накоплений за последние months месяцев, деленный на months
+func (s *GoalService) goalProgress(goal *models.Goal, account *models.Account, months int, now time.Time) (*models.GoalProgress, error) {
+	if months <= 0 {
+		months = defaultGoalProjectionMonths
+	}
+	if months > maxGoalProjectionMonths {
+		return nil, fmt.Errorf("months must be between 1 and %d", maxGoalProjectionMonths)
+	}
+	loc := utils.LoadLocation(account.Timezone)
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	tomorrow := today.AddDate(0, 0, 1)
+	goal.TargetDate = time.Date(goal.TargetDate.Year(), goal.TargetDate.Month(), goal.TargetDate.Day(), 0, 0, 0, 0, loc)
+
+	saved, err := s.savedAmount(goal, tomorrow)
+	if err != nil {
+		return nil, err
+	}
+	savedBefore, err := s.savedAmount(goal, today.AddDate(0, -months, 0))
+	if err != nil {
+		return nil, err
+	}
+	progress := &models.GoalProgress{
+		Goal:      goal,
+		Saved:     saved,
+		Remaining: goal.TargetAmount.Sub(saved),
+		Percent:   saved.Percent(goal.TargetAmount),
+		Months:    months,
+	}
+	if !progress.Remaining.IsPositive() {
+		progress.Remaining = models.NewMoney(0, goal.Currency)
+		progress.IsCompleted = true
+	}
+
+	progress.MonthsLeft = monthsBetween(today, goal.TargetDate)
+	if progress.MonthsLeft < 1 {
+		progress.MonthsLeft = 1
+	}
+	progress.MonthlyNeeded, err = progress.Remaining.Convert(1/float64(progress.MonthsLeft), goal.Currency)
+	if err != nil {
+		return nil, err
+	}
+	progress.AverageMonthly, err = saved.Sub(savedBefore).Convert(1/float64(months), goal.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case progress.IsCompleted:
+		progress.ProjectedDate = &today
+		progress.OnTrack = true
+	case progress.AverageMonthly.IsPositive():
+		monthsNeeded := int(math.Ceil(float64(progress.Remaining.Minor) / float64(progress.AverageMonthly.Minor)))
+		projected := today.AddDate(0, monthsNeeded, 0)
+		progress.ProjectedDate = &projected
+		progress.OnTrack = !projected.After(goal.TargetDate)
+	}
+	return progress, nil
+}
+
+// monthsBetween - число полных месяцев от from до to
+func monthsBetween(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+	return months
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"justTest/internal/models"
+	"justTest/internal/utils"
+	"strings"
+	"time"
+)
+
+// camtDocument - выписка ISO 20022: camt.053 (BkToCstmrStmt/Stmt) или camt.052 (BkToCstmrAcctRpt/Rpt)
+// пространство имен не проверяется, поэтому подходят все версии схемы
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+	Reports    []camtStatement `xml:"BkToCstmrAcctRpt>Rpt"`
+}
+
+type camtStatement struct {
+	ID      string `xml:"Id"`
+	Account struct {
+		IBAN     string `xml:"Id>IBAN"`
+		Currency string `xml:"Ccy"`
+	} `xml:"Acct"`
+	Balances []camtBalance `xml:"Bal"`
+	Entries  []camtEntry   `xml:"Ntry"`
+}
+
+type camtBalance struct {
+	Code      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    camtAmount `xml:"Amt"`
+	Indicator string     `xml:"CdtDbtInd"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtEntry struct {
+	Ref       string     `xml:"NtryRef"`
+	Amount    camtAmount `xml:"Amt"`
+	Indicator string     `xml:"CdtDbtInd"`
+	// в старых версиях схемы статус - текст, в новых - Sts/Cd
+	Status struct {
+		Text string `xml:",chardata"`
+		Code string `xml:"Cd"`
+	} `xml:"Sts"`
+	BookingDate     string          `xml:"BookgDt>Dt"`
+	BookingDateTime string          `xml:"BookgDt>DtTm"`
+	ValueDate       string          `xml:"ValDt>Dt"`
+	ServicerRef     string          `xml:"AcctSvcrRef"`
+	AdditionalInfo  string          `xml:"AddtlNtryInf"`
+	Details         []camtTxDetails `xml:"NtryDtls>TxDtls"`
+}
+
+type camtTxDetails struct {
+	Unstructured []string `xml:"RmtInf>Ustrd"`
+	Creditor     string   `xml:"RltdPties>Cdtr>Nm"`
+	CreditorPty  string   `xml:"RltdPties>Cdtr>Pty>Nm"`
+	Debtor       string   `xml:"RltdPties>Dbtr>Nm"`
+	DebtorPty    string   `xml:"RltdPties>Dbtr>Pty>Nm"`
+	ServicerRef  string   `xml:"Refs>AcctSvcrRef"`
+}
+
+// camt-коды остатков: входящий (OPBD, PRCD - исходящий прошлого дня) и исходящий (CLBD, для camt.052 - ITBD)
+var (
+	camtOpeningCodes = []string{"OPBD", "PRCD"}
+	camtClosingCodes = []string{"CLBD", "ITBD"}
+)
+
+// parseCamtStatements разбирает все выписки (Stmt/Rpt) из файла camt.053/052
+func parseCamtStatements(file io.Reader, loc *time.Location) ([]*parsedStatement, error) {
+	var document camtDocument
+	if err := xml.NewDecoder(file).Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid camt xml: %w", err)
+	}
+	camtStatements := append(document.Statements, document.Reports...)
+	if len(camtStatements) == 0 {
+		return nil, fmt.Errorf("no statements found, expected camt.053 or camt.052")
+	}
+	statements := make([]*parsedStatement, 0, len(camtStatements))
+	for _, camt := range camtStatements {
+		statement := &parsedStatement{
+			Rows:     make([]*statementRow, 0, len(camt.Entries)),
+			Currency: strings.ToUpper(strings.TrimSpace(camt.Account.Currency)),
+			IBAN:     utils.NormalizeIBAN(camt.Account.IBAN),
+		}
+		var err error
+		if statement.OpeningBalance, err = camtBalanceAmount(camt.Balances, camtOpeningCodes); err != nil {
+			return nil, err
+		}
+		if statement.ClosingBalance, err = camtBalanceAmount(camt.Balances, camtClosingCodes); err != nil {
+			return nil, err
+		}
+		for i, entry := range camt.Entries {
+			if statement.Currency == "" && entry.Amount.Currency != "" {
+				statement.Currency = strings.ToUpper(entry.Amount.Currency)
+			}
+			statement.Rows = append(statement.Rows, camtRow(i+1, entry, statement.Currency, loc))
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+// camtBalanceAmount - первый найденный остаток по кодам в порядке приоритета, nil если его нет
+func camtBalanceAmount(balances []camtBalance, codes []string) (*models.Money, error) {
+	for _, code := range codes {
+		for _, balance := range balances {
+			if !strings.EqualFold(balance.Code, code) {
+				continue
+			}
+			amount, err := parseAmount(balance.Amount.Value, ".")
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s balance: %w", code, err)
+			}
+			if strings.EqualFold(balance.Indicator, "DBIT") {
+				amount = amount.Neg()
+			}
+			return &amount, nil
+		}
+	}
+	return nil, nil
+}
+
+func camtRow(number int, entry camtEntry, currency string, loc *time.Location) *statementRow {
+	row := &statementRow{Row: number}
+	status := strings.ToUpper(strings.TrimSpace(entry.Status.Code))
+	if status == "" {
+		status = strings.ToUpper(strings.TrimSpace(entry.Status.Text))
+	}
+	if status != "" && status != "BOOK" {
+		row.Skip = "entry is not booked (" + status + ")"
+		return row
+	}
+	if entry.Amount.Currency != "" && currency != "" && !strings.EqualFold(entry.Amount.Currency, currency) {
+		row.Err = fmt.Errorf("entry currency %s differs from account currency %s", entry.Amount.Currency, currency)
+		return row
+	}
+
+	date := entry.BookingDate
+	if date == "" && len(entry.BookingDateTime) >= 10 {
+		date = entry.BookingDateTime[:10]
+	}
+	if date == "" {
+		date = entry.ValueDate
+	}
+	var err error
+	row.Date, err = time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		row.Err = fmt.Errorf("invalid booking date %q", date)
+		return row
+	}
+	row.Amount, err = parseAmount(entry.Amount.Value, ".")
+	if err != nil {
+		row.Err = err
+		return row
+	}
+	if strings.EqualFold(entry.Indicator, "DBIT") {
+		row.Amount = row.Amount.Neg()
+	}
+
+	var counterparty, remittance string
+	if len(entry.Details) > 0 {
+		details := entry.Details[0]
+		// для расхода интересен получатель, для дохода - плательщик
+		if row.Amount.IsNegative() {
+			counterparty = firstNonEmpty(details.Creditor, details.CreditorPty)
+		} else {
+			counterparty = firstNonEmpty(details.Debtor, details.DebtorPty)
+		}
+		remittance = strings.Join(details.Unstructured, " ")
+		if len(entry.Details) == 1 && entry.ServicerRef == "" {
+			entry.ServicerRef = details.ServicerRef
+		}
+	}
+	row.Description = joinDescription(counterparty, remittance)
+	if row.Description == "" {
+		row.Description = strings.TrimSpace(entry.AdditionalInfo)
+	}
+	if row.Description == "" {
+		row.Err = fmt.Errorf("missing description")
+		return row
+	}
+	row.ExternalID = firstNonEmpty(entry.ServicerRef, entry.Ref)
+	return row
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"justTest/internal/interfaces"
+	"justTest/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const camt053 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <GrpHdr><MsgId>MSG-1</MsgId><CreDtTm>2024-11-01T06:00:00</CreDtTm></GrpHdr>
+    <Stmt>
+      <Id>STMT-2024-10</Id>
+      <Acct>
+        <Id><IBAN>KZ86 125K ZT50 0410 0100</IBAN></Id>
+        <Ccy>KZT</Ccy>
+      </Acct>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="KZT">100000.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+      </Bal>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="KZT">345500.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+      </Bal>
+      <Ntry>
+        <NtryRef>E1</NtryRef>
+        <Amt Ccy="KZT">4500.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2024-10-05</Dt></BookgDt>
+        <ValDt><Dt>2024-10-06</Dt></ValDt>
+        <AcctSvcrRef>REF-1</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <RltdPties>
+            <Dbtr><Nm>Me</Nm></Dbtr>
+            <Cdtr><Nm>Magnum</Nm></Cdtr>
+          </RltdPties>
+          <RmtInf><Ustrd>Groceries</Ustrd><Ustrd>card *1234</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="KZT">250000.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><DtTm>2024-10-10T09:15:00+05:00</DtTm></BookgDt>
+        <NtryDtls><TxDtls>
+          <Refs><AcctSvcrRef>REF-2</AcctSvcrRef></Refs>
+          <RltdPties>
+            <Dbtr><Nm>ACME Corp</Nm></Dbtr>
+            <Cdtr><Nm>Me</Nm></Cdtr>
+          </RltdPties>
+          <RmtInf><Ustrd>Salary</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <NtryRef>E3</NtryRef>
+        <Amt Ccy="KZT">1000.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>PDNG</Sts>
+        <BookgDt><Dt>2024-10-31</Dt></BookgDt>
+        <AddtlNtryInf>Pending card hold</AddtlNtryInf>
+      </Ntry>
+      <Ntry>
+        <NtryRef>E4</NtryRef>
+        <Amt Ccy="USD">10.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2024-10-20</Dt></BookgDt>
+        <AddtlNtryInf>Foreign purchase</AddtlNtryInf>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>
+`
+
+// camt052 - внутридневной отчет новой версии схемы: статус в Sts/Cd, остатки PRCD и ITBD
+const camt052 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.052.001.08">
+  <BkToCstmrAcctRpt>
+    <Rpt>
+      <Id>RPT-1</Id>
+      <Acct><Id><IBAN>DE89370400440532013000</IBAN></Id></Acct>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>PRCD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="EUR">50.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+      </Bal>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>ITBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="EUR">20.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+      </Bal>
+      <Ntry>
+        <NtryRef>R1</NtryRef>
+        <Amt Ccy="EUR">30.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <ValDt><Dt>2024-11-02</Dt></ValDt>
+        <NtryDtls><TxDtls>
+          <RltdPties><Dbtr><Pty><Nm>John Doe</Nm></Pty></Dbtr></RltdPties>
+        </TxDtls></NtryDtls>
+      </Ntry>
+    </Rpt>
+  </BkToCstmrAcctRpt>
+</Document>
+`
+
+func TestParseCamtStatements053(t *testing.T) {
+	loc := time.FixedZone("ALMT", 5*60*60)
+	statements, err := parseCamtStatements(strings.NewReader(camt053), loc)
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+
+	statement := statements[0]
+	assert.Equal(t, "KZT", statement.Currency)
+	assert.Equal(t, "KZ86125KZT5004100100", statement.IBAN)
+	require.NotNil(t, statement.OpeningBalance)
+	assert.Equal(t, int64(10000000), statement.OpeningBalance.Minor)
+	require.NotNil(t, statement.ClosingBalance)
+	assert.Equal(t, int64(34550000), statement.ClosingBalance.Minor)
+	require.Len(t, statement.Rows, 4)
+
+	// DBIT - расход: описание по получателю, AcctSvcrRef важнее NtryRef
+	row := statement.Rows[0]
+	assert.NoError(t, row.Err)
+	assert.Equal(t, 1, row.Row)
+	assert.Equal(t, time.Date(2024, 10, 5, 0, 0, 0, 0, loc), row.Date)
+	assert.Equal(t, int64(-450000), row.Amount.Minor)
+	assert.Equal(t, "Magnum - Groceries card *1234", row.Description)
+	assert.Equal(t, "REF-1", row.ExternalID)
+
+	// CRDT - доход: описание по плательщику, дата из DtTm, ссылка из TxDtls
+	row = statements[0].Rows[1]
+	assert.NoError(t, row.Err)
+	assert.Equal(t, time.Date(2024, 10, 10, 0, 0, 0, 0, loc), row.Date)
+	assert.Equal(t, int64(25000000), row.Amount.Minor)
+	assert.Equal(t, "ACME Corp - Salary", row.Description)
+	assert.Equal(t, "REF-2", row.ExternalID)
+
+	row = statements[0].Rows[2]
+	assert.NoError(t, row.Err)
+	assert.Equal(t, "entry is not booked (PDNG)", row.Skip)
+
+	row = statements[0].Rows[3]
+	if assert.Error(t, row.Err) {
+		assert.Contains(t, row.Err.Error(), "differs from account currency")
+	}
+
+	// входящий остаток + проведенные операции = исходящий
+	total := *statement.OpeningBalance
+	for _, row := range statement.Rows {
+		if row.Err == nil && row.Skip == "" {
+			total = total.Add(row.Amount)
+		}
+	}
+	assert.Equal(t, 0, total.Cmp(*statement.ClosingBalance))
+}
+
+func TestParseCamtStatements052(t *testing.T) {
+	statements, err := parseCamtStatements(strings.NewReader(camt052), time.UTC)
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+
+	statement := statements[0]
+	// без Acct/Ccy валюта берется из операций
+	assert.Equal(t, "EUR", statement.Currency)
+	assert.Equal(t, "DE89370400440532013000", statement.IBAN)
+	// DBIT у остатка - отрицательный остаток
+	require.NotNil(t, statement.OpeningBalance)
+	assert.Equal(t, int64(-5000), statement.OpeningBalance.Minor)
+	require.NotNil(t, statement.ClosingBalance)
+	assert.Equal(t, int64(-2000), statement.ClosingBalance.Minor)
+	require.Len(t, statement.Rows, 1)
+
+	row := statement.Rows[0]
+	assert.NoError(t, row.Err)
+	// без BookgDt берется дата валютирования
+	assert.Equal(t, time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC), row.Date)
+	assert.Equal(t, int64(3000), row.Amount.Minor)
+	assert.Equal(t, "John Doe", row.Description)
+	assert.Equal(t, "R1", row.ExternalID)
+}
+
+func TestParseCamtStatementsBalancePriority(t *testing.T) {
+	file := `<Document><BkToCstmrStmt><Stmt>
+<Bal><Tp><CdOrPrtry><Cd>PRCD</Cd></CdOrPrtry></Tp><Amt Ccy="EUR">1.00</Amt><CdtDbtInd>CRDT</CdtDbtInd></Bal>
+<Bal><Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp><Amt Ccy="EUR">2.00</Amt><CdtDbtInd>CRDT</CdtDbtInd></Bal>
+<Bal><Tp><CdOrPrtry><Cd>ITBD</Cd></CdOrPrtry></Tp><Amt Ccy="EUR">3.00</Amt><CdtDbtInd>CRDT</CdtDbtInd></Bal>
+<Bal><Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp><Amt Ccy="EUR">4.00</Amt><CdtDbtInd>CRDT</CdtDbtInd></Bal>
+</Stmt></BkToCstmrStmt></Document>`
+	statements, err := parseCamtStatements(strings.NewReader(file), time.UTC)
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+	// OPBD важнее PRCD, CLBD важнее ITBD
+	assert.Equal(t, int64(200), statements[0].OpeningBalance.Minor)
+	assert.Equal(t, int64(400), statements[0].ClosingBalance.Minor)
+}
+
+func TestParseCamtStatementsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		err  string
+	}{
+		{"not xml", "date,amount\n", "invalid camt xml"},
+		{"no statements", `<Document><BkToCstmrDbtCdtNtfctn/></Document>`, "no statements found"},
+		{
+			"invalid balance",
+			`<Document><BkToCstmrStmt><Stmt><Bal><Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp><Amt>1e3</Amt></Bal></Stmt></BkToCstmrStmt></Document>`,
+			"invalid OPBD balance",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCamtStatements(strings.NewReader(tt.file), time.UTC)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+// balanceTransactionRepo - репозиторий транзакций, у которого есть только баланс счета
+type balanceTransactionRepo struct {
+	interfaces.TransactionRepository
+	balance models.Money
+}
+
+func (r *balanceTransactionRepo) GetTotalAmountByBankAccountID(int64) (models.Money, error) {
+	return r.balance, nil
+}
+
+func TestImportServiceReconcile(t *testing.T) {
+	statements, err := parseCamtStatements(strings.NewReader(camt053), time.UTC)
+	require.NoError(t, err)
+	statement := statements[0]
+	// в выписке проведено -4500.00 + 250000.00
+	inLedger := models.NewMoney(24550000, "KZT")
+
+	tests := []struct {
+		name              string
+		ledger            int64
+		openingDifference int64
+		closingDifference int64
+		balanced          bool
+	}{
+		{"balanced", 34550000, 0, 0, true},
+		{"missing earlier transaction", 34500000, -50000, -50000, false},
+		{"extra transaction in ledger", 34560000, 10000, 10000, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ImportService{transactionRepo: &balanceTransactionRepo{balance: models.NewMoney(tt.ledger, "KZT")}}
+			reconciliation, err := service.reconcile(1, statement, inLedger)
+			require.NoError(t, err)
+
+			assert.Equal(t, "KZ86125KZT5004100100", reconciliation.IBAN)
+			assert.Equal(t, tt.ledger, reconciliation.LedgerBalance.Minor)
+			assert.Equal(t, tt.ledger-inLedger.Minor, reconciliation.LedgerOpeningBalance.Minor)
+			require.NotNil(t, reconciliation.OpeningDifference)
+			assert.Equal(t, tt.openingDifference, reconciliation.OpeningDifference.Minor)
+			require.NotNil(t, reconciliation.ClosingDifference)
+			assert.Equal(t, tt.closingDifference, reconciliation.ClosingDifference.Minor)
+			assert.Equal(t, tt.balanced, reconciliation.IsBalanced)
+		})
+	}
+
+	// без остатков в выписке сверять не с чем
+	service := &ImportService{transactionRepo: &balanceTransactionRepo{balance: models.NewMoney(100, "KZT")}}
+	reconciliation, err := service.reconcile(1, &parsedStatement{}, models.Money{})
+	require.NoError(t, err)
+	assert.Nil(t, reconciliation.OpeningDifference)
+	assert.Nil(t, reconciliation.ClosingDifference)
+	assert.True(t, reconciliation.IsBalanced)
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"justTest/internal/models"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// dateFormatReplacer переводит формат вида "DD.MM.YYYY" в layout для time.Parse
+var dateFormatReplacer = strings.NewReplacer(
+	"YYYY", "2006",
+	"YY", "06",
+	"MM", "01",
+	"DD", "02",
+	"HH", "15",
+	"mm", "04",
+	"ss", "05",
+)
+
+// parseCSVStatement разбирает CSV выписку по маппингу колонок, первая строка - заголовок
+// ошибки отдельных строк возвращаются в statementRow.Err, ошибка функции - только если файл не читается целиком
+func parseCSVStatement(file io.Reader, req *models.ImportStatementRequest, loc *time.Location) ([]*statementRow, error) {
+	if req.DateColumn == "" || req.AmountColumn == "" || req.DescriptionColumn == "" {
+		return nil, fmt.Errorf("date_column, amount_column and description_column are required")
+	}
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	switch req.Delimiter {
+	case "", ",":
+		reader.Comma = ','
+	case ";":
+		reader.Comma = ';'
+	case "tab", "\t":
+		reader.Comma = '\t'
+	case "|":
+		reader.Comma = '|'
+	default:
+		return nil, fmt.Errorf("unsupported delimiter %q", req.Delimiter)
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("empty statement file")
+		}
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	dateIdx, err := columnIndex(header, req.DateColumn)
+	if err != nil {
+		return nil, err
+	}
+	amountIdx, err := columnIndex(header, req.AmountColumn)
+	if err != nil {
+		return nil, err
+	}
+	descriptionIdx, err := columnIndex(header, req.DescriptionColumn)
+	if err != nil {
+		return nil, err
+	}
+	categoryIdx := -1
+	if req.CategoryColumn != "" {
+		categoryIdx, err = columnIndex(header, req.CategoryColumn)
+		if err != nil {
+			return nil, err
+		}
+	}
+	dateFormat := req.DateFormat
+	if dateFormat == "" {
+		dateFormat = "YYYY-MM-DD"
+	}
+	layout := dateFormatReplacer.Replace(dateFormat)
+
+	rows := make([]*statementRow, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rows = append(rows, &statementRow{Row: parseErr.StartLine, Err: fmt.Errorf("invalid csv: %v", parseErr.Err)})
+				continue
+			}
+			return nil, fmt.Errorf("read statement: %w", err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		row := &statementRow{Row: line}
+		rows = append(rows, row)
+
+		value, ok := recordValue(record, dateIdx)
+		if !ok {
+			row.Err = fmt.Errorf("missing date")
+			continue
+		}
+		row.Date, err = time.ParseInLocation(layout, value, loc)
+		if err != nil {
+			row.Err = fmt.Errorf("invalid date %q, expected %s", value, dateFormat)
+			continue
+		}
+		value, ok = recordValue(record, amountIdx)
+		if !ok {
+			row.Err = fmt.Errorf("missing amount")
+			continue
+		}
+		row.Amount, err = parseAmount(value, req.DecimalSeparator)
+		if err != nil {
+			row.Err = err
+			continue
+		}
+		row.Description, ok = recordValue(record, descriptionIdx)
+		if !ok {
+			row.Err = fmt.Errorf("missing description")
+			continue
+		}
+		if categoryIdx >= 0 {
+			row.Category, _ = recordValue(record, categoryIdx)
+		}
+	}
+	return rows, nil
+}
+
+// columnIndex ищет колонку по имени в заголовке (без учета регистра) или по номеру с 1
+func columnIndex(header []string, column string) (int, error) {
+	column = strings.TrimSpace(column)
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return i, nil
+		}
+	}
+	if number, err := strconv.Atoi(column); err == nil && number >= 1 && number <= len(header) {
+		return number - 1, nil
+	}
+	return 0, fmt.Errorf("column %q not found in header", column)
+}
+
+func recordValue(record []string, idx int) (string, bool) {
+	if idx >= len(record) {
+		return "", false
+	}
+	value := strings.TrimSpace(record[idx])
+	return value, value != ""
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAmount разбирает сумму вида "-1 234,50", "1,234.50" или "(99.90)" (скобки - расход)
+func parseAmount(value, decimalSeparator string) (models.Money, error) {
+	raw := strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(raw, "(") && strings.HasSuffix(raw, ")") {
+		negative = true
+		raw = raw[1 : len(raw)-1]
+	}
+	raw = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '\'' {
+			return -1
+		}
+		return r
+	}, raw)
+	if decimalSeparator == "," {
+		raw = strings.ReplaceAll(raw, ".", "")
+		raw = strings.Replace(raw, ",", ".", 1)
+	} else {
+		raw = strings.ReplaceAll(raw, ",", "")
+	}
+	amount, err := models.ParseMoney(raw, "")
+	if err != nil {
+		return models.Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	if negative {
+		amount = amount.Abs().Neg()
+	}
+	return amount, nil
+}
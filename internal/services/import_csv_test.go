@@ -0,0 +1,199 @@
+package services
+
+import (
+	"justTest/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		name             string
+		value            string
+		decimalSeparator string
+		want             int64
+	}{
+		{"plain", "1234.50", ".", 123450},
+		{"negative", "-99.9", ".", -9990},
+		{"thousands comma", "1,234.50", ".", 123450},
+		{"default separator", "1,234.50", "", 123450},
+		{"thousands space comma decimal", "1 234,50", ",", 123450},
+		{"negative space comma decimal", "-1 234,50", ",", -123450},
+		{"thousands dot comma decimal", "1.234.567,89", ",", 123456789},
+		{"non-breaking space", "12\u00a0000,00", ",", 1200000},
+		{"swiss apostrophe", "1'234.50", ".", 123450},
+		{"parentheses", "(99.90)", ".", -9990},
+		{"parentheses with minus", "(-99.90)", ".", -9990},
+		{"parentheses comma decimal", "(1 234,50)", ",", -123450},
+		{"surrounding spaces", "  42 ", ".", 4200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAmount(tt.value, tt.decimalSeparator)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Minor)
+		})
+	}
+}
+
+func TestParseAmountInvalid(t *testing.T) {
+	for _, value := range []string{"", "abc", "12,34,56", "1/3", "1e3", "()"} {
+		t.Run(value, func(t *testing.T) {
+			_, err := parseAmount(value, ",")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseCSVStatement(t *testing.T) {
+	loc := time.FixedZone("ALMT", 5*60*60)
+	tests := []struct {
+		name string
+		file string
+		req  models.ImportStatementRequest
+	}{
+		{
+			name: "comma delimiter",
+			file: "Date,Amount,Description,Category\n" +
+				"2024-10-05,-4500.00,Magnum,Продукты\n" +
+				"2024-10-06,\"1,250,000.00\",Зарплата,\n",
+			req: models.ImportStatementRequest{
+				DateColumn: "date", AmountColumn: "Amount", DescriptionColumn: "description", CategoryColumn: "Category",
+			},
+		},
+		{
+			name: "semicolon delimiter with bom and comma decimals",
+			file: "\ufeffДата;Сумма;Описание;Категория\n" +
+				"05.10.2024;-4 500,00;Magnum;Продукты\n" +
+				"06.10.2024;1 250 000,00;Зарплата;\n",
+			req: models.ImportStatementRequest{
+				DateColumn: "Дата", AmountColumn: "Сумма", DescriptionColumn: "Описание", CategoryColumn: "Категория",
+				DateFormat: "DD.MM.YYYY", DecimalSeparator: ",", Delimiter: ";",
+			},
+		},
+		{
+			name: "tab delimiter with column numbers",
+			file: "d\ta\tdesc\tcat\n" +
+				"10/05/2024\t(4500.00)\tMagnum\tПродукты\n" +
+				"10/06/2024\t1250000\tЗарплата\t\n",
+			req: models.ImportStatementRequest{
+				DateColumn: "1", AmountColumn: "2", DescriptionColumn: "3", CategoryColumn: "4",
+				DateFormat: "MM/DD/YYYY", Delimiter: "tab",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseCSVStatement(strings.NewReader(tt.file), &tt.req, loc)
+			require.NoError(t, err)
+			require.Len(t, rows, 2)
+
+			assert.NoError(t, rows[0].Err)
+			assert.Equal(t, 2, rows[0].Row)
+			assert.Equal(t, time.Date(2024, 10, 5, 0, 0, 0, 0, loc), rows[0].Date)
+			assert.Equal(t, int64(-450000), rows[0].Amount.Minor)
+			assert.Equal(t, "Magnum", rows[0].Description)
+			assert.Equal(t, "Продукты", rows[0].Category)
+
+			assert.NoError(t, rows[1].Err)
+			assert.Equal(t, 3, rows[1].Row)
+			assert.Equal(t, time.Date(2024, 10, 6, 0, 0, 0, 0, loc), rows[1].Date)
+			assert.Equal(t, int64(125000000), rows[1].Amount.Minor)
+			assert.Equal(t, "Зарплата", rows[1].Description)
+			assert.Empty(t, rows[1].Category)
+		})
+	}
+}
+
+func TestParseCSVStatementRowErrors(t *testing.T) {
+	file := "date,amount,description\n" +
+		"2024-10-01,-100.00,ok\n" +
+		"\n" +
+		"01.10.2024,-100.00,wrong date format\n" +
+		"2024-10-02,,missing amount\n" +
+		"2024-10-03,1/3,fraction\n" +
+		"2024-10-04,-5.00,\n" +
+		"2024-10-05\n" +
+		"2024-10-06,-7.00,Кафе \"Уют\"\n"
+	req := &models.ImportStatementRequest{DateColumn: "date", AmountColumn: "amount", DescriptionColumn: "description"}
+
+	rows, err := parseCSVStatement(strings.NewReader(file), req, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, rows, 7)
+
+	assert.NoError(t, rows[0].Err)
+	assert.Equal(t, 2, rows[0].Row)
+	// пустая строка пропускается, но номера строк остаются как в файле
+	want := []struct {
+		row int
+		err string
+	}{
+		{4, "invalid date"},
+		{5, "missing amount"},
+		{6, "invalid amount"},
+		{7, "missing description"},
+		{8, "missing amount"},
+	}
+	for i, w := range want {
+		row := rows[i+1]
+		assert.Equal(t, w.row, row.Row)
+		if assert.Error(t, row.Err, "row %d", w.row) {
+			assert.Contains(t, row.Err.Error(), w.err)
+		}
+	}
+	// кавычки внутри поля без экранирования допускаются (LazyQuotes)
+	assert.NoError(t, rows[6].Err)
+	assert.Equal(t, 9, rows[6].Row)
+	assert.Equal(t, `Кафе "Уют"`, rows[6].Description)
+}
+
+func TestParseCSVStatementErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		req  models.ImportStatementRequest
+		err  string
+	}{
+		{
+			name: "missing mapping",
+			file: "date,amount\n",
+			req:  models.ImportStatementRequest{DateColumn: "date", AmountColumn: "amount"},
+			err:  "required",
+		},
+		{
+			name: "unknown column",
+			file: "date,amount,description\n",
+			req:  models.ImportStatementRequest{DateColumn: "date", AmountColumn: "sum", DescriptionColumn: "description"},
+			err:  `column "sum" not found`,
+		},
+		{
+			name: "column number out of range",
+			file: "date,amount,description\n",
+			req:  models.ImportStatementRequest{DateColumn: "1", AmountColumn: "2", DescriptionColumn: "4"},
+			err:  `column "4" not found`,
+		},
+		{
+			name: "unsupported delimiter",
+			file: "date,amount,description\n",
+			req:  models.ImportStatementRequest{DateColumn: "date", AmountColumn: "amount", DescriptionColumn: "description", Delimiter: "#"},
+			err:  "unsupported delimiter",
+		},
+		{
+			name: "empty file",
+			file: "",
+			req:  models.ImportStatementRequest{DateColumn: "date", AmountColumn: "amount", DescriptionColumn: "description"},
+			err:  "empty statement file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCSVStatement(strings.NewReader(tt.file), &tt.req, time.UTC)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// parseOFXStatement разбирает OFX/QFX выписку: SGML (OFX 1.x, теги без закрывающих) и XML (OFX 2.x)
+// номер строки - порядковый номер STMTTRN в файле, FITID идет в ExternalID
+func parseOFXStatement(file io.Reader, loc *time.Location) (*parsedStatement, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("read statement: %w", err)
+	}
+	content := string(data)
+	if !strings.Contains(strings.ToUpper(content), "<OFX") {
+		return nil, fmt.Errorf("not an OFX file")
+	}
+
+	statement := &parsedStatement{Rows: make([]*statementRow, 0)}
+	var fields map[string]string
+	number := 0
+	for _, tag := range ofxTags(content) {
+		switch {
+		case tag.name == "STMTTRN":
+			fields = make(map[string]string)
+		case tag.name == "/STMTTRN":
+			if fields == nil {
+				continue
+			}
+			number++
+			statement.Rows = append(statement.Rows, ofxRow(number, fields, loc))
+			fields = nil
+		case tag.name == "CURDEF" && statement.Currency == "":
+			statement.Currency = strings.ToUpper(tag.value)
+		case fields != nil && !strings.HasPrefix(tag.name, "/"):
+			fields[tag.name] = tag.value
+		}
+	}
+	return statement, nil
+}
+
+type ofxTag struct {
+	name  string
+	value string
+}
+
+// ofxTags разбивает OFX на теги и текст после них, подходит для SGML и XML
+func ofxTags(content string) []ofxTag {
+	tags := make([]ofxTag, 0)
+	for {
+		start := strings.IndexByte(content, '<')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(content[start:], '>')
+		if end < 0 {
+			break
+		}
+		name := strings.TrimSpace(content[start+1 : start+end])
+		content = content[start+end+1:]
+		if name == "" || name[0] == '?' || name[0] == '!' {
+			continue
+		}
+		value := content
+		if next := strings.IndexByte(content, '<'); next >= 0 {
+			value = content[:next]
+		}
+		tags = append(tags, ofxTag{
+			name:  strings.ToUpper(strings.Fields(name)[0]),
+			value: html.UnescapeString(strings.TrimSpace(value)),
+		})
+	}
+	return tags
+}
+
+func ofxRow(number int, fields map[string]string, loc *time.Location) *statementRow {
+	row := &statementRow{Row: number, ExternalID: fields["FITID"]}
+	posted := fields["DTPOSTED"]
+	if len(posted) < 8 {
+		row.Err = fmt.Errorf("invalid DTPOSTED %q", posted)
+		return row
+	}
+	var err error
+	// время и таймзона банка отбрасываются, как и в CSV берется календарный день
+	row.Date, err = time.ParseInLocation("20060102", posted[:8], loc)
+	if err != nil {
+		row.Err = fmt.Errorf("invalid DTPOSTED %q", posted)
+		return row
+	}
+	amount := fields["TRNAMT"]
+	decimalSeparator := "."
+	if strings.Contains(amount, ",") && !strings.Contains(amount, ".") {
+		decimalSeparator = ","
+	}
+	row.Amount, err = parseAmount(amount, decimalSeparator)
+	if err != nil {
+		row.Err = err
+		return row
+	}
+	row.Description = joinDescription(fields["NAME"], fields["MEMO"])
+	if row.Description == "" {
+		row.Description = strings.ToLower(fields["TRNTYPE"])
+	}
+	if row.Description == "" {
+		row.Err = fmt.Errorf("missing description")
+	}
+	return row
+}
+
+// joinDescription склеивает получателя и комментарий, если они различаются
+func joinDescription(payee, memo string) string {
+	payee = strings.TrimSpace(payee)
+	memo = strings.TrimSpace(memo)
+	switch {
+	case payee == "":
+		return memo
+	case memo == "" || strings.EqualFold(payee, memo):
+		return payee
+	default:
+		return payee + " - " + memo
+	}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ofxSGML - OFX 1.x: заголовок без XML, листовые теги не закрываются
+const ofxSGML = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:USASCII
+
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS><DTSERVER>20241031120000</SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<STMTRS>
+<CURDEF>usd
+<BANKACCTFROM><BANKID>123456789<ACCTID>0001<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>20241001
+<DTEND>20241031
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20241005120000.000[-5:EST]
+<TRNAMT>-45.10
+<FITID>2024100501
+<NAME>Whole Foods
+<MEMO>Groceries
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20241015
+<TRNAMT>1250,00
+<FITID>2024101501
+<NAME>ACME Corp &amp; Co
+<MEMO>ACME Corp &amp; Co
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>FEE
+<DTPOSTED>202410200000
+<TRNAMT>-2.50
+<FITID>2024102001
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>1202.40<DTASOF>20241031</LEDGERBAL>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`
+
+// ofxXML - OFX 2.x: XML с закрывающими тегами
+const ofxXML = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+  <BANKMSGSRSV1>
+    <STMTTRNRS>
+      <STMTRS>
+        <CURDEF>EUR</CURDEF>
+        <BANKTRANLIST>
+          <STMTTRN>
+            <TRNTYPE>POS</TRNTYPE>
+            <DTPOSTED>20241102093000[+1:CET]</DTPOSTED>
+            <TRNAMT>-12.00</TRNAMT>
+            <FITID>X-1</FITID>
+            <NAME>Cafe</NAME>
+          </STMTTRN>
+          <stmttrn>
+            <trntype>CREDIT</trntype>
+            <dtposted>20241103</dtposted>
+            <trnamt>100</trnamt>
+            <fitid>X-2</fitid>
+            <memo>Refund</memo>
+          </stmttrn>
+        </BANKTRANLIST>
+      </STMTRS>
+    </STMTTRNRS>
+  </BANKMSGSRSV1>
+</OFX>
+`
+
+func TestParseOFXStatementSGML(t *testing.T) {
+	loc := time.FixedZone("ALMT", 5*60*60)
+	statement, err := parseOFXStatement(strings.NewReader(ofxSGML), loc)
+	require.NoError(t, err)
+
+	assert.Equal(t, "USD", statement.Currency)
+	require.Len(t, statement.Rows, 3)
+
+	row := statement.Rows[0]
+	assert.NoError(t, row.Err)
+	assert.Equal(t, 1, row.Row)
+	assert.Equal(t, "2024100501", row.ExternalID)
+	// время и таймзона из DTPOSTED отбрасываются
+	assert.Equal(t, time.Date(2024, 10, 5, 0, 0, 0, 0, loc), row.Date)
+	assert.Equal(t, int64(-4510), row.Amount.Minor)
+	assert.Equal(t, "Whole Foods - Groceries", row.Description)
+
+	row = statement.Rows[1]
+	assert.NoError(t, row.Err)
+	assert.Equal(t, 2, row.Row)
+	assert.Equal(t, "2024101501", row.ExternalID)
+	assert.Equal(t, time.Date(2024, 10, 15, 0, 0, 0, 0, loc), row.Date)
+	// запятая без точки - десятичный разделитель
+	assert.Equal(t, int64(125000), row.Amount.Minor)
+	assert.Equal(t, "ACME Corp & Co", row.Description)
+
+	row = statement.Rows[2]
+	assert.NoError(t, row.Err)
+	assert.Equal(t, time.Date(2024, 10, 20, 0, 0, 0, 0, loc), row.Date)
+	assert.Equal(t, int64(-250), row.Amount.Minor)
+	// без NAME и MEMO описанием становится тип операции
+	assert.Equal(t, "fee", row.Description)
+}
+
+func TestParseOFXStatementXML(t *testing.T) {
+	statement, err := parseOFXStatement(strings.NewReader(ofxXML), time.UTC)
+	require.NoError(t, err)
+
+	assert.Equal(t, "EUR", statement.Currency)
+	require.Len(t, statement.Rows, 2)
+
+	assert.NoError(t, statement.Rows[0].Err)
+	assert.Equal(t, "X-1", statement.Rows[0].ExternalID)
+	assert.Equal(t, time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC), statement.Rows[0].Date)
+	assert.Equal(t, int64(-1200), statement.Rows[0].Amount.Minor)
+	assert.Equal(t, "Cafe", statement.Rows[0].Description)
+
+	// теги в нижнем регистре тоже разбираются
+	assert.NoError(t, statement.Rows[1].Err)
+	assert.Equal(t, "X-2", statement.Rows[1].ExternalID)
+	assert.Equal(t, int64(10000), statement.Rows[1].Amount.Minor)
+	assert.Equal(t, "Refund", statement.Rows[1].Description)
+}
+
+func TestParseOFXStatementRowErrors(t *testing.T) {
+	file := `<OFX><BANKTRANLIST>
+<STMTTRN><DTPOSTED>2024<TRNAMT>-1.00<NAME>short date</STMTTRN>
+<STMTTRN><TRNAMT>-1.00<NAME>no date</STMTTRN>
+<STMTTRN><DTPOSTED>20241399<TRNAMT>-1.00<NAME>bad month</STMTTRN>
+<STMTTRN><DTPOSTED>20241001<TRNAMT>1/3<NAME>fraction</STMTTRN>
+<STMTTRN><DTPOSTED>20241001<TRNAMT>-1.00</STMTTRN>
+</BANKTRANLIST></OFX>`
+	statement, err := parseOFXStatement(strings.NewReader(file), time.UTC)
+	require.NoError(t, err)
+	require.Len(t, statement.Rows, 5)
+
+	want := []string{"invalid DTPOSTED", "invalid DTPOSTED", "invalid DTPOSTED", "invalid amount", "missing description"}
+	for i, w := range want {
+		row := statement.Rows[i]
+		assert.Equal(t, i+1, row.Row)
+		if assert.Error(t, row.Err, "row %d", i+1) {
+			assert.Contains(t, row.Err.Error(), w)
+		}
+	}
+}
+
+func TestParseOFXStatementNotOFX(t *testing.T) {
+	_, err := parseOFXStatement(strings.NewReader("date,amount\n2024-10-01,1.00\n"), time.UTC)
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"justTest/internal/models"
+	"strings"
+	"time"
+)
+
+// qifDateLayouts - форматы дат QIF: американские с апострофом для года и европейские
+var qifDateLayouts = []string{"1/2/2006", "1/2/06", "02.01.2006", "02.01.06", "2006-01-02"}
+
+// parseQIFStatement разбирает QIF выписку (!Type:Bank, Cash, CCard), строки разбивки S/E/$ не учитываются
+// номер строки - строка файла, с которой начинается запись
+func parseQIFStatement(file io.Reader, req *models.ImportStatementRequest, loc *time.Location) (*parsedStatement, error) {
+	statement := &parsedStatement{Rows: make([]*statementRow, 0)}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		fields    map[string]string
+		startLine int
+		line      int
+		skip      bool
+	)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if text[0] == '!' {
+			header := strings.ToLower(strings.TrimSpace(text))
+			// счета, категории и классы - не транзакции
+			skip = !strings.HasPrefix(header, "!type:") || strings.HasPrefix(header, "!type:cat") ||
+				strings.HasPrefix(header, "!type:class") || strings.HasPrefix(header, "!type:memorized") ||
+				strings.HasPrefix(header, "!type:invst")
+			if strings.HasPrefix(header, "!option") || strings.HasPrefix(header, "!clear") {
+				skip = false
+			}
+			fields = nil
+			continue
+		}
+		if text[0] == '^' {
+			if fields != nil && !skip {
+				statement.Rows = append(statement.Rows, qifRow(startLine, fields, req, loc))
+			}
+			fields = nil
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]string)
+			startLine = line
+		}
+		code, value := text[:1], strings.TrimSpace(text[1:])
+		if _, ok := fields[code]; !ok {
+			fields[code] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read statement: %w", err)
+	}
+	// последняя запись без ^ в конце файла
+	if fields != nil && !skip {
+		statement.Rows = append(statement.Rows, qifRow(startLine, fields, req, loc))
+	}
+	return statement, nil
+}
+
+func qifRow(line int, fields map[string]string, req *models.ImportStatementRequest, loc *time.Location) *statementRow {
+	row := &statementRow{Row: line, IgnoreUnknownCategory: true}
+	value := strings.ReplaceAll(strings.ReplaceAll(fields["D"], "'", "/"), " ", "")
+	if value == "" {
+		row.Err = fmt.Errorf("missing date")
+		return row
+	}
+	var err error
+	layouts := qifDateLayouts
+	if req.DateFormat != "" {
+		layouts = []string{dateFormatReplacer.Replace(req.DateFormat)}
+	}
+	for _, layout := range layouts {
+		row.Date, err = time.ParseInLocation(layout, value, loc)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		row.Err = fmt.Errorf("invalid date %q", fields["D"])
+		return row
+	}
+	amount := fields["T"]
+	if amount == "" {
+		amount = fields["U"]
+	}
+	if amount == "" {
+		row.Err = fmt.Errorf("missing amount")
+		return row
+	}
+	row.Amount, err = parseAmount(amount, req.DecimalSeparator)
+	if err != nil {
+		row.Err = err
+		return row
+	}
+	row.Description = joinDescription(fields["P"], fields["M"])
+	if row.Description == "" {
+		row.Err = fmt.Errorf("missing description")
+		return row
+	}
+	// "[Счет]" - перевод в QIF, "Еда:Продукты" - подкатегория
+	category := fields["L"]
+	if category != "" && !strings.HasPrefix(category, "[") {
+		row.Category = strings.TrimSpace(strings.SplitN(category, "/", 2)[0])
+	}
+	return row
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"justTest/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const qifBank = "\ufeff!Type:Bank\r\n" +
+	"D10/ 5'24\r\n" +
+	"T-1,234.56\r\n" +
+	"PWhole Foods\r\n" +
+	"MWeekly groceries\r\n" +
+	"LFood:Groceries\r\n" +
+	"^\r\n" +
+	"D10/15/2024\r\n" +
+	"U2,500.00\r\n" +
+	"PACME Corp\r\n" +
+	"L[Savings]\r\n" +
+	"^\r\n" +
+	"!Type:Cat\r\n" +
+	"NFood\r\n" +
+	"DFood expenses\r\n" +
+	"^\r\n" +
+	"!Type:CCard\r\n" +
+	"D2024-10-20\r\n" +
+	"T-9.99\r\n" +
+	"MStreaming\r\n" +
+	"LSubscriptions/Personal\r\n" +
+	"SSubscriptions\r\n" +
+	"$-9.99\r\n"
+
+func TestParseQIFStatement(t *testing.T) {
+	loc := time.FixedZone("ALMT", 5*60*60)
+	statement, err := parseQIFStatement(strings.NewReader(qifBank), &models.ImportStatementRequest{}, loc)
+	require.NoError(t, err)
+	require.Len(t, statement.Rows, 3)
+
+	row := statement.Rows[0]
+	assert.NoError(t, row.Err)
+	assert.Equal(t, 2, row.Row)
+	assert.True(t, row.IgnoreUnknownCategory)
+	assert.Equal(t, time.Date(2024, 10, 5, 0, 0, 0, 0, loc), row.Date)
+	assert.Equal(t, int64(-123456), row.Amount.Minor)
+	assert.Equal(t, "Whole Foods - Weekly groceries", row.Description)
+	assert.Equal(t, "Food:Groceries", row.Category)
+
+	// перевод в [Счет] - без категории, U вместо T
+	row = statement.Rows[1]
+	assert.NoError(t, row.Err)
+	assert.Equal(t, 8, row.Row)
+	assert.Equal(t, time.Date(2024, 10, 15, 0, 0, 0, 0, loc), row.Date)
+	assert.Equal(t, int64(250000), row.Amount.Minor)
+	assert.Equal(t, "ACME Corp", row.Description)
+	assert.Empty(t, row.Category)
+
+	// записи !Type:Cat пропускаются, последняя запись без ^ учитывается
+	row = statement.Rows[2]
+	assert.NoError(t, row.Err)
+	assert.Equal(t, 18, row.Row)
+	assert.Equal(t, time.Date(2024, 10, 20, 0, 0, 0, 0, loc), row.Date)
+	assert.Equal(t, int64(-999), row.Amount.Minor)
+	assert.Equal(t, "Streaming", row.Description)
+	assert.Equal(t, "Subscriptions", row.Category)
+}
+
+func TestParseQIFStatementDateFormatAndSeparator(t *testing.T) {
+	file := "!Type:Bank\n" +
+		"D05.10.2024\n" +
+		"T-1 234,56\n" +
+		"PMagnum\n" +
+		"^\n"
+	req := &models.ImportStatementRequest{DateFormat: "DD.MM.YYYY", DecimalSeparator: ","}
+	statement, err := parseQIFStatement(strings.NewReader(file), req, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, statement.Rows, 1)
+
+	row := statement.Rows[0]
+	assert.NoError(t, row.Err)
+	assert.Equal(t, time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC), row.Date)
+	assert.Equal(t, int64(-123456), row.Amount.Minor)
+}
+
+func TestParseQIFStatementRowErrors(t *testing.T) {
+	file := "!Type:Bank\n" +
+		"T-1.00\n" +
+		"Pno date\n" +
+		"^\n" +
+		"D2024-13-40\n" +
+		"T-1.00\n" +
+		"Pbad date\n" +
+		"^\n" +
+		"D2024-10-01\n" +
+		"Pno amount\n" +
+		"^\n" +
+		"D2024-10-01\n" +
+		"T1/3\n" +
+		"Pfraction\n" +
+		"^\n" +
+		"D2024-10-01\n" +
+		"T-1.00\n" +
+		"^\n"
+	statement, err := parseQIFStatement(strings.NewReader(file), &models.ImportStatementRequest{}, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, statement.Rows, 5)
+
+	want := []struct {
+		row int
+		err string
+	}{
+		{2, "missing date"},
+		{5, "invalid date"},
+		{9, "missing amount"},
+		{12, "invalid amount"},
+		{16, "missing description"},
+	}
+	for i, w := range want {
+		row := statement.Rows[i]
+		assert.Equal(t, w.row, row.Row)
+		if assert.Error(t, row.Err, "row %d", w.row) {
+			assert.Contains(t, row.Err.Error(), w.err)
+		}
+	}
+}
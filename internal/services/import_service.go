@@ -0,0 +1,362 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"justTest/internal/interfaces"
+	"justTest/internal/models"
+	"justTest/internal/utils"
+	"strings"
+	"time"
+)
+
+// ImportService - импорт банковских выписок в транзакции счета
+type ImportService struct {
+	transactionRepo interfaces.TransactionRepository
+	categoryRepo    interfaces.CategoryRepository
+	accountRepo     interfaces.AccountRepository
+	bankAccService  *BankAccService
+}
+
+func NewImportService(
+	transactionRepo interfaces.TransactionRepository,
+	categoryRepo interfaces.CategoryRepository,
+	accountRepo interfaces.AccountRepository,
+	bankAccService *BankAccService,
+) *ImportService {
+	return &ImportService{
+		transactionRepo: transactionRepo,
+		categoryRepo:    categoryRepo,
+		accountRepo:     accountRepo,
+		bankAccService:  bankAccService,
+	}
+}
+
+// parsedStatement - результат разбора файла выписки любого формата
+type parsedStatement struct {
+	Rows     []*statementRow
+	Currency string // валюта из файла, если формат ее содержит
+	IBAN     string // счет из файла (camt)
+	// остатки из файла (camt), для сверки с балансом счета
+	OpeningBalance *models.Money
+	ClosingBalance *models.Money
+}
+
+// statementRow - строка выписки после разбора, Row - номер строки (записи) в файле
+type statementRow struct {
+	Row         int
+	Date        time.Time
+	Amount      models.Money
+	Description string
+	Category    string
+	// IgnoreUnknownCategory - категория из самого файла (QIF), а не выбранная пользователем колонка
+	IgnoreUnknownCategory bool
+	ExternalID            string // FITID из OFX, AcctSvcrRef из camt
+	Skip                  string // причина пропуска без ошибки (неподтвержденная операция camt)
+	Err                   error
+}
+
+// ImportStatement импортирует выписку (csv, ofx/qfx, qif, camt053/camt052) на счет пользователя одной транзакцией БД
+// строки с уже импортированным FITID или совпадающие с существующими транзакциями счета
+// (дата, сумма, описание) пропускаются
+// bankAccountID = 0 - счет ищется по IBAN из выписки (только camt)
+func (s *ImportService) ImportStatement(userID string, bankAccountID int64, file io.Reader, req *models.ImportStatementRequest) (*models.ImportSummary, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	loc := utils.LoadLocation(account.Timezone)
+
+	statements, err := parseStatement(file, req, loc)
+	if err != nil {
+		return nil, err
+	}
+	bankAccount, statement, err := s.resolveBankAccount(userID, bankAccountID, statements)
+	if err != nil {
+		return nil, err
+	}
+	if !bankAccount.IsActive {
+		return nil, fmt.Errorf("bank account is not active")
+	}
+	bankAccountID = bankAccount.ID
+	if statement.Currency != "" && !strings.EqualFold(statement.Currency, bankAccount.Currency) {
+		return nil, fmt.Errorf("statement currency %s does not match bank account currency %s",
+			statement.Currency, bankAccount.Currency)
+	}
+	rows := statement.Rows
+	categories, err := s.categoryRepo.GetByAccountID(account.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get categories: %w", err)
+	}
+	categoryByName := make(map[string]int64, len(categories))
+	for _, category := range categories {
+		categoryByName[strings.ToLower(strings.TrimSpace(category.Name))] = category.ID
+	}
+
+	summary := &models.ImportSummary{
+		BankAccountID: bankAccountID,
+		ImportedRows:  make([]int, 0),
+		SkippedRows:   make([]*models.ImportRowIssue, 0),
+		FailedRows:    make([]*models.ImportRowIssue, 0),
+	}
+	now := time.Now()
+	valid := make([]*statementRow, 0, len(rows))
+	categoryIDs := make(map[int]*int64)
+	for _, row := range rows {
+		if row.Err == nil && row.Skip != "" {
+			summary.SkippedRows = append(summary.SkippedRows, &models.ImportRowIssue{Row: row.Row, Reason: row.Skip})
+			continue
+		}
+		if row.Err == nil && row.Amount.IsZero() {
+			row.Err = fmt.Errorf("zero amount")
+		}
+		if row.Err == nil && row.Date.After(now) {
+			row.Err = fmt.Errorf("date is in the future")
+		}
+		if row.Err == nil && row.Category != "" {
+			categoryID, ok := findCategory(categoryByName, row.Category)
+			if ok {
+				categoryIDs[row.Row] = &categoryID
+			} else if !row.IgnoreUnknownCategory {
+				row.Err = fmt.Errorf("category %q not found", row.Category)
+			}
+		}
+		if row.Err != nil {
+			summary.FailedRows = append(summary.FailedRows, &models.ImportRowIssue{Row: row.Row, Reason: row.Err.Error()})
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	existing, withoutExternalID, err := s.existingKeys(bankAccountID, valid, loc)
+	if err != nil {
+		return nil, err
+	}
+	externalIDs := make([]string, 0)
+	for _, row := range valid {
+		if row.ExternalID != "" {
+			externalIDs = append(externalIDs, row.ExternalID)
+		}
+	}
+	importedIDs, err := s.transactionRepo.GetExistingExternalIDs(bankAccountID, externalIDs)
+	if err != nil {
+		return nil, err
+	}
+	transactions := make([]*models.Transaction, 0, len(valid))
+	importedRows := make([]int, 0, len(valid))
+	// сумма операций выписки, которые после импорта есть на счете (созданные и дубликаты)
+	var inLedger models.Money
+	for _, row := range valid {
+		inLedger = inLedger.Add(row.Amount)
+		var externalID *string
+		if row.ExternalID != "" {
+			if importedIDs[row.ExternalID] {
+				summary.SkippedRows = append(summary.SkippedRows, &models.ImportRowIssue{Row: row.Row, Reason: "already imported"})
+				continue
+			}
+			importedIDs[row.ExternalID] = true
+			externalID = &row.ExternalID
+		}
+		// строки с FITID сверяются только с транзакциями без него (ручными или из CSV)
+		keys := existing
+		if externalID != nil {
+			keys = withoutExternalID
+		}
+		key := importKey(row.Date, row.Amount, row.Description, loc)
+		if keys[key] > 0 {
+			keys[key]--
+			summary.SkippedRows = append(summary.SkippedRows, &models.ImportRowIssue{Row: row.Row, Reason: "duplicate of existing transaction"})
+			continue
+		}
+		transactionType := "income"
+		if row.Amount.IsNegative() {
+			transactionType = "expense"
+		}
+		transactions = append(transactions, &models.Transaction{
+			BankAccountID:   bankAccountID,
+			CategoryID:      categoryIDs[row.Row],
+			Amount:          row.Amount,
+			Description:     row.Description,
+			TransactionType: transactionType,
+			Date:            row.Date,
+			ExternalID:      externalID,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		})
+		importedRows = append(importedRows, row.Row)
+	}
+	if len(transactions) > 0 {
+		if err := s.transactionRepo.CreateBatch(transactions); err != nil {
+			return nil, fmt.Errorf("import transactions: %w", err)
+		}
+	}
+	summary.ImportedRows = importedRows
+	summary.Imported = len(importedRows)
+	summary.Skipped = len(summary.SkippedRows)
+	summary.Failed = len(summary.FailedRows)
+
+	if statement.OpeningBalance != nil || statement.ClosingBalance != nil {
+		summary.Reconciliation, err = s.reconcile(bankAccountID, statement, inLedger)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+// resolveBankAccount выбирает счет и выписку: по bankAccountID (с проверкой IBAN) или по IBAN из файла
+func (s *ImportService) resolveBankAccount(userID string, bankAccountID int64, statements []*parsedStatement) (*models.BankAccount, *parsedStatement, error) {
+	if bankAccountID > 0 {
+		bankAccount, err := s.bankAccService.GetBankAccount(userID, bankAccountID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(statements) == 1 {
+			statement := statements[0]
+			if statement.IBAN != "" && bankAccount.IBAN != "" && statement.IBAN != bankAccount.IBAN {
+				return nil, nil, fmt.Errorf("statement IBAN %s does not match bank account IBAN %s", statement.IBAN, bankAccount.IBAN)
+			}
+			return bankAccount, statement, nil
+		}
+		for _, statement := range statements {
+			if bankAccount.IBAN != "" && statement.IBAN == bankAccount.IBAN {
+				return bankAccount, statement, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("file contains %d statements and none matches the bank account IBAN", len(statements))
+	}
+
+	if len(statements) != 1 {
+		return nil, nil, fmt.Errorf("file contains %d statements, import it into a selected bank account", len(statements))
+	}
+	statement := statements[0]
+	if statement.IBAN == "" {
+		return nil, nil, fmt.Errorf("statement has no IBAN, select a bank account")
+	}
+	bankAccounts, err := s.bankAccService.GetBankAccountsByAccountID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, bankAccount := range bankAccounts {
+		if bankAccount.IBAN == statement.IBAN {
+			return bankAccount, statement, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no bank account with IBAN %s", statement.IBAN)
+}
+
+// reconcile сравнивает остатки выписки с балансом счета после импорта
+// входящий остаток сверяется с балансом счета без операций этой выписки
+func (s *ImportService) reconcile(bankAccountID int64, statement *parsedStatement, inLedger models.Money) (*models.StatementReconciliation, error) {
+	ledger, err := s.transactionRepo.GetTotalAmountByBankAccountID(bankAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("get bank account balance: %w", err)
+	}
+	reconciliation := &models.StatementReconciliation{
+		IBAN:                 statement.IBAN,
+		OpeningBalance:       statement.OpeningBalance,
+		ClosingBalance:       statement.ClosingBalance,
+		LedgerBalance:        ledger,
+		LedgerOpeningBalance: ledger.Sub(inLedger),
+		IsBalanced:           true,
+	}
+	if statement.OpeningBalance != nil {
+		difference := reconciliation.LedgerOpeningBalance.Sub(*statement.OpeningBalance)
+		reconciliation.OpeningDifference = &difference
+		reconciliation.IsBalanced = reconciliation.IsBalanced && difference.IsZero()
+	}
+	if statement.ClosingBalance != nil {
+		difference := reconciliation.LedgerBalance.Sub(*statement.ClosingBalance)
+		reconciliation.ClosingDifference = &difference
+		reconciliation.IsBalanced = reconciliation.IsBalanced && difference.IsZero()
+	}
+	return reconciliation, nil
+}
+
+// parseStatement выбирает парсер по req.Format, по умолчанию csv
+// несколько выписок в одном файле бывает только у camt
+func parseStatement(file io.Reader, req *models.ImportStatementRequest, loc *time.Location) ([]*parsedStatement, error) {
+	var (
+		statement *parsedStatement
+		err       error
+	)
+	switch strings.ToLower(req.Format) {
+	case "", "csv":
+		var rows []*statementRow
+		rows, err = parseCSVStatement(file, req, loc)
+		statement = &parsedStatement{Rows: rows}
+	case "ofx", "qfx":
+		statement, err = parseOFXStatement(file, loc)
+	case "qif":
+		statement, err = parseQIFStatement(file, req, loc)
+	case "camt053", "camt052":
+		return parseCamtStatements(file, loc)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", req.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []*parsedStatement{statement}, nil
+}
+
+// findCategory ищет категорию по имени, для "Родитель:Подкатегория" - также по подкатегории
+func findCategory(categoryByName map[string]int64, name string) (int64, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if categoryID, ok := categoryByName[name]; ok {
+		return categoryID, true
+	}
+	if idx := strings.LastIndex(name, ":"); idx >= 0 {
+		categoryID, ok := categoryByName[strings.TrimSpace(name[idx+1:])]
+		return categoryID, ok
+	}
+	return 0, false
+}
+
+// existingKeys считает уже существующие транзакции счета за период выписки по ключу дата+сумма+описание:
+// все и только без external_id
+func (s *ImportService) existingKeys(bankAccountID int64, rows []*statementRow, loc *time.Location) (map[string]int, map[string]int, error) {
+	keys := make(map[string]int)
+	withoutExternalID := make(map[string]int)
+	if len(rows) == 0 {
+		return keys, withoutExternalID, nil
+	}
+	first, last := rows[0].Date, rows[0].Date
+	for _, row := range rows {
+		if row.Date.Before(first) {
+			first = row.Date
+		}
+		if row.Date.After(last) {
+			last = row.Date
+		}
+	}
+	first = first.In(loc)
+	last = last.In(loc)
+	start := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(last.Year(), last.Month(), last.Day()+1, 0, 0, 0, 0, loc)
+
+	transactions, err := s.transactionRepo.GetByBankAccountAndDateRange(bankAccountID, start, end)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get existing transactions: %w", err)
+	}
+	for _, transaction := range transactions {
+		key := importKey(transaction.Date, transaction.Amount, transaction.Description, loc)
+		keys[key]++
+		if transaction.ExternalID == nil {
+			withoutExternalID[key]++
+		}
+	}
+	return keys, withoutExternalID, nil
+}
+
+// importKey - ключ сверки: календарный день в таймзоне аккаунта, сумма в копейках и описание
+func importKey(date time.Time, amount models.Money, description string, loc *time.Location) string {
+	return fmt.Sprintf("%s|%d|%s",
+		date.In(loc).Format("2006-01-02"),
+		amount.Minor,
+		strings.ToLower(strings.TrimSpace(description)),
+	)
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"fmt"
+	"justTest/internal/interfaces"
+	"justTest/internal/models"
+	"justTest/internal/models/events"
+	"justTest/internal/utils"
+	"log"
+	"time"
+)
+
+// на сколько месяцев вперед показываются платежи по рассрочкам
+const (
+	defaultObligationMonths = 12
+	maxObligationMonths     = 60
+)
+
+// InstallmentService - рассрочки: платежи создаются сразу запланированными транзакциями,
+// планировщик делает их обычными в день платежа (в таймзоне аккаунта)
+type InstallmentService struct {
+	installmentRepo interfaces.InstallmentRepository
+	bankAccountRepo interfaces.BankAccountRepository
+	categoryRepo    interfaces.CategoryRepository
+	accountRepo     interfaces.AccountRepository
+	rateService     *RateService
+	publisher       interface{}
+}
+
+func NewInstallmentService(
+	installmentRepo interfaces.InstallmentRepository,
+	bankAccountRepo interfaces.BankAccountRepository,
+	categoryRepo interfaces.CategoryRepository,
+	accountRepo interfaces.AccountRepository,
+	rateService *RateService,
+	publisher interface{},
+) *InstallmentService {
+	return &InstallmentService{
+		installmentRepo: installmentRepo,
+		bankAccountRepo: bankAccountRepo,
+		categoryRepo:    categoryRepo,
+		accountRepo:     accountRepo,
+		rateService:     rateService,
+		publisher:       publisher,
+	}
+}
+
+// CreatePlan создает рассрочку и запланированные транзакции на все еще не внесенные платежи
+func (s *InstallmentService) CreatePlan(userID string, req *models.CreateInstallmentPlanRequest) (*models.InstallmentPlan, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if req.PaymentsCount <= 0 {
+		return nil, fmt.Errorf("invalid payments count")
+	}
+	if req.PaidPayments < 0 || req.PaidPayments >= req.PaymentsCount {
+		return nil, fmt.Errorf("paid payments must be less than payments count")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	bankAccount, err := s.bankAccountRepo.GetByBankAccountID(req.BankAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("bank account not found: %w", err)
+	}
+	if bankAccount.AccountID != account.ID {
+		return nil, fmt.Errorf("bank account does not belong to user")
+	}
+	if !bankAccount.IsActive {
+		return nil, fmt.Errorf("bank account is not active")
+	}
+	if req.CategoryID != nil {
+		category, err := s.categoryRepo.GetByID(*req.CategoryID)
+		if err != nil {
+			return nil, fmt.Errorf("category not found: %w", err)
+		}
+		if category.AccountID != account.ID {
+			return nil, fmt.Errorf("category does not belong to user")
+		}
+	}
+	totalAmount := req.TotalAmount.WithCurrency(bankAccount.Currency)
+	if !totalAmount.IsPositive() {
+		return nil, fmt.Errorf("total amount must be positive")
+	}
+	fee := models.NewMoney(0, bankAccount.Currency)
+	if req.Fee != nil {
+		fee = req.Fee.WithCurrency(bankAccount.Currency)
+		if fee.IsNegative() {
+			return nil, fmt.Errorf("fee must not be negative")
+		}
+	}
+	firstPaymentDate, err := time.Parse("2006-01-02", req.FirstPaymentDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid first payment date, expected YYYY-MM-DD")
+	}
+
+	now := time.Now()
+	plan := &models.InstallmentPlan{
+		AccountID:        account.ID,
+		BankAccountID:    bankAccount.ID,
+		CategoryID:       req.CategoryID,
+		Description:      req.Description,
+		TotalAmount:      totalAmount,
+		Fee:              fee,
+		PaymentsCount:    req.PaymentsCount,
+		PaidPayments:     req.PaidPayments,
+		FirstPaymentDate: firstPaymentDate,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	// день платежа - полночь в таймзоне аккаунта, до нее платеж остается запланированным
+	loc := utils.LoadLocation(account.Timezone)
+	amounts := splitInstallment(totalAmount.Add(fee), req.PaymentsCount)
+	payments := make([]*models.Transaction, 0, req.PaymentsCount-req.PaidPayments)
+	for i := req.PaidPayments; i < req.PaymentsCount; i++ {
+		dueDate := installmentDueDate(firstPaymentDate, i)
+		payments = append(payments, &models.Transaction{
+			BankAccountID:   bankAccount.ID,
+			CategoryID:      req.CategoryID,
+			Amount:          amounts[i].Neg(),
+			Description:     fmt.Sprintf("%s (%d/%d)", req.Description, i+1, req.PaymentsCount),
+			TransactionType: "expense",
+			Date:            time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, loc),
+			CreatedAt:       now,
+			UpdatedAt:       now,
+			IsPlanned:       true,
+		})
+	}
+	created, err := s.installmentRepo.Create(plan, payments)
+	if err != nil {
+		return nil, fmt.Errorf("create installment plan: %w", err)
+	}
+	// платежи с уже наступившей датой проводятся сразу
+	if !payments[0].Date.After(now) {
+		if err := s.ProcessDue(now); err != nil {
+			log.Printf("[InstallmentService] Error processing due payments: %v", err)
+		}
+	}
+	return s.withPayments(created)
+}
+
+func (s *InstallmentService) GetPlans(userID string) ([]*models.InstallmentPlan, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	return s.installmentRepo.GetByAccountID(account.ID)
+}
+
+// GetPlan - рассрочка с графиком платежей и остатком долга
+func (s *InstallmentService) GetPlan(userID string, planID int64) (*models.InstallmentPlan, error) {
+	plan, _, err := s.getOwnedPlan(userID, planID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withPayments(plan)
+}
+
+// DeletePlan удаляет рассрочку и ее будущие платежи, уже проведенные платежи остаются
+func (s *InstallmentService) DeletePlan(userID string, planID int64) error {
+	if _, _, err := s.getOwnedPlan(userID, planID); err != nil {
+		return err
+	}
+	return s.installmentRepo.Delete(planID)
+}
+
+// GetObligations - еще не наступившие платежи по всем рассрочкам по месяцам, начиная с текущего
+// итог месяца - в базовой валюте по сегодняшнему курсу, месяцы без платежей не возвращаются
+func (s *InstallmentService) GetObligations(userID string, months int) ([]*models.InstallmentObligations, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if months <= 0 {
+		months = defaultObligationMonths
+	}
+	if months > maxObligationMonths {
+		return nil, fmt.Errorf("months must be between 1 and %d", maxObligationMonths)
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	loc := utils.LoadLocation(account.Timezone)
+	now := time.Now().In(loc)
+	start, _ := utils.MonthRange(now.Year(), int(now.Month()), loc)
+	end := start.AddDate(0, months, 0)
+
+	payments, err := s.installmentRepo.GetOutstandingPayments(account.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	currency := baseCurrency(account)
+	result := make([]*models.InstallmentObligations, 0)
+	var current *models.InstallmentObligations
+	for _, payment := range payments {
+		due := payment.DueDate.In(loc)
+		if current == nil || current.Year != due.Year() || current.Month != int(due.Month()) {
+			current = &models.InstallmentObligations{
+				Year:     due.Year(),
+				Month:    int(due.Month()),
+				Currency: currency,
+				Total:    models.NewMoney(0, currency),
+				Payments: make([]*models.InstallmentPayment, 0),
+			}
+			result = append(result, current)
+		}
+		converted, _, err := s.rateService.Convert(payment.Amount.Abs(), payment.Currency, currency, now)
+		if err != nil {
+			return nil, err
+		}
+		current.Total = current.Total.Add(converted)
+		current.Payments = append(current.Payments, payment)
+	}
+	return result, nil
+}
+
+// ProcessDue проводит все наступившие платежи по рассрочкам, включая пропущенные пока сервер не работал
+func (s *InstallmentService) ProcessDue(now time.Time) error {
+	payments, err := s.installmentRepo.RealizeDuePayments(now)
+	if err != nil {
+		return fmt.Errorf("realize installment payments: %w", err)
+	}
+	users := make(map[int64]string)
+	for _, payment := range payments {
+		log.Printf("[InstallmentService] Realized payment %d of installment plan %d", payment.TransactionID, payment.PlanID)
+		if payment.CategoryID == nil {
+			continue
+		}
+		userID, ok := users[payment.PlanID]
+		if !ok {
+			userID, err = s.planUserID(payment.PlanID)
+			if err != nil {
+				log.Printf("[InstallmentService] Error getting owner of installment plan %d: %v", payment.PlanID, err)
+				continue
+			}
+			users[payment.PlanID] = userID
+		}
+		s.publishTransactionCreated(userID, payment)
+	}
+	return nil
+}
+
+func (s *InstallmentService) planUserID(planID int64) (string, error) {
+	plan, err := s.installmentRepo.GetByID(planID)
+	if err != nil {
+		return "", err
+	}
+	account, err := s.accountRepo.GetByID(plan.AccountID)
+	if err != nil {
+		return "", err
+	}
+	return account.UserID, nil
+}
+
+// publishTransactionCreated - проведенный платеж проверяется по бюджету как обычная трата
+func (s *InstallmentService) publishTransactionCreated(userID string, payment *models.InstallmentPayment) {
+	if s.publisher == nil {
+		return
+	}
+	if publisher, ok := s.publisher.(interface {
+		PublishTransactionCreated(events.TransactionCreatedEvent) error
+	}); ok {
+		err := publisher.PublishTransactionCreated(events.TransactionCreatedEvent{
+			TransactionID: payment.TransactionID,
+			UserID:        userID,
+			CategoryID:    *payment.CategoryID,
+			Amount:        payment.Amount,
+			Description:   payment.Description,
+			Date:          payment.DueDate,
+			Timestamp:     time.Now(),
+		})
+		if err != nil {
+			log.Printf("[InstallmentService] Error publishing TransactionCreated event: %v", err)
+		}
+	}
+}
+
+func (s *InstallmentService) getOwnedPlan(userID string, planID int64) (*models.InstallmentPlan, *models.Account, error) {
+	if userID == "" {
+		return nil, nil, fmt.Errorf("invalid user id")
+	}
+	if planID <= 0 {
+		return nil, nil, fmt.Errorf("invalid installment plan id")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get account: %w", err)
+	}
+	plan, err := s.installmentRepo.GetByID(planID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if plan.AccountID != account.ID {
+		return nil, nil, fmt.Errorf("installment plan does not belong to user")
+	}
+	return plan, account, nil
+}
+
+// withPayments добавляет к рассрочке график платежей и сумму еще не наступивших
+func (s *InstallmentService) withPayments(plan *models.InstallmentPlan) (*models.InstallmentPlan, error) {
+	payments, err := s.installmentRepo.GetPayments(plan.ID)
+	if err != nil {
+		return nil, err
+	}
+	remaining := models.NewMoney(0, plan.TotalAmount.Currency)
+	for _, payment := range payments {
+		if !payment.IsPaid {
+			remaining = remaining.Add(payment.Amount.Abs())
+		}
+	}
+	plan.Payments = payments
+	plan.Remaining = &remaining
+	return plan, nil
+}
+
+// splitInstallment делит сумму на count равных платежей в точности валюты,
+// остаток от деления добавляется по единице к первым платежам
+func splitInstallment(total models.Money, count int) []models.Money {
+	step := int64(1)
+	for i := models.CurrencyDecimals(total.Currency); i < 2; i++ {
+		step *= 10
+	}
+	units := total.Minor / step
+	base, remainder := units/int64(count), units%int64(count)
+	amounts := make([]models.Money, count)
+	for i := range amounts {
+		payment := base
+		if int64(i) < remainder {
+			payment++
+		}
+		amounts[i] = models.NewMoney(payment*step, total.Currency)
+	}
+	return amounts
+}
+
+// installmentDueDate - дата платежа с номером number (с нуля): каждый месяц в день первого платежа,
+// в коротких месяцах - последний день месяца
+func installmentDueDate(first time.Time, number int) time.Time {
+	return clampDay(first.Year(), first.Month()+time.Month(number), first.Day())
+}
@@ -49,7 +49,7 @@ func (s *NotificationService) GetSettings(userID string) (*models.UserNotificati
 			BudgetAlertsEnabled:  true,
 			BalanceAlertsEnabled: true,
 			BudgetWarningPercent: 80,
-			LowBalanceThreshold:  1000,
+			LowBalanceThreshold:  models.NewMoney(100000, ""),
 			PreferredChannel:     "email",
 		}
 		if err := s.settingsRepo.SaveSettings(defaultSettings); err != nil {
@@ -66,6 +66,9 @@ func (s *NotificationService) SaveSettings(settings *models.UserNotificationSett
 	if settings == nil || settings.UserID == "" {
 		return nil, errors.New("invalid settings")
 	}
+	if settings.LowBalanceThreshold.IsNegative() {
+		return nil, errors.New("low balance threshold must not be negative")
+	}
 	existing, err := s.settingsRepo.GetSettings(settings.UserID)
 	if err == sql.ErrNoRows {
 		settings.CreatedAt = time.Now()
@@ -96,15 +99,19 @@ func (s *NotificationService) HandleLowBalance(event events.LowBalanceEvent) err
 	if !settings.BalanceAlertsEnabled {
 		return nil
 	}
-	if event.CurrentBalance >= settings.LowBalanceThreshold {
+	if event.CurrentBalance.Cmp(settings.LowBalanceThreshold) >= 0 {
 		return nil
 	}
 
+	message := "Баланс по счету " + event.AccountName + " ниже порога"
+	if event.IsCredit {
+		message = "Доступный кредитный лимит по счету " + event.AccountName + " ниже порога"
+	}
 	n := &models.Notification{
 		UserID:    event.UserID,
 		Type:      "low_balance",
 		Title:     "Низкий баланс",
-		Message:   "Баланс по счету " + event.AccountName + " ниже порога",
+		Message:   message,
 		Data:      map[string]interface{}{"bank_account_id": event.BankAccountID, "current_balance": event.CurrentBalance, "threshold": settings.LowBalanceThreshold},
 		IsRead:    false,
 		Priority:  "high",
@@ -114,8 +121,114 @@ func (s *NotificationService) HandleLowBalance(event events.LowBalanceEvent) err
 	if err := s.notificationRepo.SaveNotification(n); err != nil {
 		return err
 	}
+	s.publishNotification(n)
+	return nil
+}
+
+// HandlePaymentDue - напоминание о сроке платежа по кредитному счету, отключается вместе с уведомлениями о балансе
+func (s *NotificationService) HandlePaymentDue(event events.PaymentDueEvent) error {
+	settings, err := s.GetSettings(event.UserID)
+	if err != nil {
+		return err
+	}
+	if !settings.BalanceAlertsEnabled {
+		return nil
+	}
+	message := fmt.Sprintf(
+		"По счету %s до %s нужно погасить %s, минимальный платеж %s",
+		event.AccountName,
+		event.DueDate.Format("02.01.2006"),
+		event.StatementBalance,
+		event.MinimumPayment,
+	)
+	n := &models.Notification{
+		UserID:  event.UserID,
+		Type:    "payment_due",
+		Title:   "Срок платежа по кредиту",
+		Message: message,
+		Data: map[string]interface{}{
+			"bank_account_id":   event.BankAccountID,
+			"statement_balance": event.StatementBalance,
+			"minimum_payment":   event.MinimumPayment,
+			"due_date":          event.DueDate.Format("2006-01-02"),
+		},
+		IsRead:    false,
+		Priority:  "high",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.notificationRepo.SaveNotification(n); err != nil {
+		return fmt.Errorf("save notification: %w", err)
+	}
+	s.publishNotification(n)
+	return nil
+}
+
+// HandleDebtDue - напоминание о сроке возврата долга, приходит всегда
+func (s *NotificationService) HandleDebtDue(event events.DebtDueEvent) error {
+	title := "Срок возврата долга"
+	message := fmt.Sprintf("%s должен вернуть %s до %s", event.CounterpartyName, event.Outstanding, event.DueDate.Format("02.01.2006"))
+	if event.Direction == "borrowed" {
+		message = fmt.Sprintf("Нужно вернуть %s: %s до %s", event.CounterpartyName, event.Outstanding, event.DueDate.Format("02.01.2006"))
+	}
+	n := &models.Notification{
+		UserID:  event.UserID,
+		Type:    "debt_due",
+		Title:   title,
+		Message: message,
+		Data: map[string]interface{}{
+			"debt_id":           event.DebtID,
+			"counterparty_name": event.CounterpartyName,
+			"direction":         event.Direction,
+			"outstanding":       event.Outstanding,
+			"due_date":          event.DueDate.Format("2006-01-02"),
+		},
+		IsRead:    false,
+		Priority:  "high",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.notificationRepo.SaveNotification(n); err != nil {
+		return fmt.Errorf("save notification: %w", err)
+	}
+	s.publishNotification(n)
+	return nil
+}
+
+// HandleGoalMilestone - цель накоплений достигла рубежа, уведомление приходит всегда
+func (s *NotificationService) HandleGoalMilestone(event events.GoalMilestoneEvent) error {
+	title := fmt.Sprintf("Цель накоплена на %d%%", event.Milestone)
+	message := fmt.Sprintf("Цель '%s': накоплено %s из %s", event.GoalName, event.SavedAmount, event.TargetAmount)
+	if event.Milestone >= 100 {
+		title = "Цель достигнута"
+		message = fmt.Sprintf("Цель '%s' достигнута: накоплено %s", event.GoalName, event.SavedAmount)
+	}
+	n := &models.Notification{
+		UserID:  event.UserID,
+		Type:    "goal_milestone",
+		Title:   title,
+		Message: message,
+		Data: map[string]interface{}{
+			"goal_id":       event.GoalID,
+			"goal_name":     event.GoalName,
+			"milestone":     event.Milestone,
+			"saved_amount":  event.SavedAmount,
+			"target_amount": event.TargetAmount,
+		},
+		IsRead:    false,
+		Priority:  "medium",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.notificationRepo.SaveNotification(n); err != nil {
+		return fmt.Errorf("save notification: %w", err)
+	}
+	s.publishNotification(n)
+	return nil
+}
 
-	// Опционально — отправить в общую очередь "notification"
+// publishNotification опционально отправляет уведомление в общую очередь "notification"
+func (s *NotificationService) publishNotification(n *models.Notification) {
 	if s.publisher != nil {
 		if publisher, ok := s.publisher.(interface {
 			PublishNotification(events.NotificationEvent) error
@@ -131,7 +244,6 @@ func (s *NotificationService) HandleLowBalance(event events.LowBalanceEvent) err
 			})
 		}
 	}
-	return nil
 }
 
 // TODO: дедупликация, как нибудь потом надо будет добавить
@@ -146,7 +258,7 @@ func (s *NotificationService) HandleBudgetExceeded(event events.BudgetExceededEv
 	}
 
 	message := fmt.Sprintf(
-		"Бюджет '%s' превышен на %.2f. Потрачено: %.2f из %.2f",
+		"Бюджет '%s' превышен на %s. Потрачено: %s из %s",
 		event.BudgetName,
 		event.ExcessAmount,
 		event.SpentAmount,
@@ -197,7 +309,7 @@ func (s *NotificationService) HandleBudgetWarning(event events.BudgetWarningEven
 		return nil
 	}
 	message := fmt.Sprintf(
-		"Бюджет '%s' использован на %.0f%%. Потрачено: %.2f из %.2f",
+		"Бюджет '%s' использован на %.0f%%. Потрачено: %s из %s",
 		event.BudgetName,
 		event.WarningPercent,
 		event.SpentAmount,
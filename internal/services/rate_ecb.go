@@ -0,0 +1,51 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"justTest/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ecbEnvelope - курсы ЕЦБ (eurofxref-daily.xml, eurofxref-hist.xml): Cube/Cube[@time]/Cube[@currency, @rate]
+// курс - сколько единиц валюты за 1 EUR
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+func parseECBRates(file io.Reader) ([]*models.CurrencyRate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(file).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("invalid ecb xml: %w", err)
+	}
+	rates := make([]*models.CurrencyRate, 0)
+	for _, day := range envelope.Days {
+		date, err := time.Parse("2006-01-02", day.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ecb date %q", day.Time)
+		}
+		for _, item := range day.Rates {
+			rate, err := strconv.ParseFloat(strings.TrimSpace(item.Rate), 64)
+			if err != nil || rate <= 0 {
+				return nil, fmt.Errorf("invalid ecb rate %q for %s", item.Rate, item.Currency)
+			}
+			rates = append(rates, &models.CurrencyRate{
+				FromCurrency: "EUR",
+				ToCurrency:   strings.ToUpper(strings.TrimSpace(item.Currency)),
+				Rate:         rate,
+				RateDate:     date,
+				Source:       "ecb",
+			})
+		}
+	}
+	return rates, nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ecbHist - фрагмент eurofxref-hist.xml за два дня
+const ecbHist = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time="2024-10-04">
+			<Cube currency="USD" rate="1.0965"/>
+			<Cube currency="JPY" rate="161.48"/>
+			<Cube currency="gbp" rate=" 0.83713 "/>
+		</Cube>
+		<Cube time="2024-10-03">
+			<Cube currency="USD" rate="1.1029"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>
+`
+
+func TestParseECBRates(t *testing.T) {
+	rates, err := parseECBRates(strings.NewReader(ecbHist))
+	require.NoError(t, err)
+	require.Len(t, rates, 4)
+
+	want := []struct {
+		to   string
+		rate float64
+		date time.Time
+	}{
+		{"USD", 1.0965, time.Date(2024, 10, 4, 0, 0, 0, 0, time.UTC)},
+		{"JPY", 161.48, time.Date(2024, 10, 4, 0, 0, 0, 0, time.UTC)},
+		{"GBP", 0.83713, time.Date(2024, 10, 4, 0, 0, 0, 0, time.UTC)},
+		{"USD", 1.1029, time.Date(2024, 10, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	for i, w := range want {
+		assert.Equal(t, "EUR", rates[i].FromCurrency)
+		assert.Equal(t, w.to, rates[i].ToCurrency)
+		assert.Equal(t, w.rate, rates[i].Rate)
+		assert.Equal(t, w.date, rates[i].RateDate)
+		assert.Equal(t, "ecb", rates[i].Source)
+	}
+}
+
+func TestParseECBRatesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		err  string
+	}{
+		{"not xml", "EUR,USD\n", "invalid ecb xml"},
+		{"invalid date", `<Envelope><Cube><Cube time="04.10.2024"><Cube currency="USD" rate="1.1"/></Cube></Cube></Envelope>`, "invalid ecb date"},
+		{"invalid rate", `<Envelope><Cube><Cube time="2024-10-04"><Cube currency="USD" rate="n/a"/></Cube></Cube></Envelope>`, "invalid ecb rate"},
+		{"zero rate", `<Envelope><Cube><Cube time="2024-10-04"><Cube currency="USD" rate="0"/></Cube></Cube></Envelope>`, "invalid ecb rate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseECBRates(strings.NewReader(tt.file))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
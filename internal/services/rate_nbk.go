@@ -0,0 +1,56 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"justTest/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nbkRates - официальные курсы Нацбанка РК (get_rates.cfm): rates/date и rates/item
+// description - сколько тенге за quant единиц валюты title
+type nbkRates struct {
+	Date  string `xml:"date"`
+	Items []struct {
+		Currency string `xml:"title"`
+		Rate     string `xml:"description"`
+		Quant    string `xml:"quant"`
+	} `xml:"item"`
+}
+
+func parseNBKRates(file io.Reader) ([]*models.CurrencyRate, error) {
+	var document nbkRates
+	if err := xml.NewDecoder(file).Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid nbk xml: %w", err)
+	}
+	date, err := time.Parse("02.01.2006", strings.TrimSpace(document.Date))
+	if err != nil {
+		return nil, fmt.Errorf("invalid nbk date %q", document.Date)
+	}
+	rates := make([]*models.CurrencyRate, 0, len(document.Items))
+	for _, item := range document.Items {
+		currency := strings.ToUpper(strings.TrimSpace(item.Currency))
+		rate, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(item.Rate), ",", ".", 1), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid nbk rate %q for %s", item.Rate, currency)
+		}
+		quant := 1.0
+		if value := strings.TrimSpace(item.Quant); value != "" {
+			quant, err = strconv.ParseFloat(value, 64)
+			if err != nil || quant <= 0 {
+				return nil, fmt.Errorf("invalid nbk quant %q for %s", item.Quant, currency)
+			}
+		}
+		rates = append(rates, &models.CurrencyRate{
+			FromCurrency: currency,
+			ToCurrency:   "KZT",
+			Rate:         rate / quant,
+			RateDate:     date,
+			Source:       "nbk",
+		})
+	}
+	return rates, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nbkDaily - фрагмент get_rates.cfm, у JPY котировка за 10 единиц
+const nbkDaily = `<?xml version="1.0" encoding="utf-8"?>
+<rates>
+	<generator>nationalbank.kz</generator>
+	<title>Official exchange rates of National Bank of Kazakhstan</title>
+	<date>04.10.2024</date>
+	<item>
+		<fullname>ДОЛЛАР США</fullname>
+		<title>USD</title>
+		<description>481.27</description>
+		<quant>1</quant>
+		<index>UP</index>
+		<change>+1.44</change>
+	</item>
+	<item>
+		<fullname>ЕВРО</fullname>
+		<title>eur</title>
+		<description>528,99</description>
+		<quant>1</quant>
+	</item>
+	<item>
+		<fullname>ЙЕНА</fullname>
+		<title>JPY</title>
+		<description>32.75</description>
+		<quant>10</quant>
+	</item>
+	<item>
+		<fullname>РУБЛЬ</fullname>
+		<title>RUB</title>
+		<description>5.07</description>
+	</item>
+</rates>
+`
+
+func TestParseNBKRates(t *testing.T) {
+	rates, err := parseNBKRates(strings.NewReader(nbkDaily))
+	require.NoError(t, err)
+	require.Len(t, rates, 4)
+
+	date := time.Date(2024, 10, 4, 0, 0, 0, 0, time.UTC)
+	want := []struct {
+		from string
+		rate float64
+	}{
+		{"USD", 481.27},
+		{"EUR", 528.99},
+		{"JPY", 3.275},
+		{"RUB", 5.07},
+	}
+	for i, w := range want {
+		assert.Equal(t, w.from, rates[i].FromCurrency)
+		assert.Equal(t, "KZT", rates[i].ToCurrency)
+		assert.InDelta(t, w.rate, rates[i].Rate, 1e-9)
+		assert.Equal(t, date, rates[i].RateDate)
+		assert.Equal(t, "nbk", rates[i].Source)
+	}
+}
+
+func TestParseNBKRatesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		err  string
+	}{
+		{"not xml", "USD;481.27\n", "invalid nbk xml"},
+		{"invalid date", `<rates><date>2024-10-04</date></rates>`, "invalid nbk date"},
+		{"invalid rate", `<rates><date>04.10.2024</date><item><title>USD</title><description>-</description></item></rates>`, "invalid nbk rate"},
+		{"invalid quant", `<rates><date>04.10.2024</date><item><title>JPY</title><description>32.75</description><quant>0</quant></item></rates>`, "invalid nbk quant"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseNBKRates(strings.NewReader(tt.file))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"justTest/internal/interfaces"
+	"justTest/internal/models"
+	"strings"
+	"time"
+)
+
+// defaultBaseCurrency - валюта отчетов и бюджетов, если у аккаунта не задана базовая
+const defaultBaseCurrency = "KZT"
+
+// ratePivots - опорные валюты для кросс-курсов: ЕЦБ публикует курсы к EUR, Нацбанк РК - к KZT
+var ratePivots = []string{"EUR", "KZT", "USD"}
+
+type RateService struct {
+	rateRepo interfaces.CurrencyRateRepository
+}
+
+func NewRateService(rateRepo interfaces.CurrencyRateRepository) *RateService {
+	return &RateService{
+		rateRepo: rateRepo,
+	}
+}
+
+// ImportRates загружает файл курсов: source "ecb" (eurofxref XML) или "nbk" (XML Нацбанка РК)
+// возвращает количество сохраненных курсов
+func (s *RateService) ImportRates(source string, file io.Reader) (int, error) {
+	var (
+		rates []*models.CurrencyRate
+		err   error
+	)
+	switch strings.ToLower(source) {
+	case "ecb":
+		rates, err = parseECBRates(file)
+	case "nbk":
+		rates, err = parseNBKRates(file)
+	default:
+		return 0, fmt.Errorf("unsupported rate source: %s", source)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(rates) == 0 {
+		return 0, fmt.Errorf("no rates found in file")
+	}
+	if err := s.rateRepo.SaveRates(rates); err != nil {
+		return 0, err
+	}
+	return len(rates), nil
+}
+
+// GetRate - курс from -> to на дату date: прямой, обратный или кросс-курс через опорную валюту
+// берется последний известный курс не позже date
+func (s *RateService) GetRate(fromCurrency, toCurrency string, date time.Time) (*models.CurrencyRateQuote, error) {
+	from := strings.ToUpper(strings.TrimSpace(fromCurrency))
+	to := strings.ToUpper(strings.TrimSpace(toCurrency))
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("invalid currency")
+	}
+	quote := &models.CurrencyRateQuote{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Date:         date,
+		Rates:        make([]*models.CurrencyRate, 0, 2),
+	}
+	if from == to {
+		quote.Rate = 1
+		return quote, nil
+	}
+	rate, used, err := s.pairRate(from, to, date)
+	if err == nil {
+		quote.Rate = rate
+		quote.Rates = append(quote.Rates, used)
+		return quote, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+	for _, pivot := range ratePivots {
+		if pivot == from || pivot == to {
+			continue
+		}
+		first, firstUsed, err := s.pairRate(from, pivot, date)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		second, secondUsed, err := s.pairRate(pivot, to, date)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		quote.Rate = first * second
+		quote.Pivot = pivot
+		quote.Rates = append(quote.Rates, firstUsed, secondUsed)
+		return quote, nil
+	}
+	return nil, fmt.Errorf("no exchange rate for %s to %s on %s", from, to, date.Format("2006-01-02"))
+}
+
+// Convert пересчитывает сумму в валюту toCurrency по курсу на дату date
+func (s *RateService) Convert(amount models.Money, fromCurrency, toCurrency string, date time.Time) (models.Money, *models.CurrencyRateQuote, error) {
+	quote, err := s.GetRate(fromCurrency, toCurrency, date)
+	if err != nil {
+		return models.Money{}, nil, err
+	}
+	converted, err := amount.Convert(quote.Rate, quote.ToCurrency)
+	if err != nil {
+		return models.Money{}, nil, err
+	}
+	return converted, quote, nil
+}
+
+// pairRate - курс пары из прямой или обратной котировки, из двух берется более свежая
+func (s *RateService) pairRate(from, to string, date time.Time) (float64, *models.CurrencyRate, error) {
+	direct, err := s.rateRepo.GetRateOnDate(from, to, date)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, nil, err
+	}
+	inverse, err := s.rateRepo.GetRateOnDate(to, from, date)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, nil, err
+	}
+	switch {
+	case direct != nil && (inverse == nil || !inverse.RateDate.After(direct.RateDate)):
+		return direct.Rate, direct, nil
+	case inverse != nil:
+		return 1 / inverse.Rate, inverse, nil
+	default:
+		return 0, nil, sql.ErrNoRows
+	}
+}
+
+// baseCurrency - валюта, в которую сводятся бюджеты и отчеты аккаунта
+func baseCurrency(account *models.Account) string {
+	if account.BaseCurrency != "" {
+		return account.BaseCurrency
+	}
+	return defaultBaseCurrency
+}
+
+// conversionDate - дата курса для периода [start, end): последний день периода, но не позже сегодня
+func conversionDate(end time.Time) time.Time {
+	date := end.AddDate(0, 0, -1)
+	if now := time.Now(); date.After(now) {
+		return now
+	}
+	return date
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"database/sql"
+	"justTest/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRateRepo - курсы в памяти, как в currency_rates: последний курс пары не позже даты
+type memoryRateRepo struct {
+	rates []*models.CurrencyRate
+}
+
+func (r *memoryRateRepo) SaveRates(rates []*models.CurrencyRate) error {
+	r.rates = append(r.rates, rates...)
+	return nil
+}
+
+func (r *memoryRateRepo) GetRateOnDate(fromCurrency, toCurrency string, date time.Time) (*models.CurrencyRate, error) {
+	var found *models.CurrencyRate
+	for _, rate := range r.rates {
+		if rate.FromCurrency != fromCurrency || rate.ToCurrency != toCurrency || rate.RateDate.After(date) {
+			continue
+		}
+		if found == nil || rate.RateDate.After(found.RateDate) {
+			found = rate
+		}
+	}
+	if found == nil {
+		return nil, sql.ErrNoRows
+	}
+	return found, nil
+}
+
+func rateOn(from, to string, rate float64, day int) *models.CurrencyRate {
+	return &models.CurrencyRate{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         rate,
+		RateDate:     time.Date(2024, 10, day, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestRateServiceGetRate(t *testing.T) {
+	repo := &memoryRateRepo{rates: []*models.CurrencyRate{
+		rateOn("EUR", "USD", 1.1029, 3),
+		rateOn("EUR", "USD", 1.0965, 4),
+		rateOn("EUR", "GBP", 0.8, 4),
+		rateOn("USD", "KZT", 481.27, 4),
+		rateOn("RUB", "KZT", 5.0, 4),
+		rateOn("CNY", "USD", 0.14, 4),
+		rateOn("CHF", "JPY", 170.0, 4),
+		// свежий обратный курс важнее старого прямого
+		rateOn("AUD", "NZD", 1.1, 1),
+		rateOn("NZD", "AUD", 0.8, 4),
+	}}
+	service := NewRateService(repo)
+	date := time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		from  string
+		to    string
+		date  time.Time
+		rate  float64
+		pivot string
+		used  int
+	}{
+		{"same currency", "KZT", "kzt", date, 1, "", 0},
+		{"direct", "EUR", "USD", date, 1.0965, "", 1},
+		{"direct on earlier date", " eur ", "usd", time.Date(2024, 10, 3, 12, 0, 0, 0, time.UTC), 1.1029, "", 1},
+		{"inverse", "USD", "EUR", date, 1 / 1.0965, "", 1},
+		{"newer inverse wins", "AUD", "NZD", date, 1 / 0.8, "", 1},
+		{"eur pivot", "USD", "GBP", date, 0.8 / 1.0965, "EUR", 2},
+		{"kzt pivot", "RUB", "USD", date, 5.0 / 481.27, "KZT", 2},
+		{"usd pivot", "CNY", "KZT", date, 0.14 * 481.27, "USD", 2},
+		{"eur and usd pivots", "EUR", "KZT", date, 1.0965 * 481.27, "USD", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := service.GetRate(tt.from, tt.to, tt.date)
+			require.NoError(t, err)
+			assert.Equal(t, strings.ToUpper(strings.TrimSpace(tt.from)), quote.FromCurrency)
+			assert.Equal(t, strings.ToUpper(strings.TrimSpace(tt.to)), quote.ToCurrency)
+			assert.InDelta(t, tt.rate, quote.Rate, 1e-9)
+			assert.Equal(t, tt.pivot, quote.Pivot)
+			assert.Len(t, quote.Rates, tt.used)
+		})
+	}
+}
+
+func TestRateServiceGetRateMissing(t *testing.T) {
+	repo := &memoryRateRepo{rates: []*models.CurrencyRate{
+		rateOn("EUR", "USD", 1.0965, 4),
+		rateOn("CHF", "JPY", 170.0, 4),
+	}}
+	service := NewRateService(repo)
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		date time.Time
+	}{
+		{"empty currency", "", "USD", time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC)},
+		{"before first rate", "EUR", "USD", time.Date(2024, 10, 3, 0, 0, 0, 0, time.UTC)},
+		{"no pivot path", "CHF", "USD", time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.GetRate(tt.from, tt.to, tt.date)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRateServiceImportAndConvert(t *testing.T) {
+	repo := &memoryRateRepo{}
+	service := NewRateService(repo)
+
+	count, err := service.ImportRates("ECB", strings.NewReader(ecbHist))
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	count, err = service.ImportRates("nbk", strings.NewReader(nbkDaily))
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	date := time.Date(2024, 10, 4, 0, 0, 0, 0, time.UTC)
+	// GBP -> KZT: через EUR (курсы ЕЦБ и Нацбанка)
+	converted, quote, err := service.Convert(models.NewMoney(10000, "GBP"), "GBP", "KZT", date)
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", quote.Pivot)
+	assert.Equal(t, "KZT", converted.Currency)
+	assert.Equal(t, int64(6319090), converted.Minor)
+
+	// JPY -> KZT: прямой курс Нацбанка за 1 единицу
+	converted, quote, err = service.Convert(models.NewMoney(100000, "JPY"), "JPY", "KZT", date)
+	require.NoError(t, err)
+	assert.Empty(t, quote.Pivot)
+	assert.Equal(t, int64(327500), converted.Minor)
+
+	_, err = service.ImportRates("cbr", strings.NewReader(""))
+	assert.Error(t, err)
+	_, err = service.ImportRates("ecb", strings.NewReader(`<Envelope><Cube/></Envelope>`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"fmt"
+	"justTest/internal/interfaces"
+	"justTest/internal/models"
+	"justTest/internal/models/events"
+	"justTest/internal/utils"
+	"log"
+	"time"
+)
+
+// RecurringService - правила повторяющихся транзакций и их материализация планировщиком
+// даты правил хранятся как календарные (полночь UTC), "сегодня" считается в таймзоне аккаунта
+type RecurringService struct {
+	recurringRepo      interfaces.RecurringTransactionRepository
+	bankAccountRepo    interfaces.BankAccountRepository
+	categoryRepo       interfaces.CategoryRepository
+	accountRepo        interfaces.AccountRepository
+	transactionService *TransactionService
+	publisher          interface{}
+}
+
+func NewRecurringService(
+	recurringRepo interfaces.RecurringTransactionRepository,
+	bankAccountRepo interfaces.BankAccountRepository,
+	categoryRepo interfaces.CategoryRepository,
+	accountRepo interfaces.AccountRepository,
+	transactionService *TransactionService,
+	publisher interface{},
+) *RecurringService {
+	return &RecurringService{
+		recurringRepo:      recurringRepo,
+		bankAccountRepo:    bankAccountRepo,
+		categoryRepo:       categoryRepo,
+		accountRepo:        accountRepo,
+		transactionService: transactionService,
+		publisher:          publisher,
+	}
+}
+
+func (s *RecurringService) CreateRecurring(userID string, req *models.RecurringTransactionRequest) (*models.RecurringTransaction, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	recurring := &models.RecurringTransaction{
+		AccountID: account.ID,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	if err := s.applyRequest(account, recurring, req); err != nil {
+		return nil, err
+	}
+	recurring.NextRunDate = firstOccurrence(recurring)
+
+	created, err := s.recurringRepo.Create(recurring)
+	if err != nil {
+		return nil, fmt.Errorf("create recurring transaction: %w", err)
+	}
+	return created, nil
+}
+
+func (s *RecurringService) UpdateRecurring(userID string, recurringID int64, req *models.RecurringTransactionRequest) (*models.RecurringTransaction, error) {
+	recurring, account, err := s.getOwnedRecurring(userID, recurringID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyRequest(account, recurring, req); err != nil {
+		return nil, err
+	}
+	if req.IsActive != nil {
+		recurring.IsActive = *req.IsActive
+	}
+	// расписание пересчитывается, уже созданные срабатывания не повторяются
+	next := firstOccurrence(recurring)
+	for recurring.LastRunDate != nil && !next.After(*recurring.LastRunDate) {
+		next = nextOccurrence(recurring, next)
+	}
+	recurring.NextRunDate = next
+
+	updated, err := s.recurringRepo.Update(recurring)
+	if err != nil {
+		return nil, fmt.Errorf("update recurring transaction: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *RecurringService) DeleteRecurring(userID string, recurringID int64) error {
+	if _, _, err := s.getOwnedRecurring(userID, recurringID); err != nil {
+		return err
+	}
+	return s.recurringRepo.Delete(recurringID)
+}
+
+func (s *RecurringService) GetRecurring(userID string, recurringID int64) (*models.RecurringTransaction, error) {
+	recurring, _, err := s.getOwnedRecurring(userID, recurringID)
+	if err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+func (s *RecurringService) GetAllRecurring(userID string) ([]*models.RecurringTransaction, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+	return s.recurringRepo.GetByAccountID(account.ID)
+}
+
+// ProcessDue создает транзакции по всем наступившим срабатываниям, включая пропущенные пока сервер не работал
+func (s *RecurringService) ProcessDue(now time.Time) error {
+	// с запасом в сутки: "сегодня" у аккаунтов в восточных таймзонах наступает раньше UTC
+	rules, err := s.recurringRepo.GetDue(now.UTC().AddDate(0, 0, 1))
+	if err != nil {
+		return fmt.Errorf("get due recurring transactions: %w", err)
+	}
+	for _, rule := range rules {
+		if err := s.processRule(rule, now); err != nil {
+			log.Printf("[RecurringService] Error processing recurring transaction %d: %v", rule.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *RecurringService) processRule(rule *models.RecurringTransaction, now time.Time) error {
+	account, err := s.accountRepo.GetByID(rule.AccountID)
+	if err != nil {
+		return fmt.Errorf("get account: %w", err)
+	}
+	local := now.In(utils.LoadLocation(account.Timezone))
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	for !rule.NextRunDate.After(today) {
+		if rule.EndDate != nil && rule.NextRunDate.After(*rule.EndDate) {
+			break
+		}
+		runDate := rule.NextRunDate
+		transaction, err := s.transactionService.buildTransaction(
+			account.UserID,
+			rule.BankAccountID,
+			rule.Amount,
+			rule.Description,
+			rule.CategoryID,
+			rule.TransactionType,
+			runDate.Format("2006-01-02"),
+			false,
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("create transaction: %w", err)
+		}
+		// срабатывание и транзакция сохраняются в одной транзакции БД,
+		// иначе сбой между ними оставит занятую дату без транзакции
+		created, err := s.recurringRepo.CreateRun(rule.ID, runDate, transaction)
+		if err != nil {
+			return err
+		}
+		if created {
+			s.publishTransactionCreated(account.UserID, transaction)
+			log.Printf("[RecurringService] Created transaction %d for recurring %d on %s",
+				transaction.ID, rule.ID, runDate.Format("2006-01-02"))
+		}
+		rule.LastRunDate = &runDate
+		rule.NextRunDate = nextOccurrence(rule, runDate)
+		if err := s.recurringRepo.UpdateSchedule(rule.ID, rule.NextRunDate, rule.LastRunDate, rule.IsActive); err != nil {
+			return err
+		}
+	}
+	if rule.EndDate != nil && rule.NextRunDate.After(*rule.EndDate) {
+		rule.IsActive = false
+		return s.recurringRepo.UpdateSchedule(rule.ID, rule.NextRunDate, rule.LastRunDate, rule.IsActive)
+	}
+	return nil
+}
+
+func (s *RecurringService) publishTransactionCreated(userID string, transaction *models.Transaction) {
+	if transaction.CategoryID == nil || transaction.TransactionType != "expense" || s.publisher == nil {
+		return
+	}
+	if publisher, ok := s.publisher.(interface {
+		PublishTransactionCreated(events.TransactionCreatedEvent) error
+	}); ok {
+		err := publisher.PublishTransactionCreated(events.TransactionCreatedEvent{
+			TransactionID: transaction.ID,
+			UserID:        userID,
+			CategoryID:    *transaction.CategoryID,
+			Amount:        transaction.Amount,
+			Description:   transaction.Description,
+			Date:          transaction.Date,
+			Timestamp:     time.Now(),
+		})
+		if err != nil {
+			log.Printf("[RecurringService] Error publishing TransactionCreated event: %v", err)
+		}
+	}
+}
+
+func (s *RecurringService) getOwnedRecurring(userID string, recurringID int64) (*models.RecurringTransaction, *models.Account, error) {
+	if userID == "" {
+		return nil, nil, fmt.Errorf("invalid user id")
+	}
+	if recurringID <= 0 {
+		return nil, nil, fmt.Errorf("invalid recurring transaction id")
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get account: %w", err)
+	}
+	recurring, err := s.recurringRepo.GetByID(recurringID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if recurring.AccountID != account.ID {
+		return nil, nil, fmt.Errorf("recurring transaction does not belong to user")
+	}
+	return recurring, account, nil
+}
+
+// applyRequest проверяет запрос и переносит его поля в правило
+func (s *RecurringService) applyRequest(account *models.Account, recurring *models.RecurringTransaction, req *models.RecurringTransactionRequest) error {
+	if req == nil {
+		return fmt.Errorf("invalid request")
+	}
+	if req.Amount.IsZero() {
+		return fmt.Errorf("invalid amount")
+	}
+	bankAccount, err := s.bankAccountRepo.GetByBankAccountID(req.BankAccountID)
+	if err != nil {
+		return fmt.Errorf("bank account not found: %w", err)
+	}
+	if bankAccount.AccountID != account.ID {
+		return fmt.Errorf("bank account does not belong to user")
+	}
+	if req.CategoryID != nil {
+		category, err := s.categoryRepo.GetByID(*req.CategoryID)
+		if err != nil {
+			return fmt.Errorf("category not found: %w", err)
+		}
+		if category.AccountID != account.ID {
+			return fmt.Errorf("category does not belong to user")
+		}
+	}
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid start date: %w", err)
+	}
+	var endDate *time.Time
+	if req.EndDate != nil {
+		parsed, err := time.Parse("2006-01-02", *req.EndDate)
+		if err != nil {
+			return fmt.Errorf("invalid end date: %w", err)
+		}
+		if parsed.Before(startDate) {
+			return fmt.Errorf("end date is before start date")
+		}
+		endDate = &parsed
+	}
+	if req.DayOfMonth != nil && req.Frequency != "monthly" {
+		return fmt.Errorf("day of month is only allowed for monthly frequency")
+	}
+	interval := req.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+	amount := req.Amount.Abs()
+	if req.TransactionType == "expense" {
+		amount = amount.Neg()
+	}
+
+	recurring.BankAccountID = req.BankAccountID
+	recurring.CategoryID = req.CategoryID
+	recurring.Amount = amount
+	recurring.Description = req.Description
+	recurring.TransactionType = req.TransactionType
+	recurring.Frequency = req.Frequency
+	recurring.Interval = interval
+	recurring.DayOfMonth = req.DayOfMonth
+	recurring.StartDate = startDate
+	recurring.EndDate = endDate
+	recurring.UpdatedAt = time.Now()
+	return nil
+}
+
+// firstOccurrence - первое срабатывание не раньше start_date
+func firstOccurrence(rule *models.RecurringTransaction) time.Time {
+	if rule.Frequency == "monthly" && rule.DayOfMonth != nil {
+		first := clampDay(rule.StartDate.Year(), rule.StartDate.Month(), *rule.DayOfMonth)
+		if first.Before(rule.StartDate) {
+			first = nextOccurrence(rule, first)
+		}
+		return first
+	}
+	return rule.StartDate
+}
+
+// nextOccurrence - срабатывание, следующее за current
+func nextOccurrence(rule *models.RecurringTransaction, current time.Time) time.Time {
+	interval := rule.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+	switch rule.Frequency {
+	case "daily":
+		return current.AddDate(0, 0, interval)
+	case "weekly":
+		return current.AddDate(0, 0, 7*interval)
+	case "monthly":
+		day := rule.StartDate.Day()
+		if rule.DayOfMonth != nil {
+			day = *rule.DayOfMonth
+		}
+		return clampDay(current.Year(), current.Month()+time.Month(interval), day)
+	case "yearly":
+		return clampDay(current.Year()+interval, rule.StartDate.Month(), rule.StartDate.Day())
+	}
+	return current.AddDate(0, 0, interval)
+}
+
+// clampDay - дата с днем day, в коротких месяцах - последний день месяца
+func clampDay(year int, month time.Month, day int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
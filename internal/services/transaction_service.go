@@ -1,10 +1,13 @@
 package services
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"justTest/internal/interfaces"
 	"justTest/internal/models"
 	"justTest/internal/utils"
+	"math"
 	"time"
 )
 
@@ -13,6 +16,8 @@ type TransactionService struct {
 	bankAccountRepo interfaces.BankAccountRepository
 	categoryRepo    interfaces.CategoryRepository
 	accountRepo     interfaces.AccountRepository
+	balanceRepo     interfaces.BalanceRepository
+	rateService     *RateService
 }
 
 func NewTransactionService(
@@ -20,50 +25,142 @@ func NewTransactionService(
 	bankAccountRepo interfaces.BankAccountRepository,
 	categoryRepo interfaces.CategoryRepository,
 	accountRepo interfaces.AccountRepository,
+	balanceRepo interfaces.BalanceRepository,
+	rateService *RateService,
 ) *TransactionService {
 	return &TransactionService{
 		transactionRepo: transactionRepo,
 		bankAccountRepo: bankAccountRepo,
 		categoryRepo:    categoryRepo,
 		accountRepo:     accountRepo,
+		balanceRepo:     balanceRepo,
+		rateService:     rateService,
 	}
 }
 
 func (s *TransactionService) validateCategoryOwnership(userID string, categoryID int64) error {
 	category, err := s.categoryRepo.GetByID(categoryID)
 	if err != nil {
-		return fmt.Errorf("category not found: %w", err)
+		return invalidInput("category not found: %w", err)
 	}
 	userAccount, err := s.accountRepo.GetByUserID(userID)
 	if err != nil {
 		return fmt.Errorf("user not found: %w", err)
 	}
 	if category.AccountID != userAccount.ID {
-		return fmt.Errorf("user is not owned by the category: %w", err)
+		return forbidden("category does not belong to user")
 	}
 	return nil
 }
+
+// buildSplits проверяет строки разбивки: категории пользователя, знак как у amount, сумма = amount
+func (s *TransactionService) buildSplits(userID string, amount models.Money, splits []models.TransactionSplitRequest) ([]*models.TransactionSplit, error) {
+	result := make([]*models.TransactionSplit, 0, len(splits))
+	for _, split := range splits {
+		if split.Amount.IsZero() {
+			return nil, invalidInput("invalid split amount")
+		}
+		if err := s.validateCategoryOwnership(userID, split.CategoryID); err != nil {
+			return nil, fmt.Errorf("split category: %w", err)
+		}
+		splitAmount := split.Amount.Abs()
+		if amount.IsNegative() {
+			splitAmount = splitAmount.Neg()
+		}
+		result = append(result, &models.TransactionSplit{
+			CategoryID:  split.CategoryID,
+			Amount:      splitAmount,
+			Description: split.Description,
+		})
+	}
+	if len(result) > 0 && splitsTotal(result).Cmp(amount) != 0 {
+		return nil, invalidInput("splits total %s does not match amount %s", splitsTotal(result), amount)
+	}
+	return result, nil
+}
+
+func splitsTotal(splits []*models.TransactionSplit) models.Money {
+	var total models.Money
+	for _, split := range splits {
+		total = total.Add(split.Amount)
+	}
+	return total
+}
+
+// parseTransactionDate разбирает дату в таймзоне аккаунта, будущие даты только для запланированных
+func (s *TransactionService) parseTransactionDate(userID string, value string, isPlanned bool) (time.Time, error) {
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("user not found: %w", err)
+	}
+	date, err := utils.ParseLocalDate(value, utils.LoadLocation(account.Timezone))
+	if err != nil {
+		return time.Time{}, invalidInput("invalid date: %w", err)
+	}
+	if !isPlanned && date.After(time.Now()) {
+		return time.Time{}, invalidInput("date is in the future, mark the transaction as planned")
+	}
+	return date, nil
+}
+
 func (s *TransactionService) validateBankAccountOwnership(userID string, bankAccountID int64) error {
+	_, err := s.getOwnedBankAccount(userID, bankAccountID)
+	return err
+}
+
+func (s *TransactionService) getOwnedBankAccount(userID string, bankAccountID int64) (*models.BankAccount, error) {
 	bankAccount, err := s.bankAccountRepo.GetByBankAccountID(bankAccountID)
 	if err != nil {
-		return fmt.Errorf("bank account not found: %w", err)
+		return nil, fmt.Errorf("bank account not found: %w", err)
 	}
 	userAccount, err := s.accountRepo.GetByUserID(userID)
 	if err != nil {
-		return fmt.Errorf("user not found: %w", err)
+		return nil, fmt.Errorf("user not found: %w", err)
 	}
 	if bankAccount.AccountID != userAccount.ID {
-		return fmt.Errorf("user is not owned by the bank account: %w", err)
+		return nil, forbidden("bank account does not belong to user")
 	}
-	return nil
+	return bankAccount, nil
 }
 
-func (s *TransactionService) CreateTransaction(userID string, bankAccountID int64, amount float64, description string, categoryID *int64, transactionType string) (*models.Transaction, error) {
+// getOwnedTransaction - транзакция пользователя, ErrNotFound если ее нет
+func (s *TransactionService) getOwnedTransaction(userID string, transactionID int64) (*models.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByTransactionID(transactionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFound("transaction %d not found", transactionID)
+		}
+		return nil, err
+	}
+	if err := s.validateBankAccountOwnership(userID, transaction.BankAccountID); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// CreateTransaction создает доход или расход
+// date - дата в таймзоне аккаунта (см. utils.ParseLocalDate), пустая строка - текущий момент
+// дата в будущем допускается только для запланированных транзакций
+// splits - необязательная разбивка по категориям, сумма строк должна совпасть с amount
+func (s *TransactionService) CreateTransaction(userID string, bankAccountID int64, amount models.Money, description string, categoryID *int64, transactionType string, date string, isPlanned bool, splits []models.TransactionSplitRequest) (*models.Transaction, error) {
+	transaction, err := s.buildTransaction(userID, bankAccountID, amount, description, categoryID, transactionType, date, isPlanned, splits)
+	if err != nil {
+		return nil, err
+	}
+	createdTransaction, err := s.transactionRepo.Create(transaction)
+	if err != nil {
+		return nil, err
+	}
+	return createdTransaction, nil
+
+}
 
+// buildTransaction проверяет параметры CreateTransaction и собирает транзакцию без сохранения
+func (s *TransactionService) buildTransaction(userID string, bankAccountID int64, amount models.Money, description string, categoryID *int64, transactionType string, date string, isPlanned bool, splits []models.TransactionSplitRequest) (*models.Transaction, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("invalid user id")
 	}
-	if amount == 0 {
+	if amount.IsZero() {
 		return nil, fmt.Errorf("invalid amount")
 	}
 	if transactionType == "" {
@@ -85,11 +182,27 @@ func (s *TransactionService) CreateTransaction(userID string, bankAccountID int6
 			return nil, err
 		}
 	}
-	if transactionType == "expense" && amount > 0 {
-		amount = -amount
+	if transactionType == "expense" && amount.IsPositive() {
+		amount = amount.Neg()
+	}
+	if transactionType == "income" && amount.IsNegative() {
+		amount = amount.Neg()
 	}
-	if transactionType == "income" && amount < 0 {
-		amount = -amount
+	transactionSplits, err := s.buildSplits(userID, amount, splits)
+	if err != nil {
+		return nil, err
+	}
+	if len(transactionSplits) > 0 {
+		// категории берутся из строк разбивки
+		categoryID = nil
+	}
+	now := time.Now()
+	transactionDate := now
+	if date != "" {
+		transactionDate, err = s.parseTransactionDate(userID, date, isPlanned)
+		if err != nil {
+			return nil, err
+		}
 	}
 	transaction := &models.Transaction{
 		BankAccountID:   bankAccountID,
@@ -97,54 +210,124 @@ func (s *TransactionService) CreateTransaction(userID string, bankAccountID int6
 		Amount:          amount,
 		Description:     description,
 		TransactionType: transactionType,
-		Date:            time.Now(),
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
+		Date:            transactionDate,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		IsPlanned:       isPlanned,
+		Splits:          transactionSplits,
 	}
-	createdTransaction, err := s.transactionRepo.Create(transaction)
-	if err != nil {
-		return nil, err
-	}
-	return createdTransaction, nil
-
+	return transaction, nil
 }
 
 // TransferBetweenAccounts
 // GetTransactionHistory
 // GetBankAccountBalance
 // GetTransaction
-func (s *TransactionService) TransferBetweenAccounts(userID string, fromAccountID int64, toAccountID int64, description string, amount float64) error {
+
+// TransferBetweenAccounts переводит amount (в валюте источника) на другой счет
+// между разными валютами сумма зачисления считается по rate или берется из receivedAmount,
+// если не указано ни то ни другое - по курсу на сегодня из справочника курсов (RateService)
+func (s *TransactionService) TransferBetweenAccounts(userID string, fromAccountID int64, toAccountID int64, description string, amount models.Money, rate *float64, receivedAmount *models.Money) (*models.TransferResult, error) {
 	if userID == "" {
-		return fmt.Errorf("invalid user id")
+		return nil, fmt.Errorf("invalid user id")
 	}
 	if fromAccountID <= 0 {
-		return fmt.Errorf("invalid from account id")
+		return nil, fmt.Errorf("invalid from account id")
 	}
 	if toAccountID <= 0 {
-		return fmt.Errorf("invalid to account id")
+		return nil, fmt.Errorf("invalid to account id")
 	}
-	if amount == 0 {
-		return fmt.Errorf("invalid amount")
+	if fromAccountID == toAccountID {
+		return nil, fmt.Errorf("cannot transfer to the same account")
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("invalid amount")
 	}
 	if description == "" {
-		return fmt.Errorf("invalid description")
+		return nil, fmt.Errorf("invalid description")
 	}
-	err := s.validateBankAccountOwnership(userID, fromAccountID)
+	if rate != nil && receivedAmount != nil {
+		return nil, fmt.Errorf("specify either rate or received amount, not both")
+	}
+	if rate != nil && *rate <= 0 {
+		return nil, fmt.Errorf("invalid rate")
+	}
+	if receivedAmount != nil && !receivedAmount.IsPositive() {
+		return nil, fmt.Errorf("invalid received amount")
+	}
+	fromAccount, err := s.getOwnedBankAccount(userID, fromAccountID)
 	if err != nil {
-		return fmt.Errorf("source account: %w", err)
+		return nil, fmt.Errorf("source account: %w", err)
 	}
-
-	err = s.validateBankAccountOwnership(userID, toAccountID)
+	toAccount, err := s.getOwnedBankAccount(userID, toAccountID)
 	if err != nil {
-		return fmt.Errorf("destination account: %w", err)
+		return nil, fmt.Errorf("destination account: %w", err)
 	}
 
-	err = s.transactionRepo.CreateTransaction(nil, &fromAccountID, nil, toAccountID, amount, description, nil)
+	result := &models.TransferResult{
+		FromAccountID: fromAccountID,
+		ToAccountID:   toAccountID,
+		Amount:        amount.WithCurrency(fromAccount.Currency),
+		FromCurrency:  fromAccount.Currency,
+		ToCurrency:    toAccount.Currency,
+	}
+	if result.Amount.IsZero() {
+		return nil, fmt.Errorf("amount is below %s precision", fromAccount.Currency)
+	}
+	var transferRate *float64
+	if fromAccount.Currency == toAccount.Currency {
+		if rate != nil && *rate != 1 {
+			return nil, fmt.Errorf("rate must be 1 for a transfer in the same currency")
+		}
+		if receivedAmount != nil && receivedAmount.Cmp(result.Amount) != 0 {
+			return nil, fmt.Errorf("received amount must equal amount for a transfer in the same currency")
+		}
+		result.ReceivedAmount = result.Amount
+		result.Rate = 1
+		result.RateSource = "same_currency"
+	} else {
+		switch {
+		case receivedAmount != nil:
+			result.ReceivedAmount = receivedAmount.WithCurrency(toAccount.Currency)
+			result.Rate = transferRateOf(result.Amount, result.ReceivedAmount)
+			result.RateSource = "received_amount"
+		case rate != nil:
+			result.Rate = *rate
+			result.RateSource = "request"
+		default:
+			quote, err := s.rateService.GetRate(fromAccount.Currency, toAccount.Currency, time.Now())
+			if err != nil {
+				return nil, fmt.Errorf("%w, specify rate or received amount", err)
+			}
+			result.Rate = roundRate(quote.Rate)
+			result.RateSource = "stored"
+		}
+		if result.RateSource != "received_amount" {
+			result.ReceivedAmount, err = result.Amount.Convert(result.Rate, toAccount.Currency)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !result.ReceivedAmount.IsPositive() {
+			return nil, fmt.Errorf("received amount is below %s precision", toAccount.Currency)
+		}
+		transferRate = &result.Rate
+	}
+
+	err = s.transactionRepo.CreateTransaction(nil, &fromAccountID, nil, toAccountID, result.Amount, result.ReceivedAmount, description, transferRate)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return result, nil
+}
 
+// transferRateOf - фактический курс received / amount с точностью transfer_rate (6 знаков)
+func transferRateOf(amount, received models.Money) float64 {
+	return roundRate(float64(received.Minor) / float64(amount.Minor))
+}
+
+func roundRate(rate float64) float64 {
+	return math.Round(rate*1e6) / 1e6
 }
 func (s *TransactionService) GetTransactionHistory(userID string, bankAccountID int64) ([]*models.Transaction, error) {
 	if userID == "" {
@@ -182,24 +365,86 @@ func (s *TransactionService) GetAllTransactions(userID string) ([]*models.Transa
 	}
 	return transactions, nil
 }
-func (s *TransactionService) GetBankAccountBalance(userID string, bankAccountID int64) (float64, error) {
+func (s *TransactionService) GetBankAccountBalance(userID string, bankAccountID int64) (models.Money, error) {
 	if userID == "" {
-		return 0, fmt.Errorf("invalid user id")
+		return models.Money{}, fmt.Errorf("invalid user id")
 	}
 	if bankAccountID <= 0 {
-		return 0, fmt.Errorf("invalid bank account id")
+		return models.Money{}, fmt.Errorf("invalid bank account id")
 	}
 	err := s.validateBankAccountOwnership(userID, bankAccountID)
 	if err != nil {
-		return 0, err
+		return models.Money{}, err
 	}
 	balance, err := s.transactionRepo.GetTotalAmountByBankAccountID(bankAccountID)
 	if err != nil {
-		return 0, err
+		return models.Money{}, err
 
 	}
 	return balance, nil
 }
+
+// maxBalanceHistoryDays - самый длинный период истории остатков
+const maxBalanceHistoryDays = 366
+
+// GetBalanceHistory - остаток счета на конец каждого дня [from, to], дни - в таймзоне аккаунта
+// нулевой to - сегодня, нулевой from - 30 дней по to включительно
+func (s *TransactionService) GetBalanceHistory(userID string, bankAccountID int64, from, to time.Time) (*models.BalanceHistory, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if bankAccountID <= 0 {
+		return nil, fmt.Errorf("invalid bank account id")
+	}
+	bankAccount, err := s.getOwnedBankAccount(userID, bankAccountID)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := utils.LoadLocation(account.Timezone)
+	if to.IsZero() {
+		to = time.Now().In(loc)
+	}
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc)
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -29)
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	if to.Before(from) {
+		return nil, fmt.Errorf("to is before from")
+	}
+	if from.AddDate(0, 0, maxBalanceHistoryDays).Before(to) {
+		return nil, fmt.Errorf("period is longer than %d days", maxBalanceHistoryDays)
+	}
+
+	snapshots, err := s.balanceRepo.GetDailyBalances(bankAccountID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	history := &models.BalanceHistory{
+		BankAccountID: bankAccountID,
+		Currency:      bankAccount.Currency,
+		From:          from,
+		To:            to,
+		Days:          make([]*models.DailyBalance, 0),
+	}
+	// в таблице только дни с транзакциями, остальные дни - остаток последнего такого дня
+	balance := models.NewMoney(0, bankAccount.Currency)
+	next := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		for next < len(snapshots) && snapshots[next].Date.Format("2006-01-02") <= date {
+			balance = snapshots[next].Balance.WithCurrency(bankAccount.Currency)
+			next++
+		}
+		history.Days = append(history.Days, &models.DailyBalance{Date: day, Balance: balance})
+	}
+	return history, nil
+}
+
 func (s *TransactionService) GetTransactionByID(userID string, transactionID int64) (*models.Transaction, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("invalid user id")
@@ -235,3 +480,122 @@ func (s *TransactionService) GetAllTransactionsByCategoryID(userID string, categ
 	}
 	return transactions, nil
 }
+
+// UpdateTransaction меняет сумму, категорию, описание, тип и дату транзакции,
+// is_planned = false проводит запланированную транзакцию: она начинает влиять на баланс и бюджеты
+// возвращает обновленную транзакцию и ее предыдущее состояние (нужно для перепроверки бюджетов)
+func (s *TransactionService) UpdateTransaction(userID string, transactionID int64, req *models.UpdateTransactionRequest) (*models.Transaction, *models.Transaction, error) {
+	if userID == "" {
+		return nil, nil, invalidInput("invalid user id")
+	}
+	if transactionID <= 0 {
+		return nil, nil, invalidInput("invalid transaction id")
+	}
+	if req == nil {
+		return nil, nil, invalidInput("invalid request")
+	}
+	if req.Amount.IsZero() {
+		return nil, nil, invalidInput("invalid amount")
+	}
+	if req.Description == "" {
+		return nil, nil, invalidInput("invalid description")
+	}
+	if req.TransactionType != "income" && req.TransactionType != "expense" {
+		return nil, nil, invalidInput("invalid transaction type")
+	}
+	transaction, err := s.getOwnedTransaction(userID, transactionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if transaction.TransactionType == "transfer" {
+		return nil, nil, invalidInput("transfers cannot be edited")
+	}
+	if transaction.TransactionType == "opening_balance" {
+		return nil, nil, invalidInput("opening balance cannot be edited, delete it instead")
+	}
+	if transaction.TransactionType == "debt" {
+		return nil, nil, invalidInput("debt transactions are changed through their debt")
+	}
+	if transaction.InstallmentPlanID != nil {
+		return nil, nil, invalidInput("installment payments are managed via /installments")
+	}
+	if req.CategoryID != nil {
+		err := s.validateCategoryOwnership(userID, *req.CategoryID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	previous := *transaction
+
+	amount := req.Amount
+	if req.TransactionType == "expense" && amount.IsPositive() {
+		amount = amount.Neg()
+	}
+	if req.TransactionType == "income" && amount.IsNegative() {
+		amount = amount.Neg()
+	}
+	if req.IsPlanned != nil {
+		transaction.IsPlanned = *req.IsPlanned
+	}
+	if req.Date != nil {
+		date, err := s.parseTransactionDate(userID, *req.Date, transaction.IsPlanned)
+		if err != nil {
+			return nil, nil, err
+		}
+		transaction.Date = date
+	} else if !transaction.IsPlanned && transaction.Date.After(time.Now()) {
+		return nil, nil, invalidInput("date is in the future, mark the transaction as planned")
+	}
+	if req.Splits != nil {
+		transaction.Splits, err = s.buildSplits(userID, amount, req.Splits)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if len(transaction.Splits) > 0 && splitsTotal(transaction.Splits).Cmp(amount) != 0 {
+		return nil, nil, invalidInput("splits total does not match amount, send updated splits")
+	} else if len(transaction.Splits) > 0 && req.TransactionType != previous.TransactionType {
+		return nil, nil, invalidInput("transaction type changed, send updated splits")
+	}
+	transaction.Amount = amount
+	transaction.CategoryID = req.CategoryID
+	if len(transaction.Splits) > 0 {
+		transaction.CategoryID = nil
+	}
+	transaction.Description = req.Description
+	transaction.TransactionType = req.TransactionType
+	transaction.UpdatedAt = time.Now()
+
+	updatedTransaction, err := s.transactionRepo.Update(transaction)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updatedTransaction, &previous, nil
+}
+
+// DeleteTransaction удаляет транзакцию и возвращает удаленную
+func (s *TransactionService) DeleteTransaction(userID string, transactionID int64) (*models.Transaction, error) {
+	if userID == "" {
+		return nil, invalidInput("invalid user id")
+	}
+	if transactionID <= 0 {
+		return nil, invalidInput("invalid transaction id")
+	}
+	transaction, err := s.getOwnedTransaction(userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.TransactionType == "transfer" {
+		return nil, invalidInput("transfers cannot be deleted")
+	}
+	if transaction.TransactionType == "debt" {
+		return nil, invalidInput("debt transactions are managed via /debts")
+	}
+	if transaction.InstallmentPlanID != nil {
+		return nil, invalidInput("installment payments are managed via /installments")
+	}
+	err = s.transactionRepo.Delete(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -48,3 +49,8 @@ func GetUserID(c *gin.Context) (string, bool) {
 
 	return userIDStr, true
 }
+
+// NormalizeIBAN убирает пробелы и приводит IBAN к верхнему регистру
+func NormalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+}
@@ -0,0 +1,61 @@
+package utils
+
+import "time"
+
+// DefaultTimezone - таймзона по умолчанию, совпадает с default в таблице accounts
+const DefaultTimezone = "Asia/Almaty"
+
+// LoadLocation возвращает таймзону аккаунта, при пустой или неизвестной - DefaultTimezone
+func LoadLocation(timezone string) *time.Location {
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc, err = time.LoadLocation(DefaultTimezone)
+		if err != nil {
+			return time.UTC
+		}
+	}
+	return loc
+}
+
+// MonthRange - границы месяца [start, end) в указанной таймзоне
+func MonthRange(year, month int, loc *time.Location) (time.Time, time.Time) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 1, 0)
+	return start, end
+}
+
+// WeekRange - границы недели [start, end), в которую попадает календарная дата date, в указанной таймзоне
+// weekStart - первый день недели (time.Monday, time.Sunday ...)
+func WeekRange(date time.Time, weekStart time.Weekday, loc *time.Location) (time.Time, time.Time) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	start := day.AddDate(0, 0, -offset)
+	return start, start.AddDate(0, 0, 7)
+}
+
+// YearRange - границы года [start, end) в указанной таймзоне
+func YearRange(year int, loc *time.Location) (time.Time, time.Time) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	return start, start.AddDate(1, 0, 0)
+}
+
+// DateRange - границы периода [start, end) по календарным датам в указанной таймзоне, endDate включительно
+func DateRange(startDate, endDate time.Time, loc *time.Location) (time.Time, time.Time) {
+	start := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	return start, end
+}
+
+// ParseLocalDate разбирает дату из запроса: "2006-01-02", "2006-01-02T15:04:05" (время аккаунта) или RFC3339
+func ParseLocalDate(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04:05", value, loc); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, loc)
+}
@@ -0,0 +1,4 @@
+-- запланированные транзакции (дата в будущем) не влияют на баланс, бюджеты и аналитику
+ALTER TABLE transactions ADD COLUMN is_planned BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX idx_transactions_is_planned ON transactions(is_planned);
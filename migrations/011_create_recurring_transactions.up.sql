@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS recurring_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    bank_account_id BIGINT NOT NULL REFERENCES bank_accounts(id) ON DELETE CASCADE,
+    category_id BIGINT REFERENCES categories(id) ON DELETE SET NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    description TEXT NOT NULL,
+    transaction_type VARCHAR(20) NOT NULL CHECK (transaction_type IN ('income', 'expense')),
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly')),
+    interval_count INTEGER NOT NULL DEFAULT 1 CHECK (interval_count > 0), -- каждые N дней/недель/месяцев/лет
+    day_of_month INTEGER CHECK (day_of_month BETWEEN 1 AND 31),          -- для monthly, в коротких месяцах - последний день
+    start_date DATE NOT NULL,
+    end_date DATE,
+    next_run_date DATE NOT NULL,
+    last_run_date DATE,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_recurring_transactions_account_id ON recurring_transactions(account_id);
+CREATE INDEX idx_recurring_transactions_next_run ON recurring_transactions(next_run_date) WHERE is_active = true;
+
+-- каждое срабатывание правила записывается один раз, это защищает от дублей при догоняющем запуске
+CREATE TABLE IF NOT EXISTS recurring_transaction_runs (
+    id BIGSERIAL PRIMARY KEY,
+    recurring_transaction_id BIGINT NOT NULL REFERENCES recurring_transactions(id) ON DELETE CASCADE,
+    run_date DATE NOT NULL,
+    transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_recurring_run UNIQUE (recurring_transaction_id, run_date)
+);
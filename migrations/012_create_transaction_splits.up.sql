@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS transaction_splits (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    category_id BIGINT NOT NULL,
+    amount DECIMAL(15,2) NOT NULL, -- знак как у родительской транзакции, сумма строк = amount транзакции
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_transaction_splits_transaction_id ON transaction_splits(transaction_id);
+CREATE INDEX idx_transaction_splits_category_id ON transaction_splits(category_id);
+
+-- строки транзакций по категориям: строки разбивки вместо родителя, если разбивка есть
+CREATE VIEW transaction_lines AS
+SELECT t.id AS transaction_id, t.bank_account_id, t.category_id, t.amount,
+       t.transaction_type, t.date, t.is_planned
+FROM transactions t
+WHERE NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id)
+UNION ALL
+SELECT t.id AS transaction_id, t.bank_account_id, s.category_id, s.amount,
+       t.transaction_type, t.date, t.is_planned
+FROM transaction_splits s
+    JOIN transactions t ON t.id = s.transaction_id;
@@ -0,0 +1,7 @@
+-- id транзакции из выписки банка
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS external_id VARCHAR(255);
+
+-- FITID из OFX уникален в пределах счета, повторный импорт той же выписки не создает дублей
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_bank_account_external_id
+    ON transactions(bank_account_id, external_id)
+    WHERE external_id IS NOT NULL;
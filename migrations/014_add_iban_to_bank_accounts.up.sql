@@ -0,0 +1,4 @@
+-- IBAN счета для сопоставления выписок ISO 20022 (camt.053 / camt.052)
+ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS iban VARCHAR(34) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_bank_accounts_iban ON bank_accounts(iban) WHERE iban <> '';
@@ -0,0 +1,10 @@
+-- курсы валют: сколько единиц to_currency дают за одну единицу from_currency
+CREATE TABLE IF NOT EXISTS currency_rates (
+    id BIGSERIAL PRIMARY KEY,
+    from_currency VARCHAR(3) NOT NULL,
+    to_currency VARCHAR(3) NOT NULL,
+    rate DECIMAL(20,10) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_currency_rate UNIQUE (from_currency, to_currency)
+);
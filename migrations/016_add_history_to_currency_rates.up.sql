@@ -0,0 +1,8 @@
+-- история курсов: один курс на пару валют за день, source - откуда загружен (ecb, nbk, manual)
+ALTER TABLE currency_rates ADD COLUMN IF NOT EXISTS rate_date DATE NOT NULL DEFAULT CURRENT_DATE;
+ALTER TABLE currency_rates ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'manual';
+
+ALTER TABLE currency_rates DROP CONSTRAINT IF EXISTS unique_currency_rate;
+ALTER TABLE currency_rates ADD CONSTRAINT unique_currency_rate_per_day UNIQUE (from_currency, to_currency, rate_date);
+
+CREATE INDEX IF NOT EXISTS idx_currency_rates_lookup ON currency_rates(from_currency, to_currency, rate_date DESC);
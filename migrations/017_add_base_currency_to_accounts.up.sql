@@ -0,0 +1,2 @@
+-- базовая валюта аккаунта: в нее сводятся чистые активы, отчеты и бюджеты
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS base_currency VARCHAR(3) NOT NULL DEFAULT 'KZT';
@@ -0,0 +1,38 @@
+-- текущий остаток счета, обновляется в той же транзакции БД, что и транзакции счета (без запланированных)
+CREATE TABLE IF NOT EXISTS bank_account_balances (
+    bank_account_id BIGINT PRIMARY KEY REFERENCES bank_accounts(id) ON DELETE CASCADE,
+    balance DECIMAL(15,2) NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    last_updated TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- остаток на конец дня (день - в таймзоне аккаунта), строка есть только для дней с транзакциями
+CREATE TABLE IF NOT EXISTS bank_account_daily_balances (
+    bank_account_id BIGINT NOT NULL REFERENCES bank_accounts(id) ON DELETE CASCADE,
+    balance_date DATE NOT NULL,
+    balance DECIMAL(15,2) NOT NULL,
+
+    PRIMARY KEY (bank_account_id, balance_date)
+);
+
+-- заполнение по уже существующим транзакциям
+INSERT INTO bank_account_balances (bank_account_id, balance, currency, last_updated)
+SELECT ba.id, COALESCE(SUM(t.amount) FILTER (WHERE t.is_planned = false), 0), ba.currency, NOW()
+FROM bank_accounts ba
+    LEFT JOIN transactions t ON t.bank_account_id = ba.id
+GROUP BY ba.id, ba.currency
+ON CONFLICT (bank_account_id) DO NOTHING;
+
+INSERT INTO bank_account_daily_balances (bank_account_id, balance_date, balance)
+SELECT bank_account_id, balance_date,
+       SUM(SUM(amount)) OVER (PARTITION BY bank_account_id ORDER BY balance_date)
+FROM (
+    SELECT t.bank_account_id, t.amount,
+           (t.date AT TIME ZONE COALESCE(a.timezone, 'Asia/Almaty'))::date AS balance_date
+    FROM transactions t
+        JOIN bank_accounts ba ON ba.id = t.bank_account_id
+        JOIN accounts a ON a.id = ba.account_id
+    WHERE t.is_planned = false
+) lines
+GROUP BY bank_account_id, balance_date
+ON CONFLICT (bank_account_id, balance_date) DO NOTHING;
@@ -0,0 +1,5 @@
+-- opening_balance - начальный остаток счета, заведенного с уже существующими деньгами;
+-- входит в остатки, но не в доходы, расходы и бюджеты
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_transaction_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_transaction_type_check
+    CHECK (transaction_type IN ('income', 'expense', 'transfer', 'opening_balance'));
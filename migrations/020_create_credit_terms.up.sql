@@ -0,0 +1,12 @@
+-- условия кредитных счетов (bank_accounts.account_type = 'credit'): лимит, цикл выписки, ставка
+CREATE TABLE IF NOT EXISTS credit_terms (
+    bank_account_id BIGINT PRIMARY KEY REFERENCES bank_accounts(id) ON DELETE CASCADE,
+    credit_limit DECIMAL(15,2) NOT NULL CHECK (credit_limit > 0),
+    statement_day SMALLINT NOT NULL CHECK (statement_day BETWEEN 1 AND 31),     -- день закрытия выписки
+    payment_due_day SMALLINT NOT NULL CHECK (payment_due_day BETWEEN 1 AND 31), -- день платежа по выписке
+    interest_rate DECIMAL(6,3) NOT NULL DEFAULT 0,                              -- годовая ставка, %
+    min_payment_percent DECIMAL(5,2) NOT NULL DEFAULT 5,                        -- минимальный платеж, % от выписки
+    last_reminder_date DATE,                                                    -- срок платежа, о котором уже напомнили
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1,27 @@
+-- цели накоплений: прогресс по остатку привязанного сберегательного счета или по журналу взносов
+CREATE TABLE IF NOT EXISTS goals (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    bank_account_id BIGINT REFERENCES bank_accounts(id) ON DELETE SET NULL, -- NULL - взносы вручную
+    name VARCHAR(100) NOT NULL,
+    target_amount DECIMAL(15,2) NOT NULL CHECK (target_amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    target_date DATE NOT NULL,
+    last_milestone SMALLINT NOT NULL DEFAULT 0, -- последний отмеченный рубеж: 0, 25, 50, 75, 100
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_goals_account_id ON goals(account_id);
+
+-- взносы в цели без привязанного счета, отрицательная сумма - снятие
+CREATE TABLE IF NOT EXISTS goal_contributions (
+    id BIGSERIAL PRIMARY KEY,
+    goal_id BIGINT NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+    amount DECIMAL(15,2) NOT NULL,
+    date TIMESTAMP WITH TIME ZONE NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_goal_contributions_goal_id ON goal_contributions(goal_id, date);
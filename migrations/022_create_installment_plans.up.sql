@@ -0,0 +1,22 @@
+-- рассрочки: покупка, оплачиваемая равными ежемесячными платежами со счета
+CREATE TABLE IF NOT EXISTS installment_plans (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    bank_account_id BIGINT NOT NULL REFERENCES bank_accounts(id) ON DELETE CASCADE,
+    category_id BIGINT REFERENCES categories(id) ON DELETE SET NULL,
+    description TEXT NOT NULL,
+    total_amount DECIMAL(15,2) NOT NULL CHECK (total_amount > 0), -- стоимость покупки
+    fee_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (fee_amount >= 0), -- комиссия за весь срок
+    payments_count INTEGER NOT NULL CHECK (payments_count > 0),
+    paid_payments INTEGER NOT NULL DEFAULT 0 CHECK (paid_payments >= 0), -- оплачено до заведения, транзакции не создавались
+    first_payment_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_installment_plans_account_id ON installment_plans(account_id);
+
+-- платежи рассрочки - запланированные транзакции, в день платежа планировщик делает их обычными
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS installment_plan_id BIGINT REFERENCES installment_plans(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_installment_due ON transactions(date) WHERE installment_plan_id IS NOT NULL AND is_planned = true;
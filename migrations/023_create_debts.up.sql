@@ -0,0 +1,50 @@
+-- люди, которым давали или у которых брали в долг
+CREATE TABLE IF NOT EXISTS counterparties (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_counterparty_name UNIQUE (account_id, name)
+);
+
+-- lent - дали в долг (вернут нам), borrowed - взяли в долг (возвращаем мы)
+CREATE TABLE IF NOT EXISTS debts (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    counterparty_id BIGINT NOT NULL REFERENCES counterparties(id) ON DELETE RESTRICT,
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('lent', 'borrowed')),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    date TIMESTAMP WITH TIME ZONE NOT NULL,
+    due_date DATE,
+    bank_account_id BIGINT REFERENCES bank_accounts(id) ON DELETE SET NULL,
+    transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL, -- движение денег по счету, если счет указан
+    last_reminder_date DATE,                                             -- срок, о котором уже напомнили
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_debts_account_id ON debts(account_id);
+CREATE INDEX idx_debts_counterparty_id ON debts(counterparty_id);
+
+CREATE TABLE IF NOT EXISTS debt_repayments (
+    id BIGSERIAL PRIMARY KEY,
+    debt_id BIGINT NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    date TIMESTAMP WITH TIME ZONE NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    bank_account_id BIGINT REFERENCES bank_accounts(id) ON DELETE SET NULL,
+    transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_debt_repayments_debt_id ON debt_repayments(debt_id);
+
+-- debt - выдача, получение и возврат долга: входит в остатки, но не в доходы, расходы и бюджеты
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_transaction_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_transaction_type_check
+    CHECK (transaction_type IN ('income', 'expense', 'transfer', 'opening_balance', 'debt'));
@@ -0,0 +1,6 @@
+-- первый день недели для недельных бюджетов: 0 - воскресенье, 1 - понедельник ... 6 - суббота (как time.Weekday)
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS week_start_day SMALLINT NOT NULL DEFAULT 1 CHECK (week_start_day BETWEEN 0 AND 6);
+
+-- бюджеты ищутся по дате внутри периода [start_date, end_date]
+CREATE INDEX IF NOT EXISTS idx_budgets_category_dates ON budgets(category_id, start_date, end_date) WHERE is_active = true;
+CREATE INDEX IF NOT EXISTS idx_budgets_account_dates ON budgets(account_id, start_date, end_date) WHERE is_active = true;
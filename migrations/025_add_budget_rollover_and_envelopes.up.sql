@@ -0,0 +1,19 @@
+-- rollover: остаток (или перерасход) бюджета переносится в следующий период той же категории
+-- mode: limit - лимит amount на период, envelope - конверт, доступно столько, сколько в него распределено
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS rollover BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS mode VARCHAR(20) NOT NULL DEFAULT 'limit' CHECK (mode IN ('limit', 'envelope'));
+
+-- распределение дохода по конвертам в базовой валюте аккаунта: положительная сумма - в конверт, отрицательная - из него
+-- перемещение между конвертами - две строки с ссылкой друг на друга через related_budget_id
+CREATE TABLE IF NOT EXISTS budget_assignments (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    budget_id BIGINT NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    related_budget_id BIGINT REFERENCES budgets(id) ON DELETE SET NULL,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount <> 0),
+    description VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_budget_assignments_budget_id ON budget_assignments(budget_id);
+CREATE INDEX IF NOT EXISTS idx_budget_assignments_account_id ON budget_assignments(account_id);
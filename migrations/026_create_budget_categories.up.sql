@@ -0,0 +1,17 @@
+-- категории бюджета: у бюджета одной категории она же в budgets.category_id, у общего бюджета category_id пустой,
+-- а бюджет с all_categories учитывает траты по всем категориям аккаунта и строк здесь не имеет
+CREATE TABLE IF NOT EXISTS budget_categories (
+    budget_id BIGINT NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    category_id BIGINT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+
+    PRIMARY KEY (budget_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_budget_categories_category_id ON budget_categories(category_id);
+
+INSERT INTO budget_categories (budget_id, category_id)
+SELECT id, category_id FROM budgets WHERE category_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE budgets ALTER COLUMN category_id DROP NOT NULL;
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS all_categories BOOLEAN NOT NULL DEFAULT false;